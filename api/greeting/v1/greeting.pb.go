@@ -0,0 +1,727 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: greeting/v1/greeting.proto
+
+package greetingv1
+
+import (
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Confidence describes how closely the resolved language matches the request.
+type Confidence int32
+
+const (
+	Confidence_CONFIDENCE_UNSPECIFIED Confidence = 0
+	// The requested tag is in the catalog.
+	Confidence_CONFIDENCE_EXACT Confidence = 1
+	// Same language and script, different region.
+	Confidence_CONFIDENCE_HIGH Confidence = 2
+	// Same language, different script.
+	Confidence_CONFIDENCE_LOW Confidence = 3
+	// Nothing matched; a fallback language was used.
+	Confidence_CONFIDENCE_NONE Confidence = 4
+)
+
+// Enum value maps for Confidence.
+var (
+	Confidence_name = map[int32]string{
+		0: "CONFIDENCE_UNSPECIFIED",
+		1: "CONFIDENCE_EXACT",
+		2: "CONFIDENCE_HIGH",
+		3: "CONFIDENCE_LOW",
+		4: "CONFIDENCE_NONE",
+	}
+	Confidence_value = map[string]int32{
+		"CONFIDENCE_UNSPECIFIED": 0,
+		"CONFIDENCE_EXACT":       1,
+		"CONFIDENCE_HIGH":        2,
+		"CONFIDENCE_LOW":         3,
+		"CONFIDENCE_NONE":        4,
+	}
+)
+
+func (x Confidence) Enum() *Confidence {
+	p := new(Confidence)
+	*p = x
+	return p
+}
+
+func (x Confidence) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Confidence) Descriptor() protoreflect.EnumDescriptor {
+	return file_greeting_v1_greeting_proto_enumTypes[0].Descriptor()
+}
+
+func (Confidence) Type() protoreflect.EnumType {
+	return &file_greeting_v1_greeting_proto_enumTypes[0]
+}
+
+func (x Confidence) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Confidence.Descriptor instead.
+func (Confidence) EnumDescriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{0}
+}
+
+// Options configures how a greeting is generated. Mirrors greeting.Options.
+type Options struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// BCP 47 tag or POSIX locale name.
+	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	// Languages to try when language does not match.
+	Fallbacks    []string `protobuf:"bytes,3,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	IncludeEmoji bool     `protobuf:"varint,4,opt,name=include_emoji,json=includeEmoji,proto3" json:"include_emoji,omitempty"`
+	// Greet a group of people; used instead of name when set.
+	Names []string `protobuf:"bytes,5,rep,name=names,proto3" json:"names,omitempty"`
+	// Grammatical gender: female, male or other (the default).
+	Gender        string `protobuf:"bytes,6,opt,name=gender,proto3" json:"gender,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_greeting_v1_greeting_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_greeting_v1_greeting_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Options) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Options) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Options) GetFallbacks() []string {
+	if x != nil {
+		return x.Fallbacks
+	}
+	return nil
+}
+
+func (x *Options) GetIncludeEmoji() bool {
+	if x != nil {
+		return x.IncludeEmoji
+	}
+	return false
+}
+
+func (x *Options) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *Options) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+// Greeting is a generated greeting. Mirrors greeting.Greeting.
+type Greeting struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Message           string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Language          string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	RequestedLanguage string                 `protobuf:"bytes,3,opt,name=requested_language,json=requestedLanguage,proto3" json:"requested_language,omitempty"`
+	Confidence        Confidence             `protobuf:"varint,4,opt,name=confidence,proto3,enum=greeting.v1.Confidence" json:"confidence,omitempty"`
+	Emoji             string                 `protobuf:"bytes,5,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Timestamp         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Time of day the greeting was chosen for: morning, afternoon, evening
+	// or night.
+	Period string `protobuf:"bytes,7,opt,name=period,proto3" json:"period,omitempty"`
+	// Holiday key from the server's calendar, empty on ordinary days or when
+	// the server has no calendar.
+	Holiday       string `protobuf:"bytes,8,opt,name=holiday,proto3" json:"holiday,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Greeting) Reset() {
+	*x = Greeting{}
+	mi := &file_greeting_v1_greeting_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Greeting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Greeting) ProtoMessage() {}
+
+func (x *Greeting) ProtoReflect() protoreflect.Message {
+	mi := &file_greeting_v1_greeting_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Greeting.ProtoReflect.Descriptor instead.
+func (*Greeting) Descriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Greeting) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Greeting) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Greeting) GetRequestedLanguage() string {
+	if x != nil {
+		return x.RequestedLanguage
+	}
+	return ""
+}
+
+func (x *Greeting) GetConfidence() Confidence {
+	if x != nil {
+		return x.Confidence
+	}
+	return Confidence_CONFIDENCE_UNSPECIFIED
+}
+
+func (x *Greeting) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *Greeting) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Greeting) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *Greeting) GetHoliday() string {
+	if x != nil {
+		return x.Holiday
+	}
+	return ""
+}
+
+type GenerateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *Options               `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_greeting_v1_greeting_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_greeting_v1_greeting_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type GenerateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Greeting      *Greeting              `protobuf:"bytes,1,opt,name=greeting,proto3" json:"greeting,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_greeting_v1_greeting_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_greeting_v1_greeting_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateResponse) GetGreeting() *Greeting {
+	if x != nil {
+		return x.Greeting
+	}
+	return nil
+}
+
+type ListLanguagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLanguagesRequest) Reset() {
+	*x = ListLanguagesRequest{}
+	mi := &file_greeting_v1_greeting_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLanguagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLanguagesRequest) ProtoMessage() {}
+
+func (x *ListLanguagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_greeting_v1_greeting_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLanguagesRequest.ProtoReflect.Descriptor instead.
+func (*ListLanguagesRequest) Descriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{4}
+}
+
+// Language describes a supported language.
+type Language struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Name of the language in that language.
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Hello         string `protobuf:"bytes,3,opt,name=hello,proto3" json:"hello,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Language) Reset() {
+	*x = Language{}
+	mi := &file_greeting_v1_greeting_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Language) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Language) ProtoMessage() {}
+
+func (x *Language) ProtoReflect() protoreflect.Message {
+	mi := &file_greeting_v1_greeting_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Language.ProtoReflect.Descriptor instead.
+func (*Language) Descriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Language) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Language) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Language) GetHello() string {
+	if x != nil {
+		return x.Hello
+	}
+	return ""
+}
+
+type ListLanguagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Languages     []*Language            `protobuf:"bytes,1,rep,name=languages,proto3" json:"languages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLanguagesResponse) Reset() {
+	*x = ListLanguagesResponse{}
+	mi := &file_greeting_v1_greeting_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLanguagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLanguagesResponse) ProtoMessage() {}
+
+func (x *ListLanguagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_greeting_v1_greeting_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLanguagesResponse.ProtoReflect.Descriptor instead.
+func (*ListLanguagesResponse) Descriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListLanguagesResponse) GetLanguages() []*Language {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+type BatchGenerateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Zero-based position of the request on the stream.
+	Index int64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*BatchGenerateResponse_Greeting
+	//	*BatchGenerateResponse_Error
+	Result        isBatchGenerateResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGenerateResponse) Reset() {
+	*x = BatchGenerateResponse{}
+	mi := &file_greeting_v1_greeting_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGenerateResponse) ProtoMessage() {}
+
+func (x *BatchGenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_greeting_v1_greeting_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGenerateResponse.ProtoReflect.Descriptor instead.
+func (*BatchGenerateResponse) Descriptor() ([]byte, []int) {
+	return file_greeting_v1_greeting_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchGenerateResponse) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchGenerateResponse) GetResult() isBatchGenerateResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchGenerateResponse) GetGreeting() *Greeting {
+	if x != nil {
+		if x, ok := x.Result.(*BatchGenerateResponse_Greeting); ok {
+			return x.Greeting
+		}
+	}
+	return nil
+}
+
+func (x *BatchGenerateResponse) GetError() *status.Status {
+	if x != nil {
+		if x, ok := x.Result.(*BatchGenerateResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchGenerateResponse_Result interface {
+	isBatchGenerateResponse_Result()
+}
+
+type BatchGenerateResponse_Greeting struct {
+	Greeting *Greeting `protobuf:"bytes,2,opt,name=greeting,proto3,oneof"`
+}
+
+type BatchGenerateResponse_Error struct {
+	Error *status.Status `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchGenerateResponse_Greeting) isBatchGenerateResponse_Result() {}
+
+func (*BatchGenerateResponse_Error) isBatchGenerateResponse_Result() {}
+
+var File_greeting_v1_greeting_proto protoreflect.FileDescriptor
+
+const file_greeting_v1_greeting_proto_rawDesc = "" +
+	"\n" +
+	"\x1agreeting/v1/greeting.proto\x12\vgreeting.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17google/rpc/status.proto\"\xaa\x01\n" +
+	"\aOptions\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x1c\n" +
+	"\tfallbacks\x18\x03 \x03(\tR\tfallbacks\x12#\n" +
+	"\rinclude_emoji\x18\x04 \x01(\bR\fincludeEmoji\x12\x14\n" +
+	"\x05names\x18\x05 \x03(\tR\x05names\x12\x16\n" +
+	"\x06gender\x18\x06 \x01(\tR\x06gender\"\xaa\x02\n" +
+	"\bGreeting\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12-\n" +
+	"\x12requested_language\x18\x03 \x01(\tR\x11requestedLanguage\x127\n" +
+	"\n" +
+	"confidence\x18\x04 \x01(\x0e2\x17.greeting.v1.ConfidenceR\n" +
+	"confidence\x12\x14\n" +
+	"\x05emoji\x18\x05 \x01(\tR\x05emoji\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06period\x18\a \x01(\tR\x06period\x12\x18\n" +
+	"\aholiday\x18\b \x01(\tR\aholiday\"A\n" +
+	"\x0fGenerateRequest\x12.\n" +
+	"\aoptions\x18\x01 \x01(\v2\x14.greeting.v1.OptionsR\aoptions\"E\n" +
+	"\x10GenerateResponse\x121\n" +
+	"\bgreeting\x18\x01 \x01(\v2\x15.greeting.v1.GreetingR\bgreeting\"\x16\n" +
+	"\x14ListLanguagesRequest\"H\n" +
+	"\bLanguage\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05hello\x18\x03 \x01(\tR\x05hello\"L\n" +
+	"\x15ListLanguagesResponse\x123\n" +
+	"\tlanguages\x18\x01 \x03(\v2\x15.greeting.v1.LanguageR\tlanguages\"\x98\x01\n" +
+	"\x15BatchGenerateResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x03R\x05index\x123\n" +
+	"\bgreeting\x18\x02 \x01(\v2\x15.greeting.v1.GreetingH\x00R\bgreeting\x12*\n" +
+	"\x05error\x18\x03 \x01(\v2\x12.google.rpc.StatusH\x00R\x05errorB\b\n" +
+	"\x06result*|\n" +
+	"\n" +
+	"Confidence\x12\x1a\n" +
+	"\x16CONFIDENCE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10CONFIDENCE_EXACT\x10\x01\x12\x13\n" +
+	"\x0fCONFIDENCE_HIGH\x10\x02\x12\x12\n" +
+	"\x0eCONFIDENCE_LOW\x10\x03\x12\x13\n" +
+	"\x0fCONFIDENCE_NONE\x10\x042\x89\x02\n" +
+	"\x0fGreetingService\x12G\n" +
+	"\bGenerate\x12\x1c.greeting.v1.GenerateRequest\x1a\x1d.greeting.v1.GenerateResponse\x12V\n" +
+	"\rListLanguages\x12!.greeting.v1.ListLanguagesRequest\x1a\".greeting.v1.ListLanguagesResponse\x12U\n" +
+	"\rBatchGenerate\x12\x1c.greeting.v1.GenerateRequest\x1a\".greeting.v1.BatchGenerateResponse(\x010\x01BGZEgithub.com/go-cli-template/hello-world-cli/api/greeting/v1;greetingv1b\x06proto3"
+
+var (
+	file_greeting_v1_greeting_proto_rawDescOnce sync.Once
+	file_greeting_v1_greeting_proto_rawDescData []byte
+)
+
+func file_greeting_v1_greeting_proto_rawDescGZIP() []byte {
+	file_greeting_v1_greeting_proto_rawDescOnce.Do(func() {
+		file_greeting_v1_greeting_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_greeting_v1_greeting_proto_rawDesc), len(file_greeting_v1_greeting_proto_rawDesc)))
+	})
+	return file_greeting_v1_greeting_proto_rawDescData
+}
+
+var file_greeting_v1_greeting_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_greeting_v1_greeting_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_greeting_v1_greeting_proto_goTypes = []any{
+	(Confidence)(0),               // 0: greeting.v1.Confidence
+	(*Options)(nil),               // 1: greeting.v1.Options
+	(*Greeting)(nil),              // 2: greeting.v1.Greeting
+	(*GenerateRequest)(nil),       // 3: greeting.v1.GenerateRequest
+	(*GenerateResponse)(nil),      // 4: greeting.v1.GenerateResponse
+	(*ListLanguagesRequest)(nil),  // 5: greeting.v1.ListLanguagesRequest
+	(*Language)(nil),              // 6: greeting.v1.Language
+	(*ListLanguagesResponse)(nil), // 7: greeting.v1.ListLanguagesResponse
+	(*BatchGenerateResponse)(nil), // 8: greeting.v1.BatchGenerateResponse
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*status.Status)(nil),         // 10: google.rpc.Status
+}
+var file_greeting_v1_greeting_proto_depIdxs = []int32{
+	0,  // 0: greeting.v1.Greeting.confidence:type_name -> greeting.v1.Confidence
+	9,  // 1: greeting.v1.Greeting.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 2: greeting.v1.GenerateRequest.options:type_name -> greeting.v1.Options
+	2,  // 3: greeting.v1.GenerateResponse.greeting:type_name -> greeting.v1.Greeting
+	6,  // 4: greeting.v1.ListLanguagesResponse.languages:type_name -> greeting.v1.Language
+	2,  // 5: greeting.v1.BatchGenerateResponse.greeting:type_name -> greeting.v1.Greeting
+	10, // 6: greeting.v1.BatchGenerateResponse.error:type_name -> google.rpc.Status
+	3,  // 7: greeting.v1.GreetingService.Generate:input_type -> greeting.v1.GenerateRequest
+	5,  // 8: greeting.v1.GreetingService.ListLanguages:input_type -> greeting.v1.ListLanguagesRequest
+	3,  // 9: greeting.v1.GreetingService.BatchGenerate:input_type -> greeting.v1.GenerateRequest
+	4,  // 10: greeting.v1.GreetingService.Generate:output_type -> greeting.v1.GenerateResponse
+	7,  // 11: greeting.v1.GreetingService.ListLanguages:output_type -> greeting.v1.ListLanguagesResponse
+	8,  // 12: greeting.v1.GreetingService.BatchGenerate:output_type -> greeting.v1.BatchGenerateResponse
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_greeting_v1_greeting_proto_init() }
+func file_greeting_v1_greeting_proto_init() {
+	if File_greeting_v1_greeting_proto != nil {
+		return
+	}
+	file_greeting_v1_greeting_proto_msgTypes[7].OneofWrappers = []any{
+		(*BatchGenerateResponse_Greeting)(nil),
+		(*BatchGenerateResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_greeting_v1_greeting_proto_rawDesc), len(file_greeting_v1_greeting_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_greeting_v1_greeting_proto_goTypes,
+		DependencyIndexes: file_greeting_v1_greeting_proto_depIdxs,
+		EnumInfos:         file_greeting_v1_greeting_proto_enumTypes,
+		MessageInfos:      file_greeting_v1_greeting_proto_msgTypes,
+	}.Build()
+	File_greeting_v1_greeting_proto = out.File
+	file_greeting_v1_greeting_proto_goTypes = nil
+	file_greeting_v1_greeting_proto_depIdxs = nil
+}
@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v6.33.1
+// source: greeting/v1/greeting.proto
+
+package greetingv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GreetingService_Generate_FullMethodName      = "/greeting.v1.GreetingService/Generate"
+	GreetingService_ListLanguages_FullMethodName = "/greeting.v1.GreetingService/ListLanguages"
+	GreetingService_BatchGenerate_FullMethodName = "/greeting.v1.GreetingService/BatchGenerate"
+)
+
+// GreetingServiceClient is the client API for GreetingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GreetingService generates localized greetings.
+type GreetingServiceClient interface {
+	// Generate creates a single greeting.
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	// ListLanguages returns every supported language.
+	ListLanguages(ctx context.Context, in *ListLanguagesRequest, opts ...grpc.CallOption) (*ListLanguagesResponse, error)
+	// BatchGenerate answers each request on the stream with one response, in
+	// order. Invalid requests are reported per item and do not end the stream.
+	BatchGenerate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GenerateRequest, BatchGenerateResponse], error)
+}
+
+type greetingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGreetingServiceClient(cc grpc.ClientConnInterface) GreetingServiceClient {
+	return &greetingServiceClient{cc}
+}
+
+func (c *greetingServiceClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, GreetingService_Generate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *greetingServiceClient) ListLanguages(ctx context.Context, in *ListLanguagesRequest, opts ...grpc.CallOption) (*ListLanguagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLanguagesResponse)
+	err := c.cc.Invoke(ctx, GreetingService_ListLanguages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *greetingServiceClient) BatchGenerate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GenerateRequest, BatchGenerateResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GreetingService_ServiceDesc.Streams[0], GreetingService_BatchGenerate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenerateRequest, BatchGenerateResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GreetingService_BatchGenerateClient = grpc.BidiStreamingClient[GenerateRequest, BatchGenerateResponse]
+
+// GreetingServiceServer is the server API for GreetingService service.
+// All implementations must embed UnimplementedGreetingServiceServer
+// for forward compatibility.
+//
+// GreetingService generates localized greetings.
+type GreetingServiceServer interface {
+	// Generate creates a single greeting.
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	// ListLanguages returns every supported language.
+	ListLanguages(context.Context, *ListLanguagesRequest) (*ListLanguagesResponse, error)
+	// BatchGenerate answers each request on the stream with one response, in
+	// order. Invalid requests are reported per item and do not end the stream.
+	BatchGenerate(grpc.BidiStreamingServer[GenerateRequest, BatchGenerateResponse]) error
+	mustEmbedUnimplementedGreetingServiceServer()
+}
+
+// UnimplementedGreetingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGreetingServiceServer struct{}
+
+func (UnimplementedGreetingServiceServer) Generate(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedGreetingServiceServer) ListLanguages(context.Context, *ListLanguagesRequest) (*ListLanguagesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListLanguages not implemented")
+}
+func (UnimplementedGreetingServiceServer) BatchGenerate(grpc.BidiStreamingServer[GenerateRequest, BatchGenerateResponse]) error {
+	return status.Error(codes.Unimplemented, "method BatchGenerate not implemented")
+}
+func (UnimplementedGreetingServiceServer) mustEmbedUnimplementedGreetingServiceServer() {}
+func (UnimplementedGreetingServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeGreetingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GreetingServiceServer will
+// result in compilation errors.
+type UnsafeGreetingServiceServer interface {
+	mustEmbedUnimplementedGreetingServiceServer()
+}
+
+func RegisterGreetingServiceServer(s grpc.ServiceRegistrar, srv GreetingServiceServer) {
+	// If the following call panics, it indicates UnimplementedGreetingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GreetingService_ServiceDesc, srv)
+}
+
+func _GreetingService_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GreetingServiceServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GreetingService_Generate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GreetingServiceServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GreetingService_ListLanguages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLanguagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GreetingServiceServer).ListLanguages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GreetingService_ListLanguages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GreetingServiceServer).ListLanguages(ctx, req.(*ListLanguagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GreetingService_BatchGenerate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GreetingServiceServer).BatchGenerate(&grpc.GenericServerStream[GenerateRequest, BatchGenerateResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GreetingService_BatchGenerateServer = grpc.BidiStreamingServer[GenerateRequest, BatchGenerateResponse]
+
+// GreetingService_ServiceDesc is the grpc.ServiceDesc for GreetingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GreetingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "greeting.v1.GreetingService",
+	HandlerType: (*GreetingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _GreetingService_Generate_Handler,
+		},
+		{
+			MethodName: "ListLanguages",
+			Handler:    _GreetingService_ListLanguages_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchGenerate",
+			Handler:       _GreetingService_BatchGenerate_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "greeting/v1/greeting.proto",
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	// Embed the time zone database so --tz works on systems without one
+	_ "time/tzdata"
+
 	"github.com/go-cli-template/hello-world-cli/internal/cli"
 	"github.com/go-cli-template/hello-world-cli/internal/errors"
 )
@@ -16,6 +19,7 @@ func main() {
 	if err := cli.Execute(); err != nil {
 		// Set debug mode if enabled
 		errors.SetDebug(cli.IsDebug())
+		errors.SetRedactor(cli.Redactor())
 		// Use the error handler for consistent error presentation
 		errors.Exit(err)
 	}
@@ -0,0 +1,386 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	appconfig "github.com/go-cli-template/hello-world-cli/internal/config"
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// settingList is the config view result
+type settingList []appconfig.Setting
+
+// String renders the settings as aligned columns for text output
+func (l settingList) String() string {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, s := range l {
+		source := string(s.Source)
+		if s.Origin != "" {
+			source += " (" + s.Origin + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Key, appconfig.FormatValue(s.Value), source)
+	}
+	_ = tw.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewCommand creates the config command and its subcommands
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and edit configuration",
+		Long: `View and edit the configuration of hello-world-cli.
+
+Settings come from, in increasing order of precedence: built-in defaults,
+the config files, the profile selected with --profile or
+HELLO_WORLD_CLI_PROFILE, the unprefixed LOG_LEVEL and LOG_FORMAT variables,
+HELLO_WORLD_CLI_* environment variables and command-line flags.
+
+The get, set and unset subcommands take dotted keys such as log.level; set
+and unset edit the config file in place, inside the profile named by
+--profile if given.
+
+String values may read a secret with ${env:NAME}, ${file:PATH} or
+${cmd:COMMAND}; such values are shown as [REDACTED]. References in a
+project .hello-world-cli.yaml are not resolved.`,
+		Example: `  # Show every setting and where it comes from
+  hello-world-cli config view
+
+  # Change the log level in the config file
+  hello-world-cli config set log.level debug
+
+  # Show the settings of the ci profile, and change one of them
+  hello-world-cli config view --profile ci
+  hello-world-cli config set --profile ci log.format json
+
+  # Check the config file for mistakes
+  hello-world-cli config validate
+
+  # Write a JSON Schema for editor completion
+  hello-world-cli config schema > hello-world-cli.schema.json`,
+	}
+
+	cmd.AddCommand(
+		newViewCommand(),
+		newGetCommand(),
+		newSetCommand(),
+		newUnsetCommand(),
+		newPathCommand(),
+		newValidateCommand(),
+		newSchemaCommand(),
+	)
+	return cmd
+}
+
+func newViewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Show the effective configuration and the source of each key",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := output.FromCommand(cmd)
+			if err != nil {
+				return err
+			}
+			settings, err := resolve(cmd)
+			if err != nil {
+				return err
+			}
+			for i, s := range settings {
+				if settings[i], err = s.Expand(); err != nil {
+					return err
+				}
+			}
+			return printer.Print(cmd.OutOrStdout(), settingList(settings))
+		},
+	}
+}
+
+func newGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "get <key>",
+		Short:             "Print the effective value of a key",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := appconfig.Lookup(args[0]); !ok {
+				return &errors.ConfigError{Key: args[0], Message: "unknown configuration key"}
+			}
+			printer, err := output.FromCommand(cmd)
+			if err != nil {
+				return err
+			}
+			settings, err := resolve(cmd)
+			if err != nil {
+				return err
+			}
+			for _, s := range settings {
+				if s.Key == args[0] {
+					if s, err = s.Expand(); err != nil {
+						return err
+					}
+					return printer.Print(cmd.OutOrStdout(), s.Value)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func newSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "set <key> <value>",
+		Short:             "Set a key in the config file",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := appconfig.ParseValue(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			// Problems from here on are not usage errors
+			cmd.SilenceUsage = true
+			file, err := readFile(cmd)
+			if err != nil {
+				return err
+			}
+			if err := file.Set(fileKey(cmd, args[0]), value); err != nil {
+				return err
+			}
+			return file.Save()
+		},
+	}
+}
+
+func newUnsetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "unset <key>",
+		Short:             "Remove a key from the config file",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			file, err := readFile(cmd)
+			if err != nil {
+				return err
+			}
+			key := fileKey(cmd, args[0])
+			if !file.Unset(key) {
+				return errors.New(errors.CodeNotFound, fmt.Sprintf("%s is not set in %s", key, file.Path))
+			}
+			return file.Save()
+		},
+	}
+}
+
+// locationList is the config path --all result
+type locationList []appconfig.Location
+
+// String renders the locations as aligned columns for text output
+func (l locationList) String() string {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, loc := range l {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", loc.Scope, loc.Path, loc.Status())
+	}
+	_ = tw.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func newPathCommand() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "path",
+		Short: "Print the path of the config file",
+		Long: `Print the config file that set and unset edit. With --all, list every
+config file location consulted, lowest precedence first, and whether it was
+read.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all {
+				printer, err := output.FromCommand(cmd)
+				if err != nil {
+					return err
+				}
+				_, locations, err := load(cmd)
+				if err != nil {
+					return err
+				}
+				return printer.Print(cmd.OutOrStdout(), locationList(locations))
+			}
+
+			path, err := filePath(cmd)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), path)
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "List every config file location consulted")
+	return cmd
+}
+
+func newValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config files against the known keys and values",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			layers, locations, err := load(cmd)
+			if err != nil {
+				return err
+			}
+
+			var found []string
+			problems := 0
+			for _, loc := range locations {
+				if !loc.Exists {
+					continue
+				}
+				found = append(found, loc.Path)
+				if loc.Err != nil {
+					if errors.IsFile(loc.Err) {
+						return loc.Err
+					}
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s\n", loc.Path, loc.Err.Error())
+					problems++
+				}
+			}
+			if len(found) == 0 {
+				return errors.New(errors.CodeConfigNotFound, "config file not found").
+					WithDetails("files", locationPaths(locations))
+			}
+
+			for _, file := range layers {
+				for _, p := range appconfig.Validate(file.Values()) {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s: %s (got %q)\n", file.Path, p.Key, p.Message, p.Value)
+					problems++
+				}
+			}
+			if problems > 0 {
+				return errors.New(errors.CodeConfigInvalid, fmt.Sprintf("config files have %d problem(s)", problems)).
+					WithDetails("files", found)
+			}
+
+			for _, path := range found {
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", path); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// configFlag returns the --config flag value
+func configFlag(cmd *cobra.Command) string {
+	if f := cmd.Root().PersistentFlags().Lookup("config"); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// filePath returns the config file to edit: the --config flag, then the
+// user config file
+func filePath(cmd *cobra.Command) (string, error) {
+	if path := configFlag(cmd); path != "" {
+		return path, nil
+	}
+	return appconfig.DefaultPath()
+}
+
+func readFile(cmd *cobra.Command) (*appconfig.File, error) {
+	path, err := filePath(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return appconfig.ReadFile(path)
+}
+
+// load reads the --config file, or the config files discovered from the
+// working directory
+func load(cmd *cobra.Command) (appconfig.Layers, []appconfig.Location, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = ""
+	}
+	return appconfig.Load(configFlag(cmd), dir)
+}
+
+func locationPaths(locations []appconfig.Location) []string {
+	paths := make([]string, 0, len(locations))
+	for _, loc := range locations {
+		paths = append(paths, loc.Path)
+	}
+	return paths
+}
+
+// resolve returns the effective settings for the command's flags,
+// environment and config files
+func resolve(cmd *cobra.Command) ([]appconfig.Setting, error) {
+	layers, locations, err := load(cmd)
+	if err != nil {
+		return nil, err
+	}
+	for _, loc := range locations {
+		if loc.Err != nil {
+			return nil, loc.Err
+		}
+	}
+
+	flags := cmd.Root().PersistentFlags()
+	return appconfig.Resolve(appconfig.Sources{
+		Flags:     flags,
+		LookupEnv: os.LookupEnv,
+		Files:     layers,
+		Profile:   appconfig.ProfileName(flags, os.LookupEnv),
+	})
+}
+
+// fileKey returns where set and unset edit a key: inside the profile given
+// by --profile, or at the top level of the file
+func fileKey(cmd *cobra.Command, key string) string {
+	if f := cmd.Root().PersistentFlags().Lookup(appconfig.ProfileFlag); f != nil && f.Value.String() != "" {
+		return appconfig.ProfilesKey + "." + f.Value.String() + "." + key
+	}
+	return key
+}
+
+// completeKeys completes the key argument with the known keys
+func completeKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(appconfig.Keys))
+	for _, key := range appconfig.Keys {
+		names = append(names, key.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func newSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print a JSON Schema of the config file",
+		Long: `Print a JSON Schema describing the keys, types and allowed values of
+the config file, including profiles. Point an editor's YAML or JSON language
+server at it for completion and validation.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(appconfig.Schema())
+		},
+	}
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// executeConfig runs the config command under a root with the global
+// flags it reads
+func executeConfig(args ...string) (stdout, stderr string, err error) {
+	root := &cobra.Command{Use: "hello-world-cli"}
+	root.PersistentFlags().String("config", "", "")
+	root.PersistentFlags().String("log-level", "", "")
+	root.PersistentFlags().String("profile", "", "")
+	output.AddFlag(root.PersistentFlags())
+	root.AddCommand(NewCommand())
+
+	outBuf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	root.SetOut(outBuf)
+	root.SetErr(errBuf)
+	root.SetArgs(args)
+	err = root.Execute()
+	return outBuf.String(), errBuf.String(), err
+}
+
+func TestConfigCommand(t *testing.T) {
+	t.Setenv("HELLO_WORLD_CLI_LOG_FORMAT", "json")
+
+	tests := []struct {
+		name       string
+		file       string
+		args       []string
+		wantOutput []string
+		wantErrors []string
+		wantFile   string
+		wantCode   errors.ErrorCode
+	}{
+		{
+			name: "view shows sources",
+			file: "log:\n  level: warn\n",
+			args: []string{"config", "view"},
+			wantOutput: []string{
+				"debug false default",
+				"greeting.emoji false default",
+				"log.format json env (HELLO_WORLD_CLI_LOG_FORMAT)",
+				"log.level warn file (",
+			},
+		},
+		{
+			name:       "flag wins over file",
+			file:       "log:\n  level: warn\n",
+			args:       []string{"--log-level", "error", "config", "get", "log.level"},
+			wantOutput: []string{"error"},
+		},
+		{
+			name:     "get unknown key",
+			args:     []string{"config", "get", "bogus"},
+			wantCode: errors.CodeConfig,
+		},
+		{
+			name:     "set keeps comments",
+			file:     "# Logging\nlog:\n  level: warn # quiet\n",
+			args:     []string{"config", "set", "log.level", "debug"},
+			wantFile: "# Logging\nlog:\n  level: debug # quiet\n",
+		},
+		{
+			name:     "set rejects invalid value",
+			file:     "log:\n  level: warn\n",
+			args:     []string{"config", "set", "log.level", "loud"},
+			wantCode: errors.CodeConfig,
+			wantFile: "log:\n  level: warn\n",
+		},
+		{
+			name:     "unset removes key",
+			file:     "verbose: true\nlog:\n  level: warn\n",
+			args:     []string{"config", "unset", "log.level"},
+			wantFile: "verbose: true\n",
+		},
+		{
+			name:     "unset missing key",
+			file:     "verbose: true\n",
+			args:     []string{"config", "unset", "debug"},
+			wantCode: errors.CodeNotFound,
+		},
+		{
+			name:       "validate valid file",
+			file:       "debug: true\n",
+			args:       []string{"config", "validate"},
+			wantOutput: []string{"is valid"},
+		},
+		{
+			name:       "validate reports every problem",
+			file:       "debug: yes please\nlog:\n  level: loud\n",
+			args:       []string{"config", "validate"},
+			wantErrors: []string{"debug: must be true or false", "log.level: must be one of debug, info, warn, error"},
+			wantCode:   errors.CodeConfigInvalid,
+		},
+		{
+			name:     "validate malformed file",
+			file:     "log: [",
+			args:     []string{"config", "validate"},
+			wantCode: errors.CodeConfigInvalid,
+		},
+		{
+			name:     "validate missing file",
+			args:     []string{"config", "validate"},
+			wantCode: errors.CodeConfigNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if tt.file != "" {
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stdout, stderr, err := executeConfig(append([]string{"--config", path}, tt.args...)...)
+			if tt.wantCode == "" && err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if tt.wantCode != "" && errors.GetCode(err) != tt.wantCode {
+				t.Fatalf("Execute() error = %v, want code %v", err, tt.wantCode)
+			}
+
+			// Compare text with columns collapsed to single spaces
+			stdout = strings.Join(strings.Fields(stdout), " ")
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("output = %q, want substring %q", stdout, want)
+				}
+			}
+			for _, want := range tt.wantErrors {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("errors = %q, want substring %q", stderr, want)
+				}
+			}
+			if tt.wantFile != "" {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != tt.wantFile {
+					t.Errorf("file = %q, want %q", data, tt.wantFile)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	stdout, _, err := executeConfig("--config", "/etc/app.yaml", "config", "path")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if stdout != "/etc/app.yaml\n" {
+		t.Errorf("output = %q, want %q", stdout, "/etc/app.yaml\n")
+	}
+}
+
+func TestConfigPathAll(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if err := os.WriteFile(filepath.Join(home, ".hello-world-cli.yaml"), []byte("verbose: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := executeConfig("config", "path", "--all")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	got := strings.Join(strings.Fields(stdout), " ")
+	for _, want := range []string{
+		"user " + filepath.Join(home, ".config", "hello-world-cli", "config.json") + " not found",
+		"legacy " + filepath.Join(home, ".hello-world-cli.yaml") + " read",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output = %q, want substring %q", got, want)
+		}
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	stdout, _, err := executeConfig("config", "schema")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var schema struct {
+		Schema     string                     `json:"$schema"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &schema); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if schema.Schema == "" {
+		t.Error("schema has no $schema")
+	}
+	for _, key := range []string{"debug", "log", "greeting", "profiles"} {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("schema has no %s property", key)
+		}
+	}
+}
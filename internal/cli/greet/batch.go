@@ -0,0 +1,380 @@
+package greet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/greeting"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Batch input and output formats
+const (
+	formatAuto   = "auto"
+	formatLines  = "lines"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// stdinName is the --input value that reads from standard input
+const stdinName = "-"
+
+// record is a single row of batch input
+type record struct {
+	Line  int
+	Name  string
+	Lang  string
+	Emoji *bool
+	Err   error // Set when the row could not be parsed
+}
+
+// recordReader yields batch records until io.EOF
+type recordReader interface {
+	Next() (*record, error)
+}
+
+// result is a single row of batch output
+type result struct {
+	Line  int    `json:"line"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error,omitempty"`
+	*greeting.Greeting
+}
+
+// Header implements output.Tabler
+func (r *result) Header() []string {
+	return []string{"line", "name", "language", "message", "error"}
+}
+
+// Row implements output.Tabler
+func (r *result) Row() []string {
+	row := []string{strconv.Itoa(r.Line), r.Name, "", "", r.Error}
+	if r.Greeting != nil {
+		row[2] = r.Language
+		row[3] = r.Message
+	}
+	return row
+}
+
+// String renders the greeting for text output
+func (r *result) String() string {
+	if r.Greeting == nil {
+		return ""
+	}
+	return r.Message
+}
+
+// batchStats counts processed and failed records
+type batchStats struct {
+	total  int
+	failed int
+}
+
+// runBatch greets every record from the configured inputs
+func runBatch(cmd *cobra.Command, opts *Options, base greeting.Options, printer *output.Printer) error {
+	log := logger.FromContext(cmd.Context())
+
+	// Flags were valid; per-record failures should not print usage
+	cmd.SilenceUsage = true
+
+	inputs := opts.Inputs
+	if len(inputs) == 0 {
+		inputs = []string{stdinName}
+	}
+
+	w := printer.NewStream(cmd.OutOrStdout())
+	textOutput := printer.Format == output.FormatText
+
+	stats := &batchStats{}
+	for _, input := range inputs {
+		if err := processInput(cmd, opts, base, input, w, textOutput, stats); err != nil {
+			_ = w.Flush()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return errors.Wrap(err, errors.CodeFileWrite, "failed to write batch output")
+	}
+
+	log.Info("batch greeting complete",
+		"records", stats.total,
+		"failed", stats.failed,
+	)
+
+	if stats.failed > 0 {
+		return errors.New(errors.CodeValidation,
+			fmt.Sprintf("%d of %d records failed validation", stats.failed, stats.total))
+	}
+	return nil
+}
+
+// processInput reads one input source and writes a result for each record.
+// Text output reports failed records on stderr instead of the result stream.
+func processInput(cmd *cobra.Command, opts *Options, base greeting.Options, input string, w output.Stream, textOutput bool, stats *batchStats) error {
+	log := logger.FromContext(cmd.Context())
+
+	var in io.Reader
+	if input == stdinName {
+		in = cmd.InOrStdin()
+	} else {
+		f, err := os.Open(input) //nolint:gosec // Input paths are user-supplied by design
+		if err != nil {
+			return &errors.FileError{Path: input, Operation: "read", Err: err}
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+
+	format := resolveInputFormat(opts.InputFormat, input)
+	reader, err := newRecordReader(format, in)
+	if err != nil {
+		return err
+	}
+	log.Debug("reading batch input", "input", input, "format", format)
+
+	for {
+		rec, err := reader.Next()
+		if stderrors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return &errors.FileError{Path: input, Operation: "read", Err: err}
+		}
+
+		stats.total++
+		res := greetRecord(cmd, base, rec)
+		if rec.Err != nil {
+			stats.failed++
+			log.Debug("batch record failed validation", "input", input, "line", rec.Line, "error", rec.Err)
+			if opts.FailFast {
+				return errors.New(errors.CodeValidation,
+					fmt.Sprintf("%s line %d: %v", input, rec.Line, rec.Err)).
+					WithDetails("input", input).
+					WithDetails("line", rec.Line)
+			}
+			if textOutput {
+				cmd.PrintErrf("%s line %d: %v\n", input, rec.Line, rec.Err)
+				continue
+			}
+		}
+
+		if err := w.Write(res); err != nil {
+			return errors.Wrap(err, errors.CodeFileWrite, "failed to write batch output")
+		}
+	}
+}
+
+// greetRecord validates a record and generates its greeting, starting from
+// the options shared by the whole batch
+func greetRecord(cmd *cobra.Command, base greeting.Options, rec *record) *result {
+	res := &result{Line: rec.Line, Name: rec.Name}
+
+	if rec.Err == nil {
+		rec.Err = validateRecord(rec)
+	}
+	if rec.Err != nil {
+		res.Error = rec.Err.Error()
+		return res
+	}
+
+	greetOpts := base
+	greetOpts.Names = []string{rec.Name}
+	if rec.Lang != "" {
+		greetOpts.Language = rec.Lang
+	}
+	if rec.Emoji != nil {
+		greetOpts.IncludeEmoji = *rec.Emoji
+	}
+
+	res.Greeting = generate(cmd, greetOpts)
+	return res
+}
+
+// validateRecord checks the fields of a parsed record
+func validateRecord(rec *record) error {
+	if rec.Name == "" {
+		return &errors.ValidationError{Field: "name", Value: rec.Name, Message: "name is required"}
+	}
+	if rec.Lang != "" {
+		if _, err := greeting.ParseTag(rec.Lang); err != nil {
+			return &errors.ValidationError{Field: "lang", Value: rec.Lang, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// resolveInputFormat picks the input format, guessing from the file extension
+func resolveInputFormat(format, input string) string {
+	if format != formatAuto {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(input)) {
+	case ".csv":
+		return formatCSV
+	case ".ndjson", ".jsonl":
+		return formatNDJSON
+	default:
+		return formatLines
+	}
+}
+
+// newRecordReader creates a reader for the given input format
+func newRecordReader(format string, in io.Reader) (recordReader, error) {
+	switch format {
+	case formatLines:
+		return &lineReader{scanner: bufio.NewScanner(in)}, nil
+	case formatNDJSON:
+		return &ndjsonReader{scanner: bufio.NewScanner(in)}, nil
+	case formatCSV:
+		return newCSVReader(in)
+	default:
+		return nil, &errors.ValidationError{
+			Field:   "input-format",
+			Value:   format,
+			Message: "must be one of auto, lines, csv, ndjson",
+		}
+	}
+}
+
+// lineReader reads one name per line, skipping blank lines and # comments
+type lineReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *lineReader) Next() (*record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		return &record{Line: r.line, Name: text}, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// ndjsonReader reads one JSON object per line
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) Next() (*record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var row struct {
+			Name  string `json:"name"`
+			Lang  string `json:"lang"`
+			Emoji *bool  `json:"emoji"`
+		}
+		rec := &record{Line: r.line}
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			rec.Err = &errors.ValidationError{Field: "record", Value: text, Message: "invalid JSON: " + err.Error()}
+			return rec, nil
+		}
+		rec.Name = strings.TrimSpace(row.Name)
+		rec.Lang = strings.TrimSpace(row.Lang)
+		rec.Emoji = row.Emoji
+		return rec, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// csvReader reads CSV rows with a header naming the name, lang and emoji columns
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(in io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if stderrors.Is(err, io.EOF) {
+		return &csvReader{reader: reader}, nil
+	}
+	if err != nil {
+		return nil, &errors.ValidationError{Field: "header", Message: "invalid CSV header: " + err.Error()}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, col := range header {
+		columns[strings.ToLower(strings.TrimSpace(col))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, &errors.ValidationError{
+			Field:   "header",
+			Value:   strings.Join(header, ","),
+			Message: "CSV input must have a name column",
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (*record, error) {
+	if r.columns == nil {
+		return nil, io.EOF
+	}
+
+	row, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if stderrors.As(err, &parseErr) {
+			return &record{
+				Line: parseErr.Line,
+				Err:  &errors.ValidationError{Field: "record", Message: "invalid CSV: " + parseErr.Err.Error()},
+			}, nil
+		}
+		return nil, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	rec := &record{
+		Line: line,
+		Name: r.field(row, "name"),
+		Lang: r.field(row, "lang"),
+	}
+	if value := r.field(row, "emoji"); value != "" {
+		emoji, err := strconv.ParseBool(value)
+		if err != nil {
+			rec.Err = &errors.ValidationError{Field: "emoji", Value: value, Message: "must be true or false"}
+		}
+		rec.Emoji = &emoji
+	}
+	return rec, nil
+}
+
+// field returns the trimmed value of a named column, or "" if absent
+func (r *csvReader) field(row []string, name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
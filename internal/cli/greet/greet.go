@@ -1,21 +1,34 @@
 package greet
 
 import (
-	"encoding/json"
+	"os"
 
+	"github.com/go-cli-template/hello-world-cli/internal/cli/timeflags"
+	appconfig "github.com/go-cli-template/hello-world-cli/internal/config"
 	"github.com/go-cli-template/hello-world-cli/internal/errors"
 	"github.com/go-cli-template/hello-world-cli/internal/greeting"
 	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/internal/output"
+	"github.com/go-cli-template/hello-world-cli/internal/telemetry"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Options holds command options
 type Options struct {
-	Name         string
+	Names        []string
+	Gender       string
 	Language     string
+	Fallbacks    []string
 	IncludeEmoji bool
-	JSONOutput   bool
 	ListLangs    bool
+	Time         timeflags.Options
+
+	// Batch mode
+	Batch       bool
+	Inputs      []string
+	InputFormat string
+	FailFast    bool
 }
 
 // NewCommand creates the greet command
@@ -34,82 +47,174 @@ This command demonstrates personalized greetings with internationalization suppo
   # Spanish greeting with emoji
   hello-world-cli greet --name Carlos --lang es --emoji
   
-  # List supported languages
-  hello-world-cli greet --list-languages`,
+  # Greet a group; templates choose plural and gendered forms
+  hello-world-cli greet --name Ana --name Eva --lang es --gender female
+  
+  # Greeting for a given local time, with holidays
+  hello-world-cli greet --name Ana --lang es --at 2024-12-25T20:00:00Z --tz Europe/Madrid --calendar builtin
+  
+  # Regional tags fall back to the base language
+  hello-world-cli greet --name Lucía --lang es-MX -o json
+  
+  # Try Portuguese, then Spanish, when the locale is not supported
+  hello-world-cli greet --name Ana --lang gl --fallback pt,es
+  
+  # List supported languages as a table
+  hello-world-cli greet --list-languages -o table
+  
+  # Greet every name piped on stdin
+  cat names.txt | hello-world-cli greet --batch
+  
+  # Greet rows of a CSV file (name,lang,emoji columns) as NDJSON
+  hello-world-cli greet --input people.csv -o ndjson`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runGreet(cmd, opts)
 		},
 	}
 
 	// Add flags
-	cmd.Flags().StringVarP(&opts.Name, "name", "n", "", "Name to greet")
-	cmd.Flags().StringVarP(&opts.Language, "lang", "l", "en", "Language code (en, es, fr, de, ja, zh)")
+	cmd.Flags().StringArrayVarP(&opts.Names, "name", "n", nil, "Name to greet; repeat to greet a group")
+	cmd.Flags().StringVar(&opts.Gender, "gender", "", "Grammatical gender for the greeting (female, male, other)")
+	cmd.Flags().StringVarP(&opts.Language, "lang", "l", "", "Language tag such as es, pt-BR or zh-Hant (default from LC_ALL, LC_MESSAGES or LANG)")
+	cmd.Flags().StringSliceVar(&opts.Fallbacks, "fallback", nil, "Languages to try when --lang is not supported (default en)")
 	cmd.Flags().BoolVar(&opts.IncludeEmoji, "emoji", false, "Include emoji in greeting")
-	cmd.Flags().BoolVar(&opts.JSONOutput, "json", false, "Output in JSON format")
 	cmd.Flags().BoolVar(&opts.ListLangs, "list-languages", false, "List all supported languages")
+	opts.Time.AddFlags(cmd.Flags())
+
+	// Batch flags
+	cmd.Flags().BoolVar(&opts.Batch, "batch", false, "Read names from stdin, one greeting per record")
+	cmd.Flags().StringSliceVarP(&opts.Inputs, "input", "i", nil, "Batch input files (- for stdin); implies --batch")
+	cmd.Flags().StringVar(&opts.InputFormat, "input-format", formatAuto, "Batch input format (auto, lines, csv, ndjson)")
+	cmd.Flags().BoolVar(&opts.FailFast, "fail-fast", false, "Stop the batch at the first invalid record")
+
+	output.AddLegacyJSONFlag(cmd)
+
+	// Complete flag values in shells and the interactive shell
+	_ = cmd.RegisterFlagCompletionFunc("lang", completeLanguages)
+	_ = cmd.RegisterFlagCompletionFunc("fallback", completeLanguages)
+	_ = cmd.RegisterFlagCompletionFunc("gender", cobra.FixedCompletions(
+		[]string{greeting.GenderFemale, greeting.GenderMale, greeting.GenderOther}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("input-format", cobra.FixedCompletions(
+		[]string{formatAuto, formatLines, formatCSV, formatNDJSON}, cobra.ShellCompDirectiveNoFileComp))
 
 	return cmd
 }
 
+// completeLanguages completes language flags with the supported languages
+func completeLanguages(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return greeting.GetSupportedLanguages(), cobra.ShellCompDirectiveNoFileComp
+}
+
 func runGreet(cmd *cobra.Command, opts *Options) error {
 	log := logger.FromContext(cmd.Context())
 
 	// Log command execution
 	log.Debug("executing greet command",
-		"name", opts.Name,
+		"names", opts.Names,
+		"gender", opts.Gender,
 		"language", opts.Language,
 		"emoji", opts.IncludeEmoji,
-		"json", opts.JSONOutput,
 		"list_languages", opts.ListLangs,
+		"batch", opts.Batch || len(opts.Inputs) > 0,
 	)
 
+	printer, err := output.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Handle list languages request
 	if opts.ListLangs {
-		langs := greeting.GetSupportedLanguages()
+		langs := languageList(greeting.DescribeLanguages())
 		log.Debug("listing supported languages", "count", len(langs))
-		cmd.Println("Supported languages:")
-		for _, lang := range langs {
-			cmd.Printf("  %s\n", lang)
+		return printer.Print(cmd.OutOrStdout(), langs)
+	}
+
+	// Fall back to the configured preferences, then detect the language
+	// from the locale environment
+	cfg, err := appconfig.FromContext(cmd.Context()).Expand("greeting")
+	if err != nil {
+		return err
+	}
+	if opts.Language == "" {
+		opts.Language = cfg.Greeting.Language
+	}
+	if !cmd.Flags().Changed("emoji") {
+		opts.IncludeEmoji = cfg.Greeting.Emoji
+	}
+	if opts.Language == "" {
+		opts.Language = greeting.DetectLanguage(os.Getenv)
+		log.Debug("detected language from environment", "language", opts.Language)
+	}
+
+	gender, err := greeting.ParseGender(opts.Gender)
+	if err != nil {
+		return &errors.ValidationError{Field: "gender", Value: opts.Gender, Message: err.Error()}
+	}
+
+	// Create greeting options shared by single and batch greetings
+	greetOpts := greeting.Options{
+		Gender:       gender,
+		Language:     opts.Language,
+		Fallbacks:    opts.Fallbacks,
+		IncludeEmoji: opts.IncludeEmoji,
+	}
+	if err := opts.Time.Apply(&greetOpts); err != nil {
+		return err
+	}
+
+	// Greet many records when batch input is requested; the records give
+	// the names
+	if opts.Batch || len(opts.Inputs) > 0 {
+		if len(opts.Names) > 0 {
+			return &errors.ValidationError{Field: "name", Message: "--name cannot be used with --batch or --input; the batch input gives the names"}
 		}
-		return nil
+		return runBatch(cmd, opts, greetOpts, printer)
 	}
 
-	// Validate name is provided
-	if opts.Name == "" {
+	// Validate a name is provided
+	if len(opts.Names) == 0 {
 		log.Debug("name not provided")
 		return &errors.ValidationError{
 			Field:   "name",
-			Value:   opts.Name,
 			Message: "name is required",
 		}
 	}
-
-	// Create greeting options
-	greetOpts := greeting.Options{
-		Name:         opts.Name,
-		Language:     opts.Language,
-		IncludeEmoji: opts.IncludeEmoji,
+	for _, name := range opts.Names {
+		if name == "" {
+			return &errors.ValidationError{Field: "name", Value: name, Message: "name cannot be empty"}
+		}
 	}
+	greetOpts.Names = opts.Names
 
 	// Generate greeting
-	greet := greeting.Generate(greetOpts)
-	log.Info("generated personalized greeting",
-		"name", opts.Name,
-		"language", greet.Language,
-		"message", greet.Message,
-	)
+	greet := generate(cmd, greetOpts)
 
-	// Output based on format
-	if opts.JSONOutput {
-		output, err := json.MarshalIndent(greet, "", "  ")
-		if err != nil {
-			log.Error("failed to format JSON", "error", err)
-			return errors.Wrap(err, errors.CodeInternal, "failed to format JSON output")
-		}
-		cmd.Println(string(output))
-	} else {
-		cmd.Println(greet.Message)
+	// Output in the selected format
+	if err := printer.Print(cmd.OutOrStdout(), greet); err != nil {
+		log.Error("failed to write output", "error", err)
+		return errors.Wrap(err, errors.CodeInternal, "failed to write greeting output")
 	}
 
 	return nil
 }
+
+// generate creates a personalized greeting, traced as a child of the
+// command span, and logs it
+func generate(cmd *cobra.Command, opts greeting.Options) *greeting.Greeting {
+	ctx, span := telemetry.Tracer().Start(cmd.Context(), "greeting.Generate")
+	greet := greeting.Generate(opts)
+	span.SetAttributes(
+		attribute.String("greeting.language", greet.Language),
+		attribute.String("greeting.confidence", string(greet.Confidence)),
+	)
+	span.End()
+
+	logger.FromContext(logger.WithComponent(ctx, "greeting")).Info("generated personalized greeting",
+		"names", opts.Names,
+		"language", greet.Language,
+		"confidence", greet.Confidence,
+		"message", greet.Message,
+	)
+	return greet
+}
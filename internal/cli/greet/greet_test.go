@@ -0,0 +1,251 @@
+package greet
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// afternoon is the fixed time greet tests run at unless they pass --at
+const afternoon = "2026-03-10T15:00:00Z"
+
+// executeGreet runs greet at a fixed time under a root command carrying the
+// global --output flag
+func executeGreet(t *testing.T, stdin string, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	root := &cobra.Command{Use: "hello-world-cli"}
+	output.AddFlag(root.PersistentFlags())
+	root.AddCommand(NewCommand())
+
+	outBuf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	root.SetOut(outBuf)
+	root.SetErr(errBuf)
+	root.SetIn(strings.NewReader(stdin))
+	root.SetArgs(append([]string{"greet", "--at", afternoon}, args...))
+	err = root.Execute()
+	return outBuf.String(), errBuf.String(), err
+}
+
+func TestGreetCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantOutput string
+		wantErr    bool
+	}{
+		{
+			name:       "basic greeting",
+			args:       []string{"--name", "Alice", "--lang", "en"},
+			wantOutput: "Good afternoon, Alice! 🎉",
+		},
+		{
+			name:       "regional language tag",
+			args:       []string{"--name", "Lucía", "--lang", "es-MX"},
+			wantOutput: "¡Buenas tardes, Lucía!",
+		},
+		{
+			name:       "fallback list",
+			args:       []string{"--name", "Ana", "--lang", "gl", "--fallback", "fr"},
+			wantOutput: "Bonjour, Ana!",
+		},
+		{
+			name:       "group with gender",
+			args:       []string{"--name", "Ana", "--name", "Eva", "--lang", "fr", "--gender", "female"},
+			wantOutput: "Bonjour à toutes, Ana et Eva!",
+		},
+		{
+			name:       "time of day in time zone",
+			args:       []string{"--name", "Ana", "--lang", "es", "--tz", "Asia/Tokyo"},
+			wantOutput: "¡Buenas noches, Ana!",
+		},
+		{
+			name:       "holiday from builtin calendar",
+			args:       []string{"--name", "Ana", "--lang", "de", "--at", "2026-01-01T10:00:00Z", "--calendar", "builtin"},
+			wantOutput: "Frohes neues Jahr, Ana!",
+		},
+		{
+			name:    "unknown time zone",
+			args:    []string{"--name", "Ana", "--tz", "Mars/Olympus"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown gender",
+			args:    []string{"--name", "Ana", "--gender", "robot"},
+			wantErr: true,
+		},
+		{
+			name:       "json output",
+			args:       []string{"--name", "Hans", "--lang", "de", "-o", "json"},
+			wantOutput: `"message": "Guten Tag, Hans!"`,
+		},
+		{
+			name:       "list languages as csv",
+			args:       []string{"--list-languages", "-o", "csv"},
+			wantOutput: "code,name,hello\nde,Deutsch,\"Hallo, Welt!\"",
+		},
+		{
+			name:    "unknown output format",
+			args:    []string{"--name", "Hans", "-o", "xml"},
+			wantErr: true,
+		},
+		{
+			name:       "name with batch input",
+			args:       []string{"--name", "Ana", "--input", "people.csv"},
+			wantOutput: "--name cannot be used with --batch or --input",
+			wantErr:    true,
+		},
+		{
+			name:    "missing name",
+			args:    []string{"--lang", "en"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, err := executeGreet(t, "", tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := stdout + stderr; !strings.Contains(got, tt.wantOutput) {
+				t.Errorf("Execute() output = %q, want substring %q", got, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestGreetLogsNoNames(t *testing.T) {
+	var logs bytes.Buffer
+	cfg := logger.DefaultConfig()
+	cfg.Writer = &logs
+	ctx := logger.WithContext(context.Background(), logger.New(cfg))
+
+	root := &cobra.Command{Use: "hello-world-cli"}
+	output.AddFlag(root.PersistentFlags())
+	root.AddCommand(NewCommand())
+	root.SetOut(new(bytes.Buffer))
+	root.SetArgs([]string{"greet", "--at", afternoon, "--name", "Ana", "--name", "Bob", "--lang", "en"})
+	if err := root.ExecuteContext(ctx); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	got := logs.String()
+	if !strings.Contains(got, "generated personalized greeting") {
+		t.Fatalf("log is missing the greeting record:\n%s", got)
+	}
+	if strings.Contains(got, "Ana") || strings.Contains(got, "Bob") {
+		t.Errorf("log has the names greeted:\n%s", got)
+	}
+}
+
+func TestBatchLines(t *testing.T) {
+	stdout, _, err := executeGreet(t, "Alice\n\n# comment\nBob\n", "--batch", "--lang", "en")
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	want := "Good afternoon, Alice! 🎉\nGood afternoon, Bob! 🎉\n"
+	if stdout != want {
+		t.Errorf("Execute() output = %q, want %q", stdout, want)
+	}
+}
+
+func TestBatchCSVToNDJSON(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "people.csv")
+	content := "name,lang,emoji\nAna,es,true\n,fr,\nYuki,ja,\n"
+	if err := os.WriteFile(input, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	stdout, _, err := executeGreet(t, "", "--input", input, "-o", "ndjson", "--lang", "en")
+	if !errors.IsCode(err, errors.CodeValidation) {
+		t.Fatalf("Execute() error = %v, want %s", err, errors.CodeValidation)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d NDJSON lines, want 3: %q", len(lines), stdout)
+	}
+
+	var results []map[string]any
+	for _, line := range lines {
+		var res map[string]any
+		if err := json.Unmarshal([]byte(line), &res); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", line, err)
+		}
+		results = append(results, res)
+	}
+
+	if results[0]["message"] != "👋 ¡Buenas tardes, Ana!" {
+		t.Errorf("row 1 message = %v, want %q", results[0]["message"], "👋 ¡Buenas tardes, Ana!")
+	}
+	if results[1]["line"] != float64(3) || results[1]["error"] == nil {
+		t.Errorf("row 2 = %v, want validation error on line 3", results[1])
+	}
+	if results[2]["language"] != "ja" {
+		t.Errorf("row 3 language = %v, want ja", results[2]["language"])
+	}
+}
+
+func TestBatchNDJSONToCSV(t *testing.T) {
+	stdin := `{"name":"Marie","lang":"fr"}` + "\n" + `{"name":"Hans","lang":"de","emoji":true}` + "\n"
+	stdout, _, err := executeGreet(t, stdin, "--batch", "--input-format", "ndjson", "-o", "csv")
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV output: %v", err)
+	}
+	want := [][]string{
+		{"line", "name", "language", "message", "error"},
+		{"1", "Marie", "fr", "Bonjour, Marie!", ""},
+		{"2", "Hans", "de", "👋 Guten Tag, Hans!", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d CSV rows, want %d: %q", len(rows), len(want), stdout)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestBatchFailFast(t *testing.T) {
+	stdout, _, err := executeGreet(t, "name\nAlice\n\"\"\nBob\n", "--batch", "--input-format", "csv", "--fail-fast", "--lang", "en")
+	if !errors.IsCode(err, errors.CodeValidation) {
+		t.Fatalf("Execute() error = %v, want %s", err, errors.CodeValidation)
+	}
+	if strings.Contains(stdout, "Bob") {
+		t.Errorf("Execute() output = %q, want batch to stop before Bob", stdout)
+	}
+	if !strings.Contains(stdout, "Alice") {
+		t.Errorf("Execute() output = %q, want rows before the failure", stdout)
+	}
+}
+
+func TestBatchMissingInput(t *testing.T) {
+	_, _, err := executeGreet(t, "", "--input", filepath.Join(t.TempDir(), "missing.txt"))
+	if !errors.IsFile(err) {
+		t.Fatalf("Execute() error = %v, want FileError", err)
+	}
+}
+
+func TestBatchCSVWithoutNameColumn(t *testing.T) {
+	_, _, err := executeGreet(t, "lang,emoji\nes,true\n", "--batch", "--input-format", "csv")
+	if !errors.IsValidation(err) {
+		t.Fatalf("Execute() error = %v, want ValidationError", err)
+	}
+}
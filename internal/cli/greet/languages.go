@@ -0,0 +1,21 @@
+package greet
+
+import (
+	"strings"
+
+	"github.com/go-cli-template/hello-world-cli/internal/greeting"
+)
+
+// languageList is the --list-languages result
+type languageList []greeting.LanguageInfo
+
+// String renders the list for text output
+func (l languageList) String() string {
+	var b strings.Builder
+	b.WriteString("Supported languages:")
+	for _, lang := range l {
+		b.WriteString("\n  ")
+		b.WriteString(lang.Code)
+	}
+	return b.String()
+}
@@ -1,18 +1,20 @@
 package hello
 
 import (
-	"encoding/json"
 	"fmt"
 
+	"github.com/go-cli-template/hello-world-cli/internal/cli/timeflags"
+	appconfig "github.com/go-cli-template/hello-world-cli/internal/config"
 	"github.com/go-cli-template/hello-world-cli/internal/greeting"
 	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 // Options holds command options
 type Options struct {
 	IncludeEmoji bool
-	JSONOutput   bool
+	Time         timeflags.Options
 }
 
 // NewCommand creates the hello command
@@ -22,7 +24,7 @@ func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "hello",
 		Short: "Print a hello world message",
-		Long: `Print a hello world message with optional emoji and JSON output.
+		Long: `Print a hello world message with optional emoji, in any output format.
 
 This command demonstrates a simple greeting without personalization.`,
 		Example: `  # Basic hello
@@ -31,8 +33,11 @@ This command demonstrates a simple greeting without personalization.`,
   # With emoji
   hello-world-cli hello --emoji
   
+  # Hello for a given local time
+  hello-world-cli hello --at 2024-06-01T07:30:00Z --tz America/New_York
+  
   # JSON output
-  hello-world-cli hello --json`,
+  hello-world-cli hello -o json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runHello(cmd, opts)
 		},
@@ -40,7 +45,8 @@ This command demonstrates a simple greeting without personalization.`,
 
 	// Add flags
 	cmd.Flags().BoolVar(&opts.IncludeEmoji, "emoji", false, "Include emoji in greeting")
-	cmd.Flags().BoolVar(&opts.JSONOutput, "json", false, "Output in JSON format")
+	opts.Time.AddFlags(cmd.Flags())
+	output.AddLegacyJSONFlag(cmd)
 
 	return cmd
 }
@@ -51,29 +57,39 @@ func runHello(cmd *cobra.Command, opts *Options) error {
 	// Log command execution
 	log.Debug("executing hello command",
 		"emoji", opts.IncludeEmoji,
-		"json", opts.JSONOutput,
 	)
 
+	printer, err := output.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Use the configured emoji preference unless --emoji is given
+	if !cmd.Flags().Changed("emoji") {
+		cfg, err := appconfig.FromContext(cmd.Context()).Expand("greeting.emoji")
+		if err != nil {
+			return err
+		}
+		opts.IncludeEmoji = cfg.Greeting.Emoji
+	}
+
 	// Create greeting options
 	greetOpts := greeting.Options{
 		Language:     "en",
 		IncludeEmoji: opts.IncludeEmoji,
 	}
+	if err := opts.Time.Apply(&greetOpts); err != nil {
+		return err
+	}
 
 	// Generate greeting
 	greet := greeting.Generate(greetOpts)
 	log.Debug("generated greeting", "message", greet.Message)
 
-	// Output based on format
-	if opts.JSONOutput {
-		output, err := json.MarshalIndent(greet, "", "  ")
-		if err != nil {
-			log.Error("failed to format JSON", "error", err)
-			return fmt.Errorf("failed to format JSON: %w", err)
-		}
-		cmd.Println(string(output))
-	} else {
-		cmd.Println(greet.Message)
+	// Output in the selected format
+	if err := printer.Print(cmd.OutOrStdout(), greet); err != nil {
+		log.Error("failed to write output", "error", err)
+		return fmt.Errorf("failed to write output: %w", err)
 	}
 
 	return nil
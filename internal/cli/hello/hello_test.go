@@ -6,6 +6,9 @@ import (
 	"testing"
 )
 
+// night is a fixed time at which English uses its plain hello
+const night = "2026-03-10T23:00:00Z"
+
 func TestHelloCommand(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -15,12 +18,12 @@ func TestHelloCommand(t *testing.T) {
 	}{
 		{
 			name:       "basic hello",
-			args:       []string{},
+			args:       []string{"--at", night},
 			wantOutput: "Hello, World!",
 		},
 		{
 			name:       "hello with emoji",
-			args:       []string{"--emoji"},
+			args:       []string{"--emoji", "--at", night},
 			wantOutput: "👋 Hello, World!",
 		},
 		{
@@ -28,6 +31,21 @@ func TestHelloCommand(t *testing.T) {
 			args:       []string{"--json"},
 			wantOutput: `"message"`,
 		},
+		{
+			name:       "time of day in time zone",
+			args:       []string{"--at", night, "--tz", "America/New_York"},
+			wantOutput: "Good evening, World!",
+		},
+		{
+			name:       "holiday from builtin calendar",
+			args:       []string{"--at", "2026-12-25T09:00:00Z", "--calendar", "builtin"},
+			wantOutput: "Merry Christmas, World!",
+		},
+		{
+			name:    "invalid time",
+			args:    []string{"--at", "tomorrow"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,214 @@
+package initcmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	appconfig "github.com/go-cli-template/hello-world-cli/internal/config"
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// Options holds command options
+type Options struct {
+	NonInteractive bool
+	Force          bool
+	Language       string
+	IncludeEmoji   bool
+}
+
+// question asks for the value of a config key
+type question struct {
+	key    string
+	prompt string
+}
+
+// questions are asked in order by the interactive setup
+var questions = []question{
+	{key: "log.level", prompt: "Log level (debug, info, warn, error)"},
+	{key: "log.format", prompt: "Log format (text, json)"},
+	{key: "greeting.language", prompt: "Default language, such as en, es or pt-BR"},
+	{key: "greeting.emoji", prompt: "Include emoji in greetings (yes, no)"},
+}
+
+// NewCommand creates the init command
+func NewCommand() *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a configuration file",
+		Long: `Create a commented configuration file covering logging and greeting
+preferences.
+
+The file is written to $XDG_CONFIG_HOME/hello-world-cli/config.yaml
+(~/.config/hello-world-cli/config.yaml when XDG_CONFIG_HOME is unset), or to
+the path given by --config. An existing file is only replaced with --force.`,
+		Example: `  # Answer a few questions
+  hello-world-cli init
+
+  # Write a config file for CI without prompting
+  hello-world-cli init --non-interactive --log-format json --lang en
+
+  # Replace an existing config file
+  hello-world-cli init --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInit(cmd, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.NonInteractive, "non-interactive", false, "Use flag values and defaults without prompting")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Overwrite an existing config file")
+	cmd.Flags().StringVarP(&opts.Language, "lang", "l", "", "Default greeting language")
+	cmd.Flags().BoolVar(&opts.IncludeEmoji, "emoji", false, "Include emoji in greetings by default")
+
+	return cmd
+}
+
+func runInit(cmd *cobra.Command, opts *Options) error {
+	log := logger.FromContext(cmd.Context())
+	cmd.SilenceUsage = true
+
+	path, err := targetPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to replace a config file before asking anything
+	if _, err := os.Stat(path); err == nil && !opts.Force {
+		return &errors.FileError{Path: path, Operation: "create", Err: fs.ErrExist}
+	}
+
+	answers, err := defaults(cmd, opts)
+	if err != nil {
+		return err
+	}
+	if !opts.NonInteractive {
+		if err := ask(cmd.InOrStdin(), cmd.OutOrStdout(), answers); err != nil {
+			return err
+		}
+	}
+
+	values := make(map[string]any, len(answers))
+	for key, raw := range answers {
+		if raw == "" {
+			continue
+		}
+		value, err := appconfig.ParseValue(key, raw)
+		if err != nil {
+			return err
+		}
+		values[key] = value
+	}
+
+	file, err := appconfig.Scaffold(path, values)
+	if err != nil {
+		return err
+	}
+	if err := file.Save(); err != nil {
+		return err
+	}
+
+	log.Debug("wrote config file", "path", path, "values", values)
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Wrote configuration to %s\n", path)
+	return err
+}
+
+// targetPath returns the --config path or the XDG config file
+func targetPath(cmd *cobra.Command) (string, error) {
+	if f := cmd.Root().PersistentFlags().Lookup("config"); f != nil && f.Value.String() != "" {
+		return f.Value.String(), nil
+	}
+	return appconfig.UserPath()
+}
+
+// defaults returns the starting answers from the flags and the defaults
+// of the keys
+func defaults(cmd *cobra.Command, opts *Options) (map[string]string, error) {
+	answers := map[string]string{
+		"greeting.language": opts.Language,
+		"greeting.emoji":    strconv.FormatBool(opts.IncludeEmoji),
+	}
+	for _, key := range []string{"log.level", "log.format"} {
+		k, _ := appconfig.Lookup(key)
+		answers[key] = fmt.Sprint(k.Default)
+		if f := cmd.Root().PersistentFlags().Lookup(k.Flag); f != nil && f.Changed {
+			answers[key] = f.Value.String()
+		}
+	}
+
+	// Report bad flag values before prompting
+	for key, raw := range answers {
+		if _, err := appconfig.ParseValue(key, raw); raw != "" && err != nil {
+			return nil, err
+		}
+	}
+	return answers, nil
+}
+
+// ask prompts for each answer, keeping the current value when the reply is
+// empty and asking again when it is invalid. The remaining answers keep
+// their values when the input ends.
+func ask(in io.Reader, out io.Writer, answers map[string]string) error {
+	scanner := bufio.NewScanner(in)
+	for _, q := range questions {
+		for {
+			if _, err := fmt.Fprintf(out, "%s [%s]: ", q.prompt, display(answers[q.key])); err != nil {
+				return err
+			}
+			if !scanner.Scan() {
+				_, _ = fmt.Fprintln(out)
+				return scanner.Err()
+			}
+
+			reply := strings.TrimSpace(scanner.Text())
+			if reply == "" {
+				break
+			}
+			if value, err := parseReply(q.key, reply); err != nil {
+				_, _ = fmt.Fprintf(out, "  %s\n", errors.Message(err))
+			} else {
+				answers[q.key] = value
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseReply checks a reply, accepting yes and no for boolean keys
+func parseReply(key, reply string) (string, error) {
+	if k, ok := appconfig.Lookup(key); ok && k.Type == appconfig.TypeBool {
+		switch strings.ToLower(reply) {
+		case "y", "yes":
+			reply = "true"
+		case "n", "no":
+			reply = "false"
+		}
+	}
+	if _, err := appconfig.ParseValue(key, reply); err != nil {
+		return "", err
+	}
+	return reply, nil
+}
+
+// display renders an answer in a prompt
+func display(value string) string {
+	switch value {
+	case "":
+		return "locale"
+	case "true":
+		return "yes"
+	case "false":
+		return "no"
+	default:
+		return value
+	}
+}
@@ -0,0 +1,131 @@
+package initcmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// executeInit runs init under a root with the global flags it reads
+func executeInit(input string, args ...string) (string, error) {
+	root := &cobra.Command{Use: "hello-world-cli"}
+	root.PersistentFlags().String("config", "", "")
+	root.PersistentFlags().String("log-level", "", "")
+	root.PersistentFlags().String("log-format", "", "")
+	root.AddCommand(NewCommand())
+
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetErr(buf)
+	root.SetIn(strings.NewReader(input))
+	root.SetArgs(append([]string{"init"}, args...))
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestInitCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		input    string
+		args     []string
+		want     []string
+		wantExit errors.ExitCode
+		wantFile string
+	}{
+		{
+			name: "non-interactive defaults",
+			args: []string{"--non-interactive"},
+			want: []string{"  format: text\n", "  level: info\n", "  emoji: false\n"},
+		},
+		{
+			name: "non-interactive flags",
+			args: []string{"--non-interactive", "--log-format", "json", "--lang", "es", "--emoji"},
+			want: []string{"  format: json\n", "  language: es\n", "  emoji: true\n"},
+		},
+		{
+			name:  "interactive answers",
+			input: "warn\nloud\njson\npt-BR\ny\n",
+			want:  []string{"  level: warn\n", "  format: json\n", "  language: pt-BR\n", "  emoji: true\n"},
+		},
+		{
+			name:  "empty answers keep defaults",
+			input: "\n\n\n\n",
+			args:  []string{"--lang", "de"},
+			want:  []string{"  level: info\n", "  language: de\n"},
+		},
+		{
+			name:     "invalid flag value",
+			args:     []string{"--non-interactive", "--lang", "not a tag"},
+			wantExit: errors.ExitConfig,
+		},
+		{
+			name:     "refuses to overwrite",
+			existing: "verbose: true\n",
+			args:     []string{"--non-interactive"},
+			wantExit: errors.ExitCantCreate,
+			wantFile: "verbose: true\n",
+		},
+		{
+			name:     "force overwrites",
+			existing: "verbose: true\n",
+			args:     []string{"--non-interactive", "--force"},
+			want:     []string{"  level: info\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "conf", "config.yaml")
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.existing), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			out, err := executeInit(tt.input, append([]string{"--config", path}, tt.args...)...)
+			if got := errors.GetExitCode(err); got != tt.wantExit {
+				t.Fatalf("exit code = %v, want %v (error %v, output %q)", got, tt.wantExit, err, out)
+			}
+
+			data, readErr := os.ReadFile(path)
+			if tt.wantFile != "" && string(data) != tt.wantFile {
+				t.Errorf("file = %q, want %q", data, tt.wantFile)
+			}
+			if err != nil {
+				return
+			}
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			if !strings.Contains(out, "Wrote configuration to "+path) {
+				t.Errorf("output = %q, want the config path", out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("file = %q, want substring %q", data, want)
+				}
+			}
+		})
+	}
+}
+
+func TestInitXDGPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	if _, err := executeInit("", "--non-interactive"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "hello-world-cli", "config.yaml")); err != nil {
+		t.Errorf("config file not written to XDG_CONFIG_HOME: %v", err)
+	}
+}
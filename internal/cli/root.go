@@ -1,26 +1,73 @@
 package cli
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+	"time"
 
+	configcmd "github.com/go-cli-template/hello-world-cli/internal/cli/config"
 	"github.com/go-cli-template/hello-world-cli/internal/cli/greet"
 	"github.com/go-cli-template/hello-world-cli/internal/cli/hello"
+	"github.com/go-cli-template/hello-world-cli/internal/cli/initcmd"
+	"github.com/go-cli-template/hello-world-cli/internal/cli/serve"
+	"github.com/go-cli-template/hello-world-cli/internal/cli/servegrpc"
+	"github.com/go-cli-template/hello-world-cli/internal/cli/shell"
 	versioncmd "github.com/go-cli-template/hello-world-cli/internal/cli/version"
+	appconfig "github.com/go-cli-template/hello-world-cli/internal/config"
+	"github.com/go-cli-template/hello-world-cli/internal/greeting"
 	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/internal/output"
+	"github.com/go-cli-template/hello-world-cli/internal/telemetry"
 	"github.com/go-cli-template/hello-world-cli/pkg/version"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
-	cfgFile   string
-	verbose   bool
-	debug     bool
-	logLevel  string
-	logFormat string
+	cfgFile       string
+	profile       string
+	verbose       bool
+	debug         bool
+	logLevel      string
+	logFormat     string
+	logOutput     string
+	localesDir    string
+	explainConfig bool
+
+	// configFiles are the config files read by initConfig, lowest
+	// precedence first, out of configLocations consulted from configDir
+	configFiles     appconfig.Layers
+	configLocations []appconfig.Location
+	configDir       string
+
+	// configErr is a problem found by initConfig, reported before any
+	// command runs
+	configErr error
+
+	// redactor hides sensitive values in logs and error details, from
+	// log.redact once the configuration is resolved
+	redactor = logger.DefaultRedactor()
+
+	// configStart is when initConfig began reading the config files, the
+	// start of the command span
+	configStart time.Time
+
+	// shutdownTelemetry flushes the traces and metrics of the command,
+	// once telemetry is set up
+	shutdownTelemetry func(context.Context) error
 )
 
+// telemetryFlushTimeout bounds the time spent exporting telemetry on exit
+const telemetryFlushTimeout = 5 * time.Second
+
 // TODO: Replace "hello-world-cli" with your application name throughout this file
 
 var rootCmd = &cobra.Command{
@@ -34,53 +81,91 @@ This CLI showcases:
 - Structured logging with slog
 - Multiple commands with sub-commands
 - Internationalization support
-- JSON output formatting
+- Consistent output formats (text, json, yaml, table, csv, ...)
 - Comprehensive testing approach`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// Configure logger based on viper configuration and flags
-		cfg := logger.DefaultConfig()
-
-		// Read from viper configuration first
-		if viper.IsSet("log.level") {
-			cfg.Level = viper.GetString("log.level")
+		// Commands run from the shell reuse the logger and catalog set up
+		// when the shell started
+		if shell.InSession(cmd.Context()) {
+			cmd.SetContext(withRunFields(cmd.Context(), cmd))
+			return nil
 		}
-		if viper.IsSet("log.format") {
-			cfg.Format = viper.GetString("log.format")
+		if configErr != nil {
+			cmd.SilenceUsage = true
+			return configErr
 		}
 
-		// Command line flags override config file
-		if debug {
-			cfg.Level = "debug"
-		} else if logLevel != "" {
-			cfg.Level = logLevel
+		// Resolve every setting from flags, environment, profile, config
+		// files and defaults, in that order
+		flags := cmd.Root().PersistentFlags()
+		src := configSources(flags, configFiles)
+		if explainConfig {
+			explanations, err := appconfig.Explain(src)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			writeExplanations(cmd.ErrOrStderr(), explanations)
 		}
-
-		if logFormat != "" {
-			cfg.Format = logFormat
+		cfg, err := resolveConfig(src)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
 		}
-
-		// Environment variables for logging
-		if envLevel := os.Getenv("LOG_LEVEL"); envLevel != "" {
-			cfg.Level = envLevel
+		configEnd := time.Now()
+		if verbose && src.Profile != "" {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Using config profile:", src.Profile)
 		}
-		if envFormat := os.Getenv("LOG_FORMAT"); envFormat != "" {
-			cfg.Format = envFormat
+
+		// Create and set the logger; long-running commands reload it when
+		// the config files change
+		logCfg := loggerConfig(cfg)
+		redactor = logCfg.Redactor
+		log, reloader := logger.NewReloadable(logCfg)
+		loggerEnd := time.Now()
+
+		// Trace the invocation from the start of config loading; the
+		// span ends in Execute
+		if shutdown, err := telemetry.Setup(cmd.Context(), telemetryConfig(cfg, cmd.Root().Name()), log); err != nil {
+			log.Warn("telemetry is disabled", "error", err)
+		} else {
+			shutdownTelemetry = shutdown
 		}
+		ctx, _ := telemetry.StartCommand(cmd.Context(), cmd.CommandPath(), trace.WithTimestamp(configStart))
+		telemetry.RecordSpan(ctx, "config.load", configStart, configEnd)
+		telemetry.RecordSpan(ctx, "logger.setup", configEnd, loggerEnd)
 
-		// Create and set the logger
-		log := logger.New(cfg)
+		// Every line logged while the command runs carries the run
+		// fields, including those of the default logger such as the
+		// error ending the command. Commands log as the cli component
+		// unless they name another; long-running ones can change the
+		// levels while they run.
+		ctx = logger.WithContext(withRunFields(ctx, cmd), log)
+		ctx = logger.WithLevels(ctx, reloader.Levels())
+		watcherLog := logger.FromContext(logger.WithComponent(ctx, "config"))
+		ctx = logger.WithComponent(ctx, "cli")
+		log = logger.FromContext(ctx)
 		logger.SetDefault(log)
 
-		// Add logger to context
-		ctx := logger.WithContext(cmd.Context(), log)
+		watcher := newConfigWatcher(cfg, flags, watcherLog, reloader)
+		ctx = appconfig.WithWatcher(ctx, watcher)
 		cmd.SetContext(ctx)
 
+		// Merge user locale files over the embedded greeting catalog for
+		// the commands rendering greetings; others run whatever the
+		// state of the locale files
+		if rendersGreetings(cmd) {
+			if err := loadLocales(log, cfg); err != nil {
+				return err
+			}
+		}
+
 		// Log startup information at debug level
 		log.Debug("starting hello-world-cli",
 			"version", version.Version,
 			"args", os.Args[1:],
-			"log_level", cfg.Level,
-			"log_format", cfg.Format,
+			"log_level", logCfg.Level,
+			"log_format", logCfg.Format,
 		)
 
 		return nil
@@ -90,7 +175,26 @@ This CLI showcases:
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() error {
-	return rootCmd.Execute()
+	// Let rotated log files finish compressing
+	defer func() { _ = logger.Close() }()
+
+	cmd, err := rootCmd.ExecuteC()
+	telemetry.EndCommand(cmd.Context(), cmd.CommandPath(), err)
+	flushTelemetry()
+	return err
+}
+
+// flushTelemetry exports the remaining traces and metrics, giving up after
+// telemetryFlushTimeout
+func flushTelemetry() {
+	if shutdownTelemetry == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryFlushTimeout)
+	defer cancel()
+	if err := shutdownTelemetry(ctx); err != nil {
+		logger.Warn("failed to export telemetry", "error", err)
+	}
 }
 
 // IsDebug returns whether debug mode is enabled
@@ -98,61 +202,295 @@ func IsDebug() bool {
 	return debug
 }
 
+// Redactor returns the redactor of the configured log.redact rules
+func Redactor() *logger.Redactor {
+	return redactor
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Add commands
 	rootCmd.AddCommand(hello.NewCommand())
 	rootCmd.AddCommand(greet.NewCommand())
+	rootCmd.AddCommand(configcmd.NewCommand())
+	rootCmd.AddCommand(initcmd.NewCommand())
+	rootCmd.AddCommand(serve.NewCommand())
+	rootCmd.AddCommand(servegrpc.NewCommand())
+	rootCmd.AddCommand(shell.NewCommand())
 	rootCmd.AddCommand(versioncmd.NewCommand())
 
 	// Persistent flags - global for all subcommands
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.hello-world-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file to read instead of the system, user and project files")
+	rootCmd.PersistentFlags().StringVar(&profile, appconfig.ProfileFlag, "", "config profile to apply (default from $HELLO_WORLD_CLI_PROFILE)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug logging (includes file:line info)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "set log level (debug, info, warn, error)")
-	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "set log format (text, json)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "set log format (text, json, color, logfmt)")
+	rootCmd.PersistentFlags().StringVar(&logOutput, "log-output", "", "write logs to stderr, stdout, a file path or a file:// URL")
+	output.AddFlag(rootCmd.PersistentFlags())
+	rootCmd.PersistentFlags().StringVar(&localesDir, "locales-dir", "", "additional directory of greeting locale files (yaml, json, toml)")
+	rootCmd.PersistentFlags().BoolVar(&explainConfig, "explain-config", false, "print every source of each setting and which one wins")
+
+	// The shell sets up configuration and logging once for the session
+	shell.MarkFixed(rootCmd.PersistentFlags(), "config", appconfig.ProfileFlag, "verbose", "debug", "log-level", "log-format", "log-output", "locales-dir", "explain-config")
 
-	// Bind flags to viper
-	if err := viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose")); err != nil {
-		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	// Add version info
+	rootCmd.Version = version.String()
+}
+
+// initConfig reads the config files. Settings are resolved from them,
+// the environment and flags before each command runs.
+func initConfig() {
+	configStart = time.Now()
+
+	// The file given with --config is read alone; otherwise the system,
+	// user, legacy and project files are merged in that order
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = ""
+	}
+	layers, locations, err := appconfig.Load(cfgFile, dir)
+	if err != nil {
+		configErr = err
+		return
 	}
-	if err := viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug")); err != nil {
-		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	for _, loc := range locations {
+		if loc.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping config file %s: %s\n", loc.Path, loc.Err.Error())
+		} else if verbose {
+			fmt.Fprintf(os.Stderr, "Config file (%s) %s: %s\n", loc.Scope, loc.Path, loc.Status())
+		}
 	}
-	if err := viper.BindPFlag("log.level", rootCmd.PersistentFlags().Lookup("log-level")); err != nil {
-		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	configFiles, configLocations, configDir = layers, locations, dir
+}
+
+// writeExplanations prints the candidate values of every setting, the
+// effective one first
+func writeExplanations(w io.Writer, explanations []appconfig.Explanation) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE\tORIGIN")
+	for _, e := range explanations {
+		for i, c := range e.Candidates {
+			key := e.Key
+			if i > 0 {
+				key = "  (overridden)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", key, appconfig.FormatValue(c.Value), c.Source, c.Origin)
+		}
 	}
-	if err := viper.BindPFlag("log.format", rootCmd.PersistentFlags().Lookup("log-format")); err != nil {
-		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	_ = tw.Flush()
+}
+
+// configSources returns the sources settings are resolved from
+func configSources(flags *pflag.FlagSet, layers appconfig.Layers) appconfig.Sources {
+	return appconfig.Sources{
+		Flags:     flags,
+		LookupEnv: os.LookupEnv,
+		Files:     layers,
+		Profile:   appconfig.ProfileName(flags, os.LookupEnv),
 	}
+}
 
-	// Add version info
-	rootCmd.Version = version.String()
+// startupKeys are the settings read before every command runs. The
+// secret references of other keys are resolved by the commands reading
+// them.
+var startupKeys = []string{"debug", "verbose", "log", "telemetry"}
+
+// resolveConfig resolves every setting and checks the values, expanding
+// the references of startupKeys
+func resolveConfig(src appconfig.Sources) (*appconfig.Config, error) {
+	settings, err := appconfig.Resolve(src)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := appconfig.FromSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Expand(startupKeys...)
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
+// withRunFields returns a context whose log lines carry a run ID unique
+// to this invocation of cmd, its command path and the IDs of its span
+func withRunFields(ctx context.Context, cmd *cobra.Command) context.Context {
+	fields := []any{"run_id", newRunID(), "command", cmd.CommandPath()}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		fields = append(fields, "trace_id", span.TraceID().String(), "span_id", span.SpanID().String())
+	}
+	return logger.WithFields(ctx, fields...)
+}
+
+// newRunID returns a random ID of 16 hex digits
+func newRunID() string {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
 
-		// Search config in home directory
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".hello-world-cli") // TODO: Replace with your app name
+// loggerConfig returns the logger configuration for cfg
+func loggerConfig(cfg *appconfig.Config) logger.Config {
+	logCfg := logger.DefaultConfig()
+	logCfg.Level = cfg.Log.Level
+	logCfg.Format = cfg.Log.Format
+	logCfg.Output = cfg.Log.Output
+	logCfg.Levels = cfg.Log.Levels
+	if cfg.Debug {
+		// Debug logging covers every component
+		logCfg.Level = "debug"
+		logCfg.Levels = nil
 	}
 
-	// Set environment variable prefix
-	viper.SetEnvPrefix("HELLO_WORLD_CLI") // TODO: Replace with your app name in uppercase
-	viper.AutomaticEnv()                  // read in environment variables that match
+	logCfg.Rotation = logRotation(cfg.Log.MaxSize, cfg.Log.MaxAge, cfg.Log.RotateInterval, cfg.Log.MaxBackups, cfg.Log.Compress)
+	logCfg.Redactor = logRedactor(cfg.Log.Redact)
+	logCfg.Sampling = logger.Sampling{
+		First:      cfg.Log.Sampling.First,
+		Thereafter: cfg.Log.Sampling.Thereafter,
+		Rate:       cfg.Log.Sampling.Rate,
+		Burst:      cfg.Log.Sampling.Burst,
+	}
+	if cfg.Log.Sampling.Interval != "" {
+		logCfg.Sampling.Interval, _ = logger.ParseDuration(cfg.Log.Sampling.Interval)
+	}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil && verbose {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	for _, sink := range cfg.Log.Sinks {
+		logCfg.Sinks = append(logCfg.Sinks, logger.Sink{
+			Level:    sink.Level,
+			Format:   sink.Format,
+			Output:   sink.Output,
+			Rotation: logRotation(sink.MaxSize, sink.MaxAge, sink.RotateInterval, sink.MaxBackups, sink.Compress),
+		})
 	}
+	return logCfg
+}
+
+// logRotation returns the rotation of a log file from its settings,
+// which were checked when the configuration was resolved
+func logRotation(maxSize, maxAge, interval string, maxBackups int, compress bool) logger.Rotation {
+	rotation := logger.Rotation{MaxBackups: maxBackups, Compress: compress}
+	rotation.MaxSize, _ = logger.ParseSize(maxSize)
+	if maxAge != "" {
+		rotation.MaxAge, _ = logger.ParseDuration(maxAge)
+	}
+	if interval != "" {
+		rotation.Interval, _ = logger.ParseDuration(interval)
+	}
+	return rotation
+}
+
+// telemetryConfig returns the telemetry configuration for cfg, reporting
+// as the service name
+func telemetryConfig(cfg *appconfig.Config, name string) telemetry.Config {
+	return telemetry.Config{
+		Exporter:       cfg.Telemetry.Exporter,
+		Endpoint:       cfg.Telemetry.Endpoint,
+		Protocol:       cfg.Telemetry.Protocol,
+		Insecure:       cfg.Telemetry.Insecure,
+		File:           cfg.Telemetry.File,
+		ServiceName:    name,
+		ServiceVersion: version.Version,
+	}
+}
+
+// logRedactor returns the redactor applying rules, which were checked
+// when the configuration was resolved
+func logRedactor(rules []appconfig.RedactConfig) *logger.Redactor {
+	redactRules := make([]logger.RedactRule, 0, len(rules))
+	for _, rule := range rules {
+		redactRules = append(redactRules, logger.RedactRule{Key: rule.Key, Value: rule.Value, Action: rule.Action})
+	}
+	r, err := logger.NewRedactor(redactRules)
+	if err != nil {
+		return logger.DefaultRedactor()
+	}
+	return r
+}
+
+// newConfigWatcher returns a watcher that re-reads the config files
+// consulted at startup. Changes are logged and applied to the logger and
+// greeting catalog; a file that fails to parse or holds invalid values
+// leaves the last good configuration in effect.
+func newConfigWatcher(cfg *appconfig.Config, flags *pflag.FlagSet, log logger.Logger, reloader *logger.Reloader) *appconfig.Watcher {
+	paths := make([]string, 0, len(configLocations))
+	for _, loc := range configLocations {
+		paths = append(paths, loc.Path)
+	}
+
+	dir := configDir
+	watcher := appconfig.NewWatcher(cfg, paths, func() (*appconfig.Config, error) {
+		layers, locations, err := appconfig.Load(cfgFile, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, loc := range locations {
+			if loc.Err != nil {
+				return nil, loc.Err
+			}
+		}
+		return resolveConfig(configSources(flags, layers))
+	})
+
+	watcher.OnChange(func(old, cur *appconfig.Config, changes []appconfig.Change) {
+		fields := make([]any, 0, 2*len(changes))
+		for _, c := range changes {
+			fields = append(fields, c.Key, fmt.Sprintf("%#v -> %#v", c.Old, c.New))
+		}
+		log.Info("configuration reloaded", fields...)
+
+		if err := reloader.Reload(loggerConfig(cur)); err != nil {
+			log.Error("cannot apply log settings", "error", err)
+		}
+		if slices.ContainsFunc(changes, func(c appconfig.Change) bool { return c.Key == "locales.dir" }) {
+			if err := loadLocales(log, cur); err != nil {
+				log.Error("cannot reload greeting locales", "error", err)
+			}
+		}
+	})
+	watcher.OnError(func(err error) {
+		log.Error("configuration reload failed, keeping the previous configuration", "error", err)
+	})
+	return watcher
+}
+
+// catalogCommands are the commands rendering greetings, by the name of
+// the top-level command. Only they load the greeting catalog.
+var catalogCommands = map[string]bool{"hello": true, "greet": true, "serve": true, "serve-grpc": true, "shell": true}
+
+// rendersGreetings reports whether cmd renders greetings
+func rendersGreetings(cmd *cobra.Command) bool {
+	for ; cmd.HasParent(); cmd = cmd.Parent() {
+		if !cmd.Parent().HasParent() {
+			return catalogCommands[cmd.Name()]
+		}
+	}
+	return false
+}
+
+// loadLocales builds the greeting catalog from the embedded defaults, the
+// locales directory in the user config directory and the locales.dir of
+// cfg, in that order.
+func loadLocales(log logger.Logger, cfg *appconfig.Config) error {
+	cfg, err := cfg.Expand("locales")
+	if err != nil {
+		return err
+	}
+	var dirs []string
+	if configDir, err := appconfig.UserDir(); err == nil {
+		dirs = append(dirs, filepath.Join(configDir, "locales"))
+	}
+	if cfg.Locales.Dir != "" {
+		dirs = append(dirs, cfg.Locales.Dir)
+	}
+
+	catalog, err := greeting.LoadCatalog(dirs...)
+	if err != nil {
+		return err
+	}
+	greeting.SetDefaultCatalog(catalog)
+
+	log.Debug("loaded greeting catalog",
+		"languages", catalog.Languages(),
+		"dirs", dirs,
+	)
+	return nil
 }
@@ -0,0 +1,124 @@
+package serve
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-cli-template/hello-world-cli/internal/cli/timeflags"
+	appconfig "github.com/go-cli-template/hello-world-cli/internal/config"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// Options holds command options
+type Options struct {
+	Addr            string
+	ShutdownTimeout time.Duration
+	Fallbacks       []string
+	Calendar        string
+	Admin           bool
+	AdminAddr       string
+}
+
+// NewCommand creates the serve command
+func NewCommand() *cobra.Command {
+	defaults := server.DefaultConfig()
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve greetings over HTTP",
+		Long: `Start an HTTP server exposing the greeting engine as a JSON API.
+
+Endpoints:
+  GET /v1/greet?name=&lang=&emoji=&gender=
+                                    Generate a greeting (repeat name for a group)
+  GET /v1/languages                 List supported languages
+  GET /version                      Build information
+  GET /healthz                      Liveness probe
+  GET /readyz                       Readiness probe
+  GET, PUT /admin/log-level         Read or change log levels (with --admin,
+                                    served on --admin-addr only)
+
+When no lang parameter is given, the language is negotiated from the
+Accept-Language header. Errors are returned as JSON bodies with an error
+code and message. The server shuts down gracefully on SIGINT or SIGTERM,
+and switches debug logging on and off on SIGUSR1.`,
+		Example: `  # Listen on the default address
+  hello-world-cli serve
+
+  # Listen on a custom port
+  hello-world-cli serve --addr :9090
+
+  # Query the API
+  curl -H 'Accept-Language: fr-CA' 'localhost:8080/v1/greet?name=Ana'
+
+  # Log requests at debug while serving
+  hello-world-cli serve --admin
+  curl -X PUT -d '{"components":{"server":"debug"}}' localhost:8081/admin/log-level`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(cmd, opts)
+		},
+	}
+
+	// Add flags
+	cmd.Flags().StringVar(&opts.Addr, "addr", defaults.Addr, "Address to listen on")
+	cmd.Flags().DurationVar(&opts.ShutdownTimeout, "shutdown-timeout", defaults.ShutdownTimeout, "Grace period for in-flight requests on shutdown")
+	cmd.Flags().StringSliceVar(&opts.Fallbacks, "fallback", nil, "Fallback languages to try when negotiation fails (comma-separated)")
+	cmd.Flags().StringVar(&opts.Calendar, "calendar", "", `Holiday calendar: "builtin" or a YAML, JSON or TOML file of dates`)
+	cmd.Flags().BoolVar(&opts.Admin, "admin", false, "Serve /admin/log-level on --admin-addr to read and change log levels")
+	cmd.Flags().StringVar(&opts.AdminAddr, "admin-addr", defaults.AdminAddr, "Address the admin API listens on; it has no authentication, so anyone who can reach it can use it")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, opts *Options) error {
+	log := logger.FromContext(cmd.Context())
+
+	log.Debug("executing serve command",
+		"addr", opts.Addr,
+		"shutdown_timeout", opts.ShutdownTimeout,
+		"fallbacks", opts.Fallbacks,
+		"calendar", opts.Calendar,
+		"admin", opts.Admin,
+		"admin_addr", opts.AdminAddr,
+	)
+
+	cfg := server.DefaultConfig()
+	cfg.Addr = opts.Addr
+	cfg.AdminAddr = opts.AdminAddr
+	cfg.ShutdownTimeout = opts.ShutdownTimeout
+	cfg.Fallbacks = opts.Fallbacks
+
+	calendar, err := timeflags.LoadCalendar(opts.Calendar)
+	if err != nil {
+		return err
+	}
+	cfg.Calendar = calendar
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Apply config file changes to the log settings and default language
+	// while serving
+	if _, err := appconfig.FromContext(ctx).Expand("greeting"); err != nil {
+		return err
+	}
+	cfg.DefaultLanguage = appconfig.DefaultLanguage(ctx, log)
+	appconfig.StartWatching(ctx)
+
+	// Log levels change through SIGUSR1 and, if enabled, the admin API
+	if levels := logger.LevelsFromContext(ctx); levels != nil {
+		logger.ToggleDebugOnSignal(ctx, levels, log)
+		if opts.Admin {
+			cfg.Levels = levels
+		}
+	}
+
+	cmd.SilenceUsage = true
+	return server.New(cfg, logger.FromContext(logger.WithComponent(ctx, "server"))).Run(ctx)
+}
@@ -0,0 +1,105 @@
+package servegrpc
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-cli-template/hello-world-cli/internal/cli/timeflags"
+	appconfig "github.com/go-cli-template/hello-world-cli/internal/config"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+// Options holds command options
+type Options struct {
+	Addr            string
+	ShutdownTimeout time.Duration
+	Fallbacks       []string
+	Calendar        string
+}
+
+// NewCommand creates the serve-grpc command
+func NewCommand() *cobra.Command {
+	defaults := rpc.DefaultConfig()
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "serve-grpc",
+		Short: "Serve greetings over gRPC",
+		Long: `Start a gRPC server hosting greeting.v1.GreetingService.
+
+RPCs:
+  Generate       Generate a greeting
+  ListLanguages  List supported languages
+  BatchGenerate  Stream requests in, stream one greeting (or error) per request out
+
+The standard gRPC health service and server reflection are also registered.
+Errors carry the matching gRPC status code and a google.rpc.ErrorInfo detail
+whose reason is the application error code. The server shuts down gracefully
+on SIGINT or SIGTERM, and switches debug logging on and off on SIGUSR1.`,
+		Example: `  # Listen on the default address
+  hello-world-cli serve-grpc
+
+  # Listen on a custom port
+  hello-world-cli serve-grpc --addr :50051
+
+  # Call the service with grpcurl
+  grpcurl -plaintext -d '{"options":{"name":"Ana","language":"es"}}' \
+    localhost:9090 greeting.v1.GreetingService/Generate`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServeGRPC(cmd, opts)
+		},
+	}
+
+	// Add flags
+	cmd.Flags().StringVar(&opts.Addr, "addr", defaults.Addr, "Address to listen on")
+	cmd.Flags().DurationVar(&opts.ShutdownTimeout, "shutdown-timeout", defaults.ShutdownTimeout, "Grace period for in-flight RPCs on shutdown")
+	cmd.Flags().StringSliceVar(&opts.Fallbacks, "fallback", nil, "Fallback languages for requests that name none (comma-separated)")
+	cmd.Flags().StringVar(&opts.Calendar, "calendar", "", `Holiday calendar: "builtin" or a YAML, JSON or TOML file of dates`)
+
+	return cmd
+}
+
+func runServeGRPC(cmd *cobra.Command, opts *Options) error {
+	log := logger.FromContext(cmd.Context())
+
+	log.Debug("executing serve-grpc command",
+		"addr", opts.Addr,
+		"shutdown_timeout", opts.ShutdownTimeout,
+		"fallbacks", opts.Fallbacks,
+		"calendar", opts.Calendar,
+	)
+
+	cfg := rpc.DefaultConfig()
+	cfg.Addr = opts.Addr
+	cfg.ShutdownTimeout = opts.ShutdownTimeout
+	cfg.Fallbacks = opts.Fallbacks
+
+	calendar, err := timeflags.LoadCalendar(opts.Calendar)
+	if err != nil {
+		return err
+	}
+	cfg.Calendar = calendar
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Apply config file changes to the log settings and default language
+	// while serving
+	if _, err := appconfig.FromContext(ctx).Expand("greeting"); err != nil {
+		return err
+	}
+	cfg.DefaultLanguage = appconfig.DefaultLanguage(ctx, log)
+	appconfig.StartWatching(ctx)
+
+	if levels := logger.LevelsFromContext(ctx); levels != nil {
+		logger.ToggleDebugOnSignal(ctx, levels, log)
+	}
+
+	cmd.SilenceUsage = true
+	return rpc.New(cfg, logger.FromContext(logger.WithComponent(ctx, "rpc"))).Run(ctx)
+}
@@ -0,0 +1,255 @@
+package shell
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// builtins are the commands handled by the shell itself
+var builtins = []string{"exit", "quit", "set", "unset"}
+
+// complete is the liner word completer. It completes the word before the
+// cursor using cobra's completion machinery, so flag names and values
+// complete the same way they do in bash or zsh.
+func (s *session) complete(line string, pos int) (head string, completions []string, tail string) {
+	runes := []rune(line)
+	before, tail := string(runes[:pos]), string(runes[pos:])
+
+	// Only complete plain words; quoted words are left alone
+	start := strings.LastIndexAny(before, " \t") + 1
+	head, toComplete := before[:start], before[start:]
+	if strings.ContainsAny(toComplete, `"'\`) {
+		return head, nil, tail
+	}
+	words, err := splitArgs(head)
+	if err != nil {
+		return head, nil, tail
+	}
+
+	return head, s.candidates(words, toComplete), tail
+}
+
+// candidates returns the completions for toComplete following words
+func (s *session) candidates(words []string, toComplete string) []string {
+	if len(words) > 0 && (words[0] == "set" || words[0] == "unset") {
+		return s.completeSet(words, toComplete)
+	}
+
+	comps, directive := s.cobraComplete(append(words, toComplete))
+	comps = withPrefix(comps, toComplete)
+	if len(words) == 0 {
+		comps = append(comps, withPrefix(builtins, toComplete)...)
+		sort.Strings(comps)
+	}
+
+	// Complete file names when the command leaves it to the shell
+	if len(comps) == 0 && directive == cobra.ShellCompDirectiveDefault {
+		if matches, err := filepath.Glob(toComplete + "*"); err == nil {
+			comps = matches
+		}
+		directive = cobra.ShellCompDirectiveNoSpace
+	}
+
+	if directive&cobra.ShellCompDirectiveNoSpace == 0 {
+		for i := range comps {
+			comps[i] += " "
+		}
+	}
+	return comps
+}
+
+// completeSet completes the flag names and values of set and unset
+func (s *session) completeSet(words []string, toComplete string) []string {
+	switch {
+	case len(words) == 1 && words[0] == "unset":
+		names := make([]string, 0, len(s.defaults))
+		for name := range s.defaults {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return withSuffix(withPrefix(names, toComplete), " ")
+	case len(words) == 1:
+		return withSuffix(withPrefix(flagNames(s.root), toComplete), " ")
+	case len(words) == 2 && words[0] == "set":
+		// Ask the first command with the flag for its values
+		cmd := commandWithFlag(s.root, words[1])
+		if cmd == nil {
+			return nil
+		}
+		path := strings.Fields(cmd.CommandPath())[1:]
+		comps, _ := s.cobraComplete(append(path, "--"+words[1], toComplete))
+		return withSuffix(withPrefix(comps, toComplete), " ")
+	default:
+		return nil
+	}
+}
+
+// cobraComplete runs cobra's hidden completion command for args, the last
+// of which is the word being completed. Like the completion scripts of
+// other shells, callers filter the results by that word.
+func (s *session) cobraComplete(args []string) ([]string, cobra.ShellCompDirective) {
+	var out bytes.Buffer
+	s.root.SetOut(&out)
+	s.root.SetErr(io.Discard)
+	defer func() {
+		s.root.SetOut(s.out)
+		s.root.SetErr(s.errOut)
+		resetFlags(s.root)
+	}()
+
+	s.root.SetArgs(append([]string{cobra.ShellCompNoDescRequestCmd}, args...))
+	if err := s.root.ExecuteContext(s.ctx); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return parseCompletions(out.String())
+}
+
+// parseCompletions reads the output of cobra's completion command: one
+// completion per line followed by :<directive>
+func parseCompletions(output string) ([]string, cobra.ShellCompDirective) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[len(lines)-1], ":") {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	directive, err := strconv.Atoi(strings.TrimPrefix(lines[len(lines)-1], ":"))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var comps []string
+	for _, line := range lines[:len(lines)-1] {
+		if line != "" && !strings.HasPrefix(line, "_activeHelp_") {
+			comps = append(comps, line)
+		}
+	}
+	return comps, cobra.ShellCompDirective(directive)
+}
+
+// flagNames returns the names of all flags in the command tree
+func flagNames(root *cobra.Command) []string {
+	seen := make(map[string]bool)
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		add := func(f *pflag.Flag) {
+			if f.Name != "help" && !f.Hidden && !fixed(f) {
+				seen[f.Name] = true
+			}
+		}
+		cmd.LocalFlags().VisitAll(add)
+		for _, child := range cmd.Commands() {
+			if child.Name() != "shell" && !child.Hidden {
+				visit(child)
+			}
+		}
+	}
+	visit(root)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// commandWithFlag returns the first command in the tree with the flag
+func commandWithFlag(cmd *cobra.Command, name string) *cobra.Command {
+	if cmd.LocalFlags().Lookup(name) != nil {
+		return cmd
+	}
+	for _, child := range cmd.Commands() {
+		if child.Name() == "shell" {
+			continue
+		}
+		if found := commandWithFlag(child, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func withPrefix(values []string, prefix string) []string {
+	var matches []string
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			matches = append(matches, v)
+		}
+	}
+	return matches
+}
+
+func withSuffix(values []string, suffix string) []string {
+	for i := range values {
+		values[i] += suffix
+	}
+	return values
+}
+
+// splitArgs splits a line into words like a POSIX shell: whitespace
+// separates words, single quotes keep text literally, and double quotes
+// and backslashes escape
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("line ends with a backslash")
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,106 @@
+package shell
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/peterh/liner"
+)
+
+// lineReader reads shell input one line at a time
+type lineReader interface {
+	// ReadLine returns the next line, or io.EOF at the end of input
+	ReadLine(prompt string) (string, error)
+	// AppendHistory records a line that was run
+	AppendHistory(line string)
+	// Close releases the reader and saves its history
+	Close() error
+}
+
+// scriptReader reads lines from non-interactive input without prompting
+type scriptReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *scriptReader) ReadLine(string) (string, error) {
+	if r.scanner.Scan() {
+		return r.scanner.Text(), nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+func (r *scriptReader) AppendHistory(string) {}
+
+func (r *scriptReader) Close() error { return nil }
+
+// terminalReader edits lines on a terminal with history and completion
+type terminalReader struct {
+	state       *liner.State
+	historyFile string
+}
+
+// newTerminalReader starts line editing and loads the history file, if any
+func newTerminalReader(historyFile string, complete liner.WordCompleter, log logger.Logger) *terminalReader {
+	state := liner.NewLiner()
+	state.SetCtrlCAborts(true)
+	state.SetTabCompletionStyle(liner.TabPrints)
+	state.SetWordCompleter(complete)
+
+	if historyFile != "" {
+		if f, err := os.Open(historyFile); err == nil { //nolint:gosec // History path is user-supplied by design
+			if _, err := state.ReadHistory(f); err != nil {
+				log.Warn("failed to read shell history", "file", historyFile, "error", err)
+			}
+			_ = f.Close()
+		}
+	}
+
+	return &terminalReader{state: state, historyFile: historyFile}
+}
+
+func (r *terminalReader) ReadLine(prompt string) (string, error) {
+	return r.state.Prompt(prompt)
+}
+
+func (r *terminalReader) AppendHistory(line string) {
+	r.state.AppendHistory(line)
+}
+
+// Close restores the terminal and writes the history file
+func (r *terminalReader) Close() error {
+	defer r.state.Close() //nolint:errcheck // Restoring the terminal is best effort
+	if r.historyFile == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.historyFile), 0o700); err != nil {
+		return &errors.FileError{Path: filepath.Dir(r.historyFile), Operation: "create", Err: err}
+	}
+
+	// Write a temporary file and rename it so a failed write keeps the
+	// previous history
+	tmp, err := os.CreateTemp(filepath.Dir(r.historyFile), ".history-*")
+	if err != nil {
+		return &errors.FileError{Path: r.historyFile, Operation: "write", Err: err}
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // Already renamed on success
+
+	if _, err := r.state.WriteHistory(tmp); err != nil {
+		_ = tmp.Close()
+		return &errors.FileError{Path: r.historyFile, Operation: "write", Err: err}
+	}
+	if err := tmp.Close(); err != nil {
+		return &errors.FileError{Path: r.historyFile, Operation: "write", Err: err}
+	}
+	if err := os.Rename(tmp.Name(), r.historyFile); err != nil {
+		return &errors.FileError{Path: r.historyFile, Operation: "write", Err: err}
+	}
+	return nil
+}
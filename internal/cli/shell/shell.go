@@ -0,0 +1,430 @@
+// Package shell provides an interactive prompt that runs commands of the
+// CLI without starting a new process for each one.
+package shell
+
+import (
+	"bufio"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	appconfig "github.com/go-cli-template/hello-world-cli/internal/config"
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/internal/telemetry"
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// prompt is shown before each line in interactive sessions
+const prompt = "hello-world-cli> " // TODO: Replace with your app name
+
+// Options holds command options
+type Options struct {
+	HistoryFile string
+	NoHistory   bool
+}
+
+// NewCommand creates the shell command
+func NewCommand() *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Start an interactive shell",
+		Long: `Start an interactive prompt that runs hello-world-cli commands.
+
+Configuration and logging are set up once for the whole session. Lines are
+split like a POSIX shell, so quote arguments containing spaces.
+
+Besides the CLI's own commands, the shell understands:
+  set                 List session defaults
+  set <flag> <value>  Use <value> for --<flag> on every command that has it
+  unset <flag>        Remove a session default
+  exit, quit          Leave the shell (or press Ctrl-D)
+
+Global flags given when starting the shell become session defaults. Those
+setting up configuration and logging, such as --log-level, apply to the
+whole session and cannot be changed inside it. History
+is saved between sessions, and Tab completes commands, flags and values.
+Sending the shell SIGUSR1 switches debug logging on and off.`,
+		Example: `  # Start the shell
+  hello-world-cli shell
+
+  # Inside the shell
+  hello-world-cli> set lang es
+  hello-world-cli> greet --name Ana
+  ¡Hola, Ana!
+
+  # Run a script of shell lines
+  hello-world-cli shell < greetings.txt`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShell(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.HistoryFile, "history-file", "", "File to keep command history in (default $XDG_STATE_HOME/hello-world-cli/history)")
+	cmd.Flags().BoolVar(&opts.NoHistory, "no-history", false, "Do not read or save command history")
+
+	return cmd
+}
+
+// fixedAnnotation marks the global flags applied once when the shell
+// starts, such as --log-level
+const fixedAnnotation = "shell_fixed"
+
+// MarkFixed marks global flags applied once for the whole session, like
+// the configuration and logger: the shell rejects them on its lines and in
+// set, since changing them there would have no effect
+func MarkFixed(flags *pflag.FlagSet, names ...string) {
+	for _, name := range names {
+		_ = flags.SetAnnotation(name, fixedAnnotation, []string{"true"})
+	}
+}
+
+// fixed reports whether f is a global flag applied once per session
+func fixed(f *pflag.Flag) bool {
+	_, ok := f.Annotations[fixedAnnotation]
+	return ok
+}
+
+// fixedError reports a flag that cannot change inside the shell
+func fixedError(name string) error {
+	return &errors.ValidationError{Field: "flag", Value: "--" + name, Message: "--" + name + " cannot be changed inside a shell; give it when starting the shell"}
+}
+
+// sessionKey marks contexts of commands run from the shell
+type sessionKey struct{}
+
+// InSession reports whether ctx belongs to a command run from the shell
+func InSession(ctx context.Context) bool {
+	return ctx != nil && ctx.Value(sessionKey{}) != nil
+}
+
+// session holds the state of a running shell
+type session struct {
+	root     *cobra.Command
+	ctx      context.Context
+	log      logger.Logger
+	out      io.Writer
+	errOut   io.Writer
+	errors   *errors.Handler
+	defaults map[string]string // Flag name to value, from set
+}
+
+func runShell(cmd *cobra.Command, opts *Options) error {
+	log := logger.FromContext(cmd.Context())
+	root := cmd.Root()
+
+	s := &session{
+		root:     root,
+		ctx:      context.WithValue(cmd.Context(), sessionKey{}, true),
+		log:      log,
+		out:      cmd.OutOrStdout(),
+		errOut:   cmd.ErrOrStderr(),
+		errors:   errors.NewHandler(),
+		defaults: make(map[string]string),
+	}
+	s.errors.Output = s.errOut
+
+	// Apply config file changes to the commands run from the shell
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	s.ctx = ctx
+	appconfig.StartWatching(ctx)
+	if levels := logger.LevelsFromContext(ctx); levels != nil {
+		logger.ToggleDebugOnSignal(ctx, levels, log)
+	}
+
+	// Global flags given to the shell carry over to every command; fixed
+	// ones are already applied to the session
+	root.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed && !fixed(f) {
+			s.defaults[f.Name] = flagValue(f)
+		}
+	})
+
+	// Errors are presented by the shell instead of ending the process
+	silenceErrors, silenceUsage := root.SilenceErrors, root.SilenceUsage
+	root.SilenceErrors, root.SilenceUsage = true, true
+	defer func() {
+		root.SilenceErrors, root.SilenceUsage = silenceErrors, silenceUsage
+	}()
+
+	var reader lineReader
+	if f, ok := cmd.InOrStdin().(*os.File); ok && f == os.Stdin && liner.TerminalSupported() && isTerminal(f) {
+		historyFile := ""
+		if !opts.NoHistory {
+			historyFile = opts.HistoryFile
+			if historyFile == "" {
+				historyFile = defaultHistoryFile()
+			}
+		}
+		reader = newTerminalReader(historyFile, s.complete, log)
+		s.errors.Color = true
+	} else {
+		reader = &scriptReader{scanner: bufio.NewScanner(cmd.InOrStdin())}
+		s.errors.Color = false
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Warn("failed to save shell history", "error", err)
+		}
+	}()
+
+	log.Debug("starting shell", "defaults", s.defaults)
+	for {
+		line, err := reader.ReadLine(prompt)
+		if stderrors.Is(err, liner.ErrPromptAborted) {
+			continue
+		}
+		if stderrors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, errors.CodeInternal, "failed to read shell input")
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		reader.AppendHistory(line)
+
+		if done := s.runLine(line); done {
+			return nil
+		}
+	}
+}
+
+// runLine runs one line of input and reports whether the shell should exit
+func (s *session) runLine(line string) bool {
+	args, err := splitArgs(line)
+	if err != nil {
+		s.errors.Present(&errors.ValidationError{Field: "input", Value: line, Message: err.Error()})
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "set":
+		s.set(args[1:])
+		return false
+	case "unset":
+		s.unset(args[1:])
+		return false
+	case "shell":
+		s.errors.Present(errors.New(errors.CodeInvalidArgument, "already in a shell"))
+		return false
+	}
+
+	if err := s.execute(args); err != nil {
+		s.errors.Present(err)
+	}
+	return false
+}
+
+// execute runs a command of the tree with the session defaults applied.
+// Lines setting a fixed global flag are rejected.
+func (s *session) execute(args []string) error {
+	var fixedErr error
+	s.root.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if fixedErr == nil && fixed(f) && hasFlag(args, f) {
+			fixedErr = fixedError(f.Name)
+		}
+	})
+	if fixedErr != nil {
+		return fixedErr
+	}
+
+	args = s.withDefaults(args)
+	s.log.Debug("running shell command", "args", args)
+
+	// Flags keep their values between runs of the same command tree, so
+	// every run starts from the defaults
+	defer resetFlags(s.root)
+
+	// Each command gets its own span within the span of the shell
+	path := s.root.Name()
+	if target, _, err := s.root.Find(args); err == nil {
+		path = target.CommandPath()
+	}
+	ctx, _ := telemetry.StartCommand(s.ctx, path)
+
+	s.root.SetArgs(args)
+	err := s.root.ExecuteContext(ctx)
+	telemetry.EndCommand(ctx, path, err)
+	return err
+}
+
+// withDefaults prepends a --flag=value argument for every session default
+// the target command accepts and the line does not set itself
+func (s *session) withDefaults(args []string) []string {
+	target, _, err := s.root.Find(args)
+	if err != nil || len(s.defaults) == 0 {
+		return args
+	}
+
+	names := make([]string, 0, len(s.defaults))
+	for name := range s.defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var extra []string
+	for _, name := range names {
+		f := lookupFlag(target, name)
+		if f == nil || hasFlag(args, f) {
+			continue
+		}
+		extra = append(extra, "--"+name+"="+s.defaults[name])
+	}
+	return append(extra, args...)
+}
+
+// set lists the session defaults or sets one
+func (s *session) set(args []string) {
+	switch len(args) {
+	case 0:
+		names := make([]string, 0, len(s.defaults))
+		for name := range s.defaults {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			_, _ = fmt.Fprintf(s.out, "%s = %s\n", name, s.defaults[name])
+		}
+	case 2:
+		name := strings.TrimPrefix(args[0], "--")
+		f := findFlag(s.root, name)
+		if f == nil {
+			s.errors.Present(&errors.ValidationError{Field: "flag", Value: name, Message: "no command has a --" + name + " flag"})
+			return
+		}
+		if fixed(f) {
+			s.errors.Present(fixedError(name))
+			return
+		}
+		s.defaults[name] = args[1]
+	default:
+		s.errors.Present(errors.New(errors.CodeInvalidArgument, "usage: set <flag> <value>"))
+	}
+}
+
+// unset removes session defaults
+func (s *session) unset(args []string) {
+	if len(args) == 0 {
+		s.errors.Present(errors.New(errors.CodeInvalidArgument, "usage: unset <flag>..."))
+		return
+	}
+	for _, name := range args {
+		delete(s.defaults, strings.TrimPrefix(name, "--"))
+	}
+}
+
+// lookupFlag finds a flag cmd accepts, its own or inherited
+func lookupFlag(cmd *cobra.Command, name string) *pflag.Flag {
+	if f := cmd.Flags().Lookup(name); f != nil {
+		return f
+	}
+	return cmd.InheritedFlags().Lookup(name)
+}
+
+// findFlag finds a flag by name anywhere in the command tree
+func findFlag(cmd *cobra.Command, name string) *pflag.Flag {
+	if f := lookupFlag(cmd, name); f != nil {
+		return f
+	}
+	for _, child := range cmd.Commands() {
+		if child.Name() == "shell" {
+			continue
+		}
+		if f := findFlag(child, name); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// hasFlag reports whether args set f, by long name or shorthand
+func hasFlag(args []string, f *pflag.Flag) bool {
+	for _, arg := range args {
+		switch {
+		case arg == "--":
+			return false
+		case arg == "--"+f.Name || strings.HasPrefix(arg, "--"+f.Name+"="):
+			return true
+		case f.Shorthand != "" && !strings.HasPrefix(arg, "--") && strings.HasPrefix(arg, "-"+f.Shorthand):
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue returns a flag's value as it would be written on the command line
+func flagValue(f *pflag.Flag) string {
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		return strings.Join(sv.GetSlice(), ",")
+	}
+	return f.Value.String()
+}
+
+// resetFlags restores every flag in the tree to its default value, except
+// the fixed global flags: the configuration reloaded during the session
+// still reads them
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if fixed(f) {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			var values []string
+			if def := strings.Trim(f.DefValue, "[]"); def != "" {
+				values = strings.Split(def, ",")
+			}
+			_ = sv.Replace(values)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+// defaultHistoryFile returns the history location under the XDG state
+// directory
+func defaultHistoryFile() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "hello-world-cli", "history") // TODO: Replace with your app name
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,218 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/cli/greet"
+	"github.com/go-cli-template/hello-world-cli/internal/cli/hello"
+	"github.com/go-cli-template/hello-world-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// afternoon is a fixed time for deterministic greetings
+const afternoon = "2026-03-10T15:00:00Z"
+
+// newTestRoot builds a command tree like the CLI's with the shell in it
+func newTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "hello-world-cli"}
+	output.AddFlag(root.PersistentFlags())
+	root.PersistentFlags().String("log-level", "", "log level")
+	MarkFixed(root.PersistentFlags(), "log-level")
+	root.AddCommand(greet.NewCommand(), hello.NewCommand(), NewCommand())
+	return root
+}
+
+// runScript runs the shell with script as its input
+func runScript(t *testing.T, script string, args ...string) (stdout, stderr string) {
+	t.Helper()
+	root := newTestRoot()
+	outBuf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	root.SetOut(outBuf)
+	root.SetErr(errBuf)
+	root.SetIn(strings.NewReader(script))
+	root.SetArgs(append(args, "shell"))
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return outBuf.String(), errBuf.String()
+}
+
+func TestShell(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		script     string
+		wantOutput string
+		wantErrors []string
+	}{
+		{
+			name:       "runs commands",
+			script:     "greet --name Ana --lang es --at " + afternoon + "\nhello --at " + afternoon + "\n",
+			wantOutput: "¡Buenas tardes, Ana!\nGood afternoon, World!\n",
+		},
+		{
+			name: "set applies to later commands",
+			script: "set lang de\n" +
+				"greet --name Ana --at " + afternoon + "\n" +
+				"greet --name Ana --lang fr --at " + afternoon + "\n",
+			wantOutput: "Guten Tag, Ana!\nBonjour, Ana!\n",
+		},
+		{
+			name: "flags reset between commands",
+			script: "greet --name Ana --name Bob --lang en --at " + afternoon + "\n" +
+				"greet --name Eva --lang en --at " + afternoon + "\n",
+			wantOutput: "Good afternoon to you both, Ana and Bob! 🎉\nGood afternoon, Eva! 🎉\n",
+		},
+		{
+			name:       "quoted arguments",
+			script:     `greet --name "Ana María" --lang 'es' --at ` + afternoon + "\n",
+			wantOutput: "¡Buenas tardes, Ana María!\n",
+		},
+		{
+			name:       "global flags become defaults",
+			args:       []string{"--output", "csv"},
+			script:     "set\n",
+			wantOutput: "output = csv\n",
+		},
+		{
+			name: "fixed global flags are rejected",
+			args: []string{"--log-level", "warn"},
+			script: "set log-level debug\n" +
+				"greet --log-level=debug --name Ana --lang en --at " + afternoon + "\n" +
+				"set\n",
+			wantOutput: "",
+			wantErrors: []string{"--log-level cannot be changed inside a shell"},
+		},
+		{
+			name:       "unset and list",
+			script:     "set lang es\nset gender female\nunset lang\nset\n",
+			wantOutput: "gender = female\n",
+		},
+		{
+			name:       "errors do not end the session",
+			script:     "greet --lang en\nset bogus 1\nnope\nshell\ngreet --name Ana --lang en --at " + afternoon + "\n",
+			wantOutput: "Good afternoon, Ana! 🎉\n",
+			wantErrors: []string{"name is required", "--bogus", "unknown command", "already in a shell"},
+		},
+		{
+			name:       "exit stops reading",
+			script:     "exit\nhello\n",
+			wantOutput: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := runScript(t, tt.script, tt.args...)
+			if stdout != tt.wantOutput {
+				t.Errorf("output = %q, want %q", stdout, tt.wantOutput)
+			}
+			for _, want := range tt.wantErrors {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("errors = %q, want substring %q", stderr, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFixedFlagsKeptForReloads(t *testing.T) {
+	root := newTestRoot()
+	var cfgFile string
+	root.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	MarkFixed(root.PersistentFlags(), "config")
+
+	// reload reads the global flags as the config watcher does when a
+	// config file changes during the session
+	var reloads []string
+	reload := func() {
+		level := root.PersistentFlags().Lookup("log-level")
+		reloads = append(reloads, fmt.Sprintf("%s %s %t", cfgFile, level.Value, level.Changed))
+	}
+	root.AddCommand(&cobra.Command{Use: "reload", Run: func(*cobra.Command, []string) { reload() }})
+
+	root.SetOut(new(bytes.Buffer))
+	root.SetErr(new(bytes.Buffer))
+	root.SetIn(strings.NewReader("hello --at " + afternoon + "\ngreet --name Ana --at " + afternoon + "\nreload\n"))
+	root.SetArgs([]string{"--config", "session.yaml", "--log-level", "warn", "shell"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	reload()
+
+	want := []string{"session.yaml warn true", "session.yaml warn true"}
+	if !reflect.DeepEqual(reloads, want) {
+		t.Errorf("reloads read %q, want %q", reloads, want)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	root := newTestRoot()
+	s := &session{
+		root:     root,
+		ctx:      context.WithValue(context.Background(), sessionKey{}, true),
+		out:      new(bytes.Buffer),
+		errOut:   new(bytes.Buffer),
+		defaults: map[string]string{"lang": "es"},
+	}
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{line: "gr", want: []string{"greet "}},
+		{line: "e", want: []string{"exit "}},
+		{line: "greet --la", want: []string{"--lang "}},
+		{line: "greet --lang e", want: []string{"en ", "es "}},
+		{line: "greet --gender f", want: []string{"female "}},
+		{line: "set fall", want: []string{"fallback "}},
+		{line: "set lang f", want: []string{"fr "}},
+		{line: "unset ", want: []string{"lang "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			head, got, tail := s.complete(tt.line, len([]rune(tt.line)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("complete(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+			if !strings.HasPrefix(tt.line, head) || tail != "" {
+				t.Errorf("complete(%q) head = %q, tail = %q", tt.line, head, tail)
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "", want: nil},
+		{line: "  greet   --name  Ana ", want: []string{"greet", "--name", "Ana"}},
+		{line: `greet --name "Ana María"`, want: []string{"greet", "--name", "Ana María"}},
+		{line: `greet --name 'l"ami'`, want: []string{"greet", "--name", `l"ami`}},
+		{line: `greet --name Ana\ María`, want: []string{"greet", "--name", "Ana María"}},
+		{line: `greet --name ""`, want: []string{"greet", "--name", ""}},
+		{line: `greet --name "Ana`, wantErr: true},
+		{line: `greet \`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := splitArgs(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitArgs(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArgs(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
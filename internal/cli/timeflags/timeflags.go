@@ -0,0 +1,72 @@
+// Package timeflags provides the --at, --tz and --calendar flags shared by
+// commands that generate greetings.
+package timeflags
+
+import (
+	"time"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/greeting"
+	"github.com/spf13/pflag"
+)
+
+// BuiltinCalendar selects greeting.DefaultCalendar for --calendar
+const BuiltinCalendar = "builtin"
+
+// Options holds the time-related flag values
+type Options struct {
+	At       string
+	TZ       string
+	Calendar string
+}
+
+// AddFlags registers the time flags
+func (o *Options) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.At, "at", "", "Greet as if it were this RFC 3339 time, e.g. 2024-12-25T08:00:00Z")
+	flags.StringVar(&o.TZ, "tz", "", "IANA time zone for the time of day, e.g. Europe/Madrid (default local)")
+	flags.StringVar(&o.Calendar, "calendar", "", `Holiday calendar: "builtin" or a YAML, JSON or TOML file of dates`)
+}
+
+// Apply validates the flags and sets the clock, location and calendar on opts
+func (o *Options) Apply(opts *greeting.Options) error {
+	if o.At != "" {
+		at, err := time.Parse(time.RFC3339, o.At)
+		if err != nil {
+			return &errors.ValidationError{Field: "at", Value: o.At, Message: "must be an RFC 3339 time such as 2024-12-25T08:00:00Z"}
+		}
+		opts.Clock = func() time.Time { return at }
+	}
+
+	if o.TZ != "" {
+		loc, err := time.LoadLocation(o.TZ)
+		if err != nil {
+			return &errors.ValidationError{Field: "tz", Value: o.TZ, Message: "unknown IANA time zone"}
+		}
+		opts.Location = loc
+	}
+
+	calendar, err := LoadCalendar(o.Calendar)
+	if err != nil {
+		return err
+	}
+	opts.Calendar = calendar
+
+	return nil
+}
+
+// LoadCalendar resolves a --calendar value: "" for no calendar, "builtin"
+// for greeting.DefaultCalendar, or the path of a calendar file
+func LoadCalendar(value string) (greeting.Calendar, error) {
+	switch value {
+	case "":
+		return nil, nil
+	case BuiltinCalendar:
+		return greeting.DefaultCalendar, nil
+	default:
+		calendar, err := greeting.LoadCalendar(value)
+		if err != nil {
+			return nil, err
+		}
+		return calendar, nil
+	}
+}
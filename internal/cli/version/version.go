@@ -1,17 +1,35 @@
 package version
 
 import (
-	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/go-cli-template/hello-world-cli/internal/output"
 	"github.com/go-cli-template/hello-world-cli/pkg/version"
 	"github.com/spf13/cobra"
 )
 
 // Options holds command options
 type Options struct {
-	JSONOutput bool
-	Short      bool
+	Short bool
+}
+
+// buildInfo renders version.BuildInfo for text output
+type buildInfo struct {
+	version.BuildInfo
+}
+
+// String returns the multi-line text form of the build information
+func (b buildInfo) String() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "hello-world-cli version %s\n", b.Version)
+	fmt.Fprintf(&s, "  Build Time: %s\n", b.BuildTime)
+	if b.GitCommit != "" {
+		fmt.Fprintf(&s, "  Git Commit: %s\n", b.GitCommit)
+	}
+	fmt.Fprintf(&s, "  Go Version: %s\n", b.GoVersion)
+	fmt.Fprintf(&s, "  Platform:   %s", b.Platform)
+	return s.String()
 }
 
 // NewCommand creates the version command
@@ -29,42 +47,32 @@ func NewCommand() *cobra.Command {
   hello-world-cli version --short
   
   # Show version in JSON format
-  hello-world-cli version --json`,
+  hello-world-cli version -o json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runVersion(opts)
+			return runVersion(cmd, opts)
 		},
 	}
 
 	// Add flags
-	cmd.Flags().BoolVar(&opts.JSONOutput, "json", false, "Output version in JSON format")
 	cmd.Flags().BoolVar(&opts.Short, "short", false, "Print just the version number")
+	output.AddLegacyJSONFlag(cmd)
 
 	return cmd
 }
 
-func runVersion(opts *Options) error {
-	info := version.GetBuildInfo()
+func runVersion(cmd *cobra.Command, opts *Options) error {
+	printer, err := output.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
 
+	info := version.GetBuildInfo()
 	if opts.Short {
-		fmt.Println(info.Version)
-		return nil
+		return printer.Print(cmd.OutOrStdout(), info.Version)
 	}
 
-	if opts.JSONOutput {
-		data, err := json.MarshalIndent(info, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal version info: %w", err)
-		}
-		fmt.Println(string(data))
-	} else {
-		fmt.Printf("hello-world-cli version %s\n", info.Version)
-		fmt.Printf("  Build Time: %s\n", info.BuildTime)
-		if info.GitCommit != "" {
-			fmt.Printf("  Git Commit: %s\n", info.GitCommit)
-		}
-		fmt.Printf("  Go Version: %s\n", info.GoVersion)
-		fmt.Printf("  Platform:   %s\n", info.Platform)
+	if err := printer.Print(cmd.OutOrStdout(), buildInfo{info}); err != nil {
+		return fmt.Errorf("failed to write version info: %w", err)
 	}
-
 	return nil
 }
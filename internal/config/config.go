@@ -0,0 +1,673 @@
+// Package config describes the configuration keys of the CLI, resolves
+// where their effective values come from and edits config files.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/greeting"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/spf13/pflag"
+)
+
+// EnvPrefix is the prefix of environment variables overriding config keys
+const EnvPrefix = "HELLO_WORLD_CLI" // TODO: Replace with your app name in uppercase
+
+// Value types of configuration keys
+const (
+	TypeString = "string"
+	TypeBool   = "bool"
+	TypeInt    = "int"
+	TypeList   = "list" // A list of tables whose keys are Fields
+	TypeMap    = "map"  // A table of strings under names of the user's choosing
+)
+
+// Key describes a configuration key
+type Key struct {
+	Name        string   // Dotted key, e.g. log.level
+	Type        string   // TypeString, TypeBool, TypeInt, TypeList or TypeMap
+	Default     any      // Value used when nothing sets the key
+	Allowed     []string // Permitted values, or of each TypeMap value, if restricted
+	Flag        string   // Global flag bound to the key, if any
+	LegacyEnv   string   // Unprefixed environment variable, if any
+	Description string
+
+	// Check validates string values beyond Allowed, if set
+	Check func(value string) error
+
+	// Fields are the keys of each table in a TypeList value
+	Fields []Key
+
+	// CheckTable reports what is wrong with a table of a TypeList value
+	// whose fields are each valid, if anything
+	CheckTable func(table map[string]any) error
+}
+
+// Keys lists every known configuration key, sorted by name
+var Keys = []Key{
+	{Name: "debug", Type: TypeBool, Default: false, Flag: "debug", Description: "Enable debug logging with source locations"},
+	{Name: "greeting.emoji", Type: TypeBool, Default: false, Description: "Include emoji in greetings"},
+	{Name: "greeting.language", Type: TypeString, Default: "", Description: "Default language when --lang is not given", Check: checkLanguage},
+	{Name: "locales.dir", Type: TypeString, Default: "", Flag: "locales-dir", Description: "Additional directory of greeting locale files"},
+	{Name: "log.compress", Type: TypeBool, Default: false, Description: "Gzip rotated log files"},
+	{Name: "log.format", Type: TypeString, Default: "text", Allowed: logFormats, Flag: "log-format", LegacyEnv: "LOG_FORMAT", Description: "Log format"},
+	{Name: "log.level", Type: TypeString, Default: "info", Allowed: logLevels, Flag: "log-level", LegacyEnv: "LOG_LEVEL", Description: "Minimum log level"},
+	{Name: "log.levels", Type: TypeMap, Default: map[string]any{}, Allowed: logLevels, Description: "Minimum log level by component, e.g. {greeting: debug, cli: warn}"},
+	{Name: "log.max_age", Type: TypeString, Default: "", Description: "Remove rotated log files older than this, e.g. 7d; empty keeps them", Check: checkDuration},
+	{Name: "log.max_backups", Type: TypeInt, Default: 5, Description: "Number of rotated log files to keep, 0 keeps all", Check: checkNonNegative},
+	{Name: "log.max_size", Type: TypeString, Default: "100MB", Description: "Rotate the log file before it grows past this size, 0 never", Check: checkSize},
+	{Name: "log.output", Type: TypeString, Default: "stderr", Flag: "log-output", Description: "Where logs go: stderr, stdout, a file path or a file:// URL", Check: checkOutput},
+	{Name: "log.redact", Type: TypeList, Default: redactDefaults(), Description: "Rules hiding sensitive attributes in logs and error details", Fields: redactFields, CheckTable: checkRedactRule},
+	{Name: "log.rotate_interval", Type: TypeString, Default: "", Description: "Also rotate the log file on this interval, e.g. 24h; empty never", Check: checkDuration},
+	{Name: "log.sampling.burst", Type: TypeInt, Default: 0, Description: "Records logged at once before log.sampling.rate applies, 0 uses the rate", Check: checkNonNegative},
+	{Name: "log.sampling.first", Type: TypeInt, Default: 0, Description: "Records with the same message logged per interval before sampling, 0 logs all", Check: checkNonNegative},
+	{Name: "log.sampling.interval", Type: TypeString, Default: "1s", Description: "Window of log.sampling.first, and how often dropped records are reported", Check: checkDuration},
+	{Name: "log.sampling.rate", Type: TypeInt, Default: 0, Description: "Records below warn logged per second, 0 is unlimited", Check: checkNonNegative},
+	{Name: "log.sampling.thereafter", Type: TypeInt, Default: 0, Description: "After the first ones, log every Nth record with the same message, 0 drops the rest", Check: checkNonNegative},
+	{Name: "log.sinks", Type: TypeList, Default: []any{}, Description: "More log destinations, each with its own level, format and output", Fields: sinkFields},
+	{Name: "telemetry.endpoint", Type: TypeString, Default: "", Description: "OTLP endpoint as host:port or a URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost"},
+	{Name: "telemetry.exporter", Type: TypeString, Default: "none", Allowed: []string{"none", "otlp", "stdout", "file"}, Description: "Where traces and metrics go: none, otlp, stdout or file"},
+	{Name: "telemetry.file", Type: TypeString, Default: "", Description: "File the file exporter appends traces and metrics to"},
+	{Name: "telemetry.insecure", Type: TypeBool, Default: false, Description: "Send OTLP without TLS to a host:port endpoint"},
+	{Name: "telemetry.protocol", Type: TypeString, Default: "http", Allowed: []string{"http", "grpc"}, Description: "OTLP protocol: http or grpc"},
+	{Name: "verbose", Type: TypeBool, Default: false, Flag: "verbose", Description: "Verbose output"},
+}
+
+// Log levels and formats understood by the logger
+var (
+	logLevels  = []string{"debug", "info", "warn", "error"}
+	logFormats = []string{"text", "json", "color", "logfmt"}
+)
+
+// sinkFields are the keys of each log.sinks table
+var sinkFields = []Key{
+	{Name: "compress", Type: TypeBool, Default: false, Description: "Gzip rotated log files"},
+	{Name: "format", Type: TypeString, Default: "text", Allowed: logFormats, Description: "Log format"},
+	{Name: "level", Type: TypeString, Default: "", Allowed: logLevels, Description: "Minimum log level, log.level if not set"},
+	{Name: "max_age", Type: TypeString, Default: "", Description: "Remove rotated log files older than this, e.g. 7d; empty keeps them", Check: checkDuration},
+	{Name: "max_backups", Type: TypeInt, Default: 5, Description: "Number of rotated log files to keep, 0 keeps all", Check: checkNonNegative},
+	{Name: "max_size", Type: TypeString, Default: "100MB", Description: "Rotate the log file before it grows past this size, 0 never", Check: checkSize},
+	{Name: "output", Type: TypeString, Default: "stderr", Description: "Where logs go: stderr, stdout, a file path or a file:// URL", Check: checkOutput},
+	{Name: "rotate_interval", Type: TypeString, Default: "", Description: "Also rotate the log file on this interval, e.g. 24h; empty never", Check: checkDuration},
+}
+
+// redactFields are the keys of each log.redact table
+var redactFields = []Key{
+	{Name: "action", Type: TypeString, Default: logger.RedactMask, Allowed: []string{logger.RedactMask, logger.RedactHash, logger.RedactDrop}, Description: "What to do with a match: mask, hash or drop"},
+	{Name: "key", Type: TypeString, Default: "", Description: "Glob matching attribute keys, e.g. *token*", Check: checkKeyPattern},
+	{Name: "value", Type: TypeString, Default: "", Description: "Regular expression matching parts of values", Check: checkValuePattern},
+}
+
+// redactDefaults returns the default log.redact value, the logger's
+// default rules
+func redactDefaults() []any {
+	rules := logger.DefaultRedactRules()
+	configs := make([]RedactConfig, 0, len(rules))
+	for _, rule := range rules {
+		configs = append(configs, RedactConfig{Key: rule.Key, Value: rule.Value, Action: rule.Action})
+	}
+	return encodeList(reflect.ValueOf(configs), redactFields)
+}
+
+// Lookup returns the known key with the given name
+func Lookup(name string) (Key, bool) {
+	for _, key := range Keys {
+		if key.Name == name {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// EnvVar returns the environment variable overriding a key, e.g.
+// HELLO_WORLD_CLI_LOG_LEVEL for log.level
+func EnvVar(name string) string {
+	replacer := strings.NewReplacer(".", "_", "-", "_")
+	return EnvPrefix + "_" + strings.ToUpper(replacer.Replace(name))
+}
+
+// ParseValue converts a command-line string to the type of a key and
+// checks it is allowed. String values holding a secret reference such as
+// ${env:TOKEN} are kept as written and checked once resolved.
+func ParseValue(name, raw string) (any, error) {
+	key, ok := Lookup(name)
+	if !ok {
+		return nil, &errors.ConfigError{Key: name, Value: raw, Message: "unknown configuration key"}
+	}
+	if hasReference(raw) {
+		if key.Type != TypeString {
+			return nil, referenceError(key, raw)
+		}
+		return raw, nil
+	}
+
+	switch key.Type {
+	case TypeBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, &errors.ConfigError{Key: name, Value: raw, Message: "must be true or false"}
+		}
+		return value, nil
+	case TypeInt:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, &errors.ConfigError{Key: name, Value: raw, Message: "must be a whole number"}
+		}
+		if err := checkInt(key, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case TypeList:
+		value, ok := parseList(raw)
+		if !ok {
+			return nil, &errors.ConfigError{Key: name, Value: raw, Message: "must be a JSON list of tables"}
+		}
+		if err := checkType(key, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case TypeMap:
+		value, ok := parseMap(raw)
+		if !ok {
+			return nil, &errors.ConfigError{Key: name, Value: raw, Message: "must be a JSON object of strings"}
+		}
+		if err := checkType(key, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	if err := checkString(key, raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// Validate checks nested config file values against the known keys,
+// including those of named profiles, and returns every problem found,
+// sorted by key
+func Validate(values map[string]any) []*errors.ConfigError {
+	base := make(map[string]any, len(values))
+	for name, value := range values {
+		if name != ProfilesKey {
+			base[name] = value
+		}
+	}
+
+	problems := validateValues("", base)
+	if profiles, ok := values[ProfilesKey]; ok {
+		problems = append(problems, validateProfiles(profiles)...)
+	}
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Key < problems[j].Key })
+	return problems
+}
+
+// validateValues checks values whose keys are reported under prefix
+func validateValues(prefix string, values map[string]any) []*errors.ConfigError {
+	var problems []*errors.ConfigError
+	for name, value := range flatten("", values) {
+		key, ok := Lookup(name)
+		if !ok {
+			problems = append(problems, &errors.ConfigError{Key: prefix + name, Value: fmt.Sprint(value), Message: "unknown configuration key"})
+			continue
+		}
+		if s, ok := value.(string); ok && hasReference(s) {
+			if key.Type != TypeString {
+				err := referenceError(key, s)
+				err.Key = prefix + err.Key
+				problems = append(problems, err)
+			}
+			// Strings are checked once resolved
+			continue
+		}
+		if err := checkType(key, value); err != nil {
+			err.Key = prefix + err.Key
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// checkType checks a value read from a config file. Problems with a
+// Secret do not repeat its value.
+func checkType(key Key, value any) *errors.ConfigError {
+	if secret, ok := value.(Secret); ok {
+		if key.Type != TypeString {
+			return &errors.ConfigError{Key: key.Name, Value: Redacted, Message: "must be " + typeName(key)}
+		}
+		err := checkString(key, secret.Reveal())
+		if err != nil {
+			err.Value = Redacted
+		}
+		return err
+	}
+
+	switch key.Type {
+	case TypeBool:
+		if _, ok := value.(bool); !ok {
+			return &errors.ConfigError{Key: key.Name, Value: fmt.Sprint(value), Message: fmt.Sprintf("must be true or false, got %T", value)}
+		}
+	case TypeInt:
+		n, ok := toInt(value)
+		if !ok {
+			return &errors.ConfigError{Key: key.Name, Value: fmt.Sprint(value), Message: fmt.Sprintf("must be a whole number, got %T", value)}
+		}
+		return checkInt(key, n)
+	case TypeList:
+		return checkList(key, value)
+	case TypeMap:
+		return checkMap(key, value)
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return &errors.ConfigError{Key: key.Name, Value: fmt.Sprint(value), Message: fmt.Sprintf("must be a string, got %T", value)}
+		}
+		return checkString(key, s)
+	}
+	return nil
+}
+
+// typeName describes the values of a key in problems
+func typeName(key Key) string {
+	switch key.Type {
+	case TypeBool:
+		return "true or false"
+	case TypeInt:
+		return "a whole number"
+	case TypeList:
+		return "a list of tables"
+	case TypeMap:
+		return "a table of strings"
+	default:
+		return "a string"
+	}
+}
+
+// toInt returns a whole number read from a config file: YAML and TOML
+// decode integers, JSON decodes every number as float64
+func toInt(value any) (int, bool) {
+	switch n := value.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// checkList checks each table of a TypeList value against the key's
+// Fields. Problems are reported under keys such as log.sinks[0].level.
+func checkList(key Key, value any) *errors.ConfigError {
+	items, ok := value.([]any)
+	if !ok {
+		return &errors.ConfigError{Key: key.Name, Value: fmt.Sprint(value), Message: fmt.Sprintf("must be a list of tables, got %T", value)}
+	}
+	for i, item := range items {
+		prefix := fmt.Sprintf("%s[%d]", key.Name, i)
+		table, ok := item.(map[string]any)
+		if !ok {
+			return &errors.ConfigError{Key: prefix, Value: fmt.Sprint(item), Message: fmt.Sprintf("must be a table, got %T", item)}
+		}
+		names := make([]string, 0, len(table))
+		for name := range table {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			field, ok := lookupField(key, name)
+			if !ok {
+				return &errors.ConfigError{Key: prefix + "." + name, Value: fmt.Sprint(table[name]), Message: "unknown configuration key"}
+			}
+			if err := checkType(field, table[name]); err != nil {
+				err.Key = prefix + "." + name
+				return err
+			}
+		}
+		if key.CheckTable != nil {
+			if err := key.CheckTable(table); err != nil {
+				return &errors.ConfigError{Key: prefix, Value: fmt.Sprint(table), Message: err.Error()}
+			}
+		}
+	}
+	return nil
+}
+
+// checkMap checks each string of a TypeMap value against the key's rules.
+// Problems are reported under keys such as log.levels.greeting.
+func checkMap(key Key, value any) *errors.ConfigError {
+	table, ok := value.(map[string]any)
+	if !ok {
+		return &errors.ConfigError{Key: key.Name, Value: fmt.Sprint(value), Message: fmt.Sprintf("must be a table of strings, got %T", value)}
+	}
+	names := make([]string, 0, len(table))
+	for name := range table {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s, ok := table[name].(string)
+		if !ok {
+			return &errors.ConfigError{Key: key.Name + "." + name, Value: fmt.Sprint(table[name]), Message: fmt.Sprintf("must be a string, got %T", table[name])}
+		}
+		if err := checkString(key, s); err != nil {
+			err.Key = key.Name + "." + name
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupField returns the field of a TypeList key with the given name
+func lookupField(key Key, name string) (Key, bool) {
+	for _, field := range key.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Key{}, false
+}
+
+// parseList decodes a TypeList value given as JSON on the command line or
+// in the environment
+func parseList(raw string) ([]any, bool) {
+	var value []any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// parseMap decodes a TypeMap value given as JSON on the command line or
+// in the environment
+func parseMap(raw string) (map[string]any, bool) {
+	var value map[string]any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil || value == nil {
+		return nil, false
+	}
+	return value, true
+}
+
+func checkInt(key Key, value int) *errors.ConfigError {
+	if key.Check == nil {
+		return nil
+	}
+	if err := key.Check(strconv.Itoa(value)); err != nil {
+		return &errors.ConfigError{Key: key.Name, Value: strconv.Itoa(value), Message: err.Error()}
+	}
+	return nil
+}
+
+func checkString(key Key, value string) *errors.ConfigError {
+	if key.Check != nil {
+		if err := key.Check(value); err != nil {
+			return &errors.ConfigError{Key: key.Name, Value: value, Message: err.Error()}
+		}
+	}
+	if len(key.Allowed) == 0 {
+		return nil
+	}
+	for _, allowed := range key.Allowed {
+		if value == allowed {
+			return nil
+		}
+	}
+	return &errors.ConfigError{
+		Key:     key.Name,
+		Value:   value,
+		Message: "must be one of " + strings.Join(key.Allowed, ", "),
+	}
+}
+
+// checkLanguage accepts an empty value or a well-formed language tag
+func checkLanguage(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := greeting.ParseTag(value); err != nil {
+		return fmt.Errorf("must be a language tag such as en or pt-BR")
+	}
+	return nil
+}
+
+// checkNonNegative accepts whole numbers of zero or more
+func checkNonNegative(value string) error {
+	if n, err := strconv.Atoi(value); err != nil || n < 0 {
+		return fmt.Errorf("must be 0 or more")
+	}
+	return nil
+}
+
+// checkSize accepts a size such as 100MB
+func checkSize(value string) error {
+	_, err := logger.ParseSize(value)
+	return err
+}
+
+// checkDuration accepts an empty value or a duration such as 24h or 7d
+func checkDuration(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := logger.ParseDuration(value)
+	return err
+}
+
+// checkKeyPattern accepts a glob as understood by path.Match
+func checkKeyPattern(value string) error {
+	return logger.CheckRedactRule(logger.RedactRule{Key: value, Value: "."})
+}
+
+// checkValuePattern accepts a regular expression
+func checkValuePattern(value string) error {
+	return logger.CheckRedactRule(logger.RedactRule{Key: "*", Value: value})
+}
+
+// checkRedactRule requires a key or a value pattern in a log.redact table
+func checkRedactRule(table map[string]any) error {
+	key, _ := table["key"].(string)
+	value, _ := table["value"].(string)
+	if key == "" && value == "" {
+		return fmt.Errorf("needs a key or a value pattern")
+	}
+	return nil
+}
+
+// checkOutput accepts stderr, stdout, a file path or a file:// URL
+func checkOutput(value string) error {
+	if value == "" {
+		return fmt.Errorf("must be stderr, stdout, a file path or a file:// URL")
+	}
+	_, err := logger.OutputPath(value)
+	return err
+}
+
+// flatten turns nested maps into dotted keys. Maps that are not under a
+// known key are descended into; anything else is a leaf.
+func flatten(prefix string, values map[string]any) map[string]any {
+	flat := make(map[string]any)
+	for name, value := range values {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		nested, isMap := value.(map[string]any)
+		if _, known := Lookup(name); isMap && !known {
+			for k, v := range flatten(name, nested) {
+				flat[k] = v
+			}
+			continue
+		}
+		flat[name] = value
+	}
+	return flat
+}
+
+// FormatValue renders a setting value for text output: lists and tables
+// as JSON, anything else as with fmt.Sprint
+func FormatValue(value any) string {
+	switch value.(type) {
+	case []any, map[string]any:
+		if data, err := json.Marshal(value); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// Source identifies where the effective value of a key came from
+type Source string
+
+// Sources in increasing order of precedence
+const (
+	SourceDefault   Source = "default"
+	SourceFile      Source = "file"
+	SourceProfile   Source = "profile"
+	SourceLegacyEnv Source = "legacy-env"
+	SourceEnv       Source = "env"
+	SourceFlag      Source = "flag"
+)
+
+// Setting is the effective value of a key
+type Setting struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Source Source `json:"source"`
+	Origin string `json:"origin,omitempty"` // Flag, variable, profile or file providing the value
+
+	project    string      // Project file providing the value, whose references are not resolved
+	resolution *resolution // Resolves the references in Value once
+}
+
+// Sources are the places settings are read from
+type Sources struct {
+	Flags     *pflag.FlagSet              // Global flags bound to keys
+	LookupEnv func(string) (string, bool) // Environment, usually os.LookupEnv
+	Files     Layers                      // Config files read
+	Profile   string                      // Profile to apply, if any
+}
+
+// Resolve returns the effective value of every known key and where it
+// came from, the first of its candidates in the order given by Explain.
+// Secret references are left as written until Setting.Expand or
+// Config.Expand resolves them, so only the keys a command reads run
+// commands or read files.
+func Resolve(src Sources) ([]Setting, error) {
+	explanations, err := Explain(src)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make([]Setting, 0, len(explanations))
+	for _, e := range explanations {
+		s := e.Candidates[0]
+		if holdsReference(s.Value) {
+			s.resolution = &resolution{lookupEnv: src.LookupEnv}
+		}
+		settings = append(settings, s)
+	}
+	return settings, nil
+}
+
+// Explanation lists every value set for a key, most important first. The
+// first candidate is the effective value; the last is the default.
+type Explanation struct {
+	Key        string    `json:"key"`
+	Candidates []Setting `json:"candidates"`
+}
+
+// Explain returns the candidate values of every known key in order of
+// precedence: a changed flag, then the HELLO_WORLD_CLI_* environment
+// variable, then the unprefixed legacy variable such as LOG_LEVEL, then the
+// selected profile and the profiles it extends, then the top level of the
+// config files, then the default. Later files win over earlier ones. Secret
+// references are left as written.
+func Explain(src Sources) ([]Explanation, error) {
+	var chain []string
+	if src.Profile != "" {
+		var err error
+		if chain, err = src.Files.ProfileChain(src.Profile); err != nil {
+			return nil, err
+		}
+	}
+
+	explanations := make([]Explanation, 0, len(Keys))
+	for _, key := range Keys {
+		explanations = append(explanations, Explanation{Key: key.Name, Candidates: candidates(key, src, chain)})
+	}
+	return explanations, nil
+}
+
+func candidates(key Key, src Sources, chain []string) []Setting {
+	var found []Setting
+	if src.Flags != nil && key.Flag != "" {
+		if f := src.Flags.Lookup(key.Flag); f != nil && f.Changed {
+			found = append(found, Setting{Key: key.Name, Value: typed(key, f.Value.String()), Source: SourceFlag, Origin: "--" + key.Flag})
+		}
+	}
+
+	if src.LookupEnv != nil {
+		env := EnvVar(key.Name)
+		if raw, ok := src.LookupEnv(env); ok && raw != "" {
+			found = append(found, Setting{Key: key.Name, Value: typed(key, raw), Source: SourceEnv, Origin: env})
+		}
+		if key.LegacyEnv != "" {
+			if raw, ok := src.LookupEnv(key.LegacyEnv); ok && raw != "" {
+				found = append(found, Setting{Key: key.Name, Value: typed(key, raw), Source: SourceLegacyEnv, Origin: key.LegacyEnv})
+			}
+		}
+	}
+
+	for _, name := range chain {
+		for i := len(src.Files) - 1; i >= 0; i-- {
+			file := src.Files[i]
+			if value, ok := lookup(file.profile(name), key.Name); ok {
+				origin := ProfilesKey + "." + name + " in " + file.Path
+				found = append(found, Setting{Key: key.Name, Value: value, Source: SourceProfile, Origin: origin, project: file.project()})
+			}
+		}
+	}
+
+	for i := len(src.Files) - 1; i >= 0; i-- {
+		if value, ok := src.Files[i].Get(key.Name); ok {
+			found = append(found, Setting{Key: key.Name, Value: value, Source: SourceFile, Origin: src.Files[i].Path, project: src.Files[i].project()})
+		}
+	}
+
+	return append(found, Setting{Key: key.Name, Value: key.Default, Source: SourceDefault})
+}
+
+// typed converts a flag or environment string to the key's type, keeping
+// the string when it does not parse
+func typed(key Key, raw string) any {
+	switch key.Type {
+	case TypeBool:
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	case TypeInt:
+		if value, err := strconv.Atoi(raw); err == nil {
+			return value
+		}
+	case TypeList:
+		if value, ok := parseList(raw); ok {
+			return value
+		}
+	case TypeMap:
+		if value, ok := parseMap(raw); ok {
+			return value
+		}
+	}
+	return raw
+}
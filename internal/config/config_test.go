@@ -0,0 +1,333 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestEnvVar(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "debug", want: "HELLO_WORLD_CLI_DEBUG"},
+		{key: "log.level", want: "HELLO_WORLD_CLI_LOG_LEVEL"},
+		{key: "locales.dir", want: "HELLO_WORLD_CLI_LOCALES_DIR"},
+	}
+
+	for _, tt := range tests {
+		if got := EnvVar(tt.key); got != tt.want {
+			t.Errorf("EnvVar(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		key     string
+		raw     string
+		want    any
+		wantErr bool
+	}{
+		{key: "log.level", raw: "debug", want: "debug"},
+		{key: "log.level", raw: "loud", wantErr: true},
+		{key: "verbose", raw: "true", want: true},
+		{key: "verbose", raw: "yes", wantErr: true},
+		{key: "locales.dir", raw: "/tmp/locales", want: "/tmp/locales"},
+		{key: "greeting.language", raw: "pt-BR", want: "pt-BR"},
+		{key: "greeting.language", raw: "not a tag", wantErr: true},
+		{key: "greeting.language", raw: "${env:LANGUAGE}", want: "${env:LANGUAGE}"},
+		{key: "verbose", raw: "${file:/run/secrets/verbose}", wantErr: true},
+		{key: "log.max_backups", raw: "${env:BACKUPS}", wantErr: true},
+		{key: "log.max_backups", raw: "3", want: 3},
+		{key: "log.max_backups", raw: "-1", wantErr: true},
+		{key: "log.max_backups", raw: "many", wantErr: true},
+		{key: "log.max_size", raw: "10MB", want: "10MB"},
+		{key: "log.max_size", raw: "big", wantErr: true},
+		{key: "log.max_age", raw: "7d", want: "7d"},
+		{key: "log.max_age", raw: "a week", wantErr: true},
+		{key: "log.output", raw: "file:///var/log/app.log", want: "file:///var/log/app.log"},
+		{key: "log.output", raw: "file://server/app.log", wantErr: true},
+		{key: "log.sinks", raw: `[{"output": "debug.log", "level": "debug"}]`, want: []any{map[string]any{"output": "debug.log", "level": "debug"}}},
+		{key: "log.sinks", raw: `[{"format": "xml"}]`, wantErr: true},
+		{key: "log.sinks", raw: "debug.log", wantErr: true},
+		{key: "log.sampling.interval", raw: "10s", want: "10s"},
+		{key: "log.sampling.interval", raw: "often", wantErr: true},
+		{key: "log.sampling.first", raw: "-5", wantErr: true},
+		{key: "log.levels", raw: `{"greeting": "debug"}`, want: map[string]any{"greeting": "debug"}},
+		{key: "log.levels", raw: `{"greeting": "loud"}`, wantErr: true},
+		{key: "log.levels", raw: `{"greeting": 1}`, wantErr: true},
+		{key: "log.levels", raw: "greeting=debug", wantErr: true},
+		{key: "log.redact", raw: `[{"key": "name", "action": "hash"}]`, want: []any{map[string]any{"key": "name", "action": "hash"}}},
+		{key: "log.redact", raw: `[{"value": "[0-9]{16}"}]`, want: []any{map[string]any{"value": "[0-9]{16}"}}},
+		{key: "log.redact", raw: `[{"value": "(unclosed"}]`, wantErr: true},
+		{key: "log.redact", raw: `[{"key": "[name"}]`, wantErr: true},
+		{key: "log.redact", raw: `[{"key": "name", "action": "shred"}]`, wantErr: true},
+		{key: "log.redact", raw: `[{"action": "drop"}]`, wantErr: true},
+		{key: "bogus", raw: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.raw, func(t *testing.T) {
+			got, err := ParseValue(tt.key, tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]any
+		wantKeys []string
+	}{
+		{
+			name: "valid",
+			values: map[string]any{
+				"verbose": true,
+				"log":     map[string]any{"level": "warn", "format": "json"},
+			},
+		},
+		{
+			name:     "unknown keys",
+			values:   map[string]any{"bogus": 1, "log": map[string]any{"colour": "red"}},
+			wantKeys: []string{"bogus", "log.colour"},
+		},
+		{
+			name:     "wrong types and values",
+			values:   map[string]any{"debug": "yes", "log": map[string]any{"level": "loud", "format": 3}},
+			wantKeys: []string{"debug", "log.format", "log.level"},
+		},
+		{
+			name: "whole numbers",
+			values: map[string]any{
+				"log":      map[string]any{"max_backups": float64(3)},
+				"profiles": map[string]any{"ci": map[string]any{"log": map[string]any{"max_backups": 2.5}}},
+			},
+			wantKeys: []string{"profiles.ci.log.max_backups"},
+		},
+		{
+			name:     "secret references only in strings",
+			values:   map[string]any{"debug": "${env:DEBUG}", "greeting": map[string]any{"language": "${env:LANGUAGE}"}},
+			wantKeys: []string{"debug"},
+		},
+		{
+			name: "sinks",
+			values: map[string]any{"log": map[string]any{"sinks": []any{
+				map[string]any{"output": "debug.log", "level": "debug", "format": "json", "max_backups": 2},
+				map[string]any{"output": "stdout", "colour": "red"},
+			}}},
+			wantKeys: []string{"log.sinks[1].colour"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			for _, problem := range Validate(tt.values) {
+				keys = append(keys, problem.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("Validate() problem keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	xdg := filepath.Join(home, "xdg", "hello-world-cli", "config.yaml")
+	legacy := filepath.Join(home, ".hello-world-cli.yaml")
+
+	steps := []struct {
+		name   string
+		create string
+		want   string
+	}{
+		{name: "no files", want: xdg},
+		{name: "legacy dotfile", create: legacy, want: legacy},
+		{name: "xdg file wins", create: xdg, want: xdg},
+	}
+
+	for _, step := range steps {
+		if step.create != "" {
+			if err := WriteFileAtomic(step.create, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := DefaultPath()
+		if err != nil {
+			t.Fatalf("%s: DefaultPath() error = %v", step.name, err)
+		}
+		if got != step.want {
+			t.Errorf("%s: DefaultPath() = %q, want %q", step.name, got, step.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	file := &File{Path: "config.yaml", format: "json", values: map[string]any{
+		"log":     map[string]any{"level": "warn", "format": "json"},
+		"verbose": true,
+	}}
+	env := map[string]string{
+		"HELLO_WORLD_CLI_LOG_LEVEL":  "error",
+		"HELLO_WORLD_CLI_DEBUG":      "true",
+		"HELLO_WORLD_CLI_LOG_FORMAT": "", // empty is unset
+	}
+	lookupEnv := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Bool("debug", false, "")
+	flags.String("log-level", "", "")
+	flags.String("log-format", "", "")
+	flags.String("locales-dir", "", "")
+	flags.Bool("verbose", false, "")
+	if err := flags.Parse([]string{"--debug=false"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Setting{
+		{Key: "debug", Value: false, Source: SourceFlag, Origin: "--debug"},
+		{Key: "greeting.emoji", Value: false, Source: SourceDefault},
+		{Key: "greeting.language", Value: "", Source: SourceDefault},
+		{Key: "locales.dir", Value: "", Source: SourceDefault},
+		{Key: "log.compress", Value: false, Source: SourceDefault},
+		{Key: "log.format", Value: "json", Source: SourceFile, Origin: "config.yaml"},
+		{Key: "log.level", Value: "error", Source: SourceEnv, Origin: "HELLO_WORLD_CLI_LOG_LEVEL"},
+		{Key: "log.levels", Value: map[string]any{}, Source: SourceDefault},
+		{Key: "log.max_age", Value: "", Source: SourceDefault},
+		{Key: "log.max_backups", Value: 5, Source: SourceDefault},
+		{Key: "log.max_size", Value: "100MB", Source: SourceDefault},
+		{Key: "log.output", Value: "stderr", Source: SourceDefault},
+		{Key: "log.redact", Value: redactDefaults(), Source: SourceDefault},
+		{Key: "log.rotate_interval", Value: "", Source: SourceDefault},
+		{Key: "log.sampling.burst", Value: 0, Source: SourceDefault},
+		{Key: "log.sampling.first", Value: 0, Source: SourceDefault},
+		{Key: "log.sampling.interval", Value: "1s", Source: SourceDefault},
+		{Key: "log.sampling.rate", Value: 0, Source: SourceDefault},
+		{Key: "log.sampling.thereafter", Value: 0, Source: SourceDefault},
+		{Key: "log.sinks", Value: []any{}, Source: SourceDefault},
+		{Key: "telemetry.endpoint", Value: "", Source: SourceDefault},
+		{Key: "telemetry.exporter", Value: "none", Source: SourceDefault},
+		{Key: "telemetry.file", Value: "", Source: SourceDefault},
+		{Key: "telemetry.insecure", Value: false, Source: SourceDefault},
+		{Key: "telemetry.protocol", Value: "http", Source: SourceDefault},
+		{Key: "verbose", Value: true, Source: SourceFile, Origin: "config.yaml"},
+	}
+	got, err := Resolve(Sources{Flags: flags, LookupEnv: lookupEnv, Files: Layers{file}})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() = %+v, want %+v", got, want)
+	}
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	// Every combination of sources setting log.level: candidates follow the
+	// precedence order and the highest one present wins
+	sources := []Source{SourceFlag, SourceEnv, SourceLegacyEnv, SourceProfile, SourceFile}
+
+	for mask := 0; mask < 1<<len(sources); mask++ {
+		present := make(map[Source]bool)
+		var name []string
+		for i, src := range sources {
+			if mask&(1<<i) != 0 {
+				present[src] = true
+				name = append(name, string(src))
+			}
+		}
+		if len(name) == 0 {
+			name = []string{"none"}
+		}
+
+		t.Run(strings.Join(name, "+"), func(t *testing.T) {
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.String("log-level", "", "")
+			var args []string
+			if present[SourceFlag] {
+				args = append(args, "--log-level", "error")
+			}
+			if err := flags.Parse(args); err != nil {
+				t.Fatal(err)
+			}
+
+			env := map[string]string{}
+			if present[SourceEnv] {
+				env["HELLO_WORLD_CLI_LOG_LEVEL"] = "warn"
+			}
+			if present[SourceLegacyEnv] {
+				env["LOG_LEVEL"] = "debug"
+			}
+			lookupEnv := func(name string) (string, bool) {
+				v, ok := env[name]
+				return v, ok
+			}
+
+			values := map[string]any{}
+			if present[SourceFile] {
+				values["log"] = map[string]any{"level": "warn"}
+			}
+			if present[SourceProfile] {
+				values[ProfilesKey] = map[string]any{"ci": map[string]any{"log": map[string]any{"level": "info"}}}
+			} else {
+				values[ProfilesKey] = map[string]any{"ci": map[string]any{}}
+			}
+			file := &File{Path: "config.yaml", format: "json", values: values}
+
+			explanations, err := Explain(Sources{Flags: flags, LookupEnv: lookupEnv, Files: Layers{file}, Profile: "ci"})
+			if err != nil {
+				t.Fatalf("Explain() error = %v", err)
+			}
+			var got []Source
+			for _, c := range explanationOf(explanations, "log.level").Candidates {
+				got = append(got, c.Source)
+			}
+
+			var want []Source
+			for _, src := range sources {
+				if present[src] {
+					want = append(want, src)
+				}
+			}
+			want = append(want, SourceDefault)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("candidate sources = %v, want %v", got, want)
+			}
+
+			settings, err := Resolve(Sources{Flags: flags, LookupEnv: lookupEnv, Files: Layers{file}, Profile: "ci"})
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			for _, s := range settings {
+				if s.Key == "log.level" && s.Source != want[0] {
+					t.Errorf("Resolve() log.level source = %v, want %v", s.Source, want[0])
+				}
+			}
+		})
+	}
+}
+
+func explanationOf(explanations []Explanation, key string) Explanation {
+	for _, e := range explanations {
+		if e.Key == key {
+			return e
+		}
+	}
+	return Explanation{}
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+// AppName names the config directory and files
+const AppName = "hello-world-cli" // TODO: Replace with your app name
+
+// Scope names the layer a config file belongs to
+type Scope string
+
+// Scopes in increasing order of precedence
+const (
+	ScopeSystem  Scope = "system"
+	ScopeUser    Scope = "user"
+	ScopeLegacy  Scope = "legacy"
+	ScopeProject Scope = "project"
+	ScopeFlag    Scope = "flag" // File given with --config, read alone
+)
+
+// SystemPath is the config file shared by all users
+var SystemPath = filepath.Join("/etc", AppName, "config.yaml")
+
+// ProjectFile is the name of project config files, looked up from the
+// working directory towards the root
+const ProjectFile = "." + AppName + ".yaml"
+
+// userFiles are the XDG config file names, the first existing one is read
+var userFiles = []string{"config.yaml", "config.toml", "config.json"}
+
+// Location is a config file location consulted while loading
+type Location struct {
+	Scope  Scope  `json:"scope"`
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+	Err    error  `json:"-"` // Why an existing file could not be read
+}
+
+// Status describes whether the file at a location was read
+func (l Location) Status() string {
+	switch {
+	case l.Err != nil:
+		return "error: " + l.Err.Error()
+	case l.Exists:
+		return "read"
+	default:
+		return "not found"
+	}
+}
+
+// Layers are config files in increasing order of precedence
+type Layers []*File
+
+// Discover returns the config file locations to consult, in increasing
+// order of precedence: the system file, the XDG user file, the legacy
+// dotfile in the home directory and the nearest project file at or above
+// dir. Only the first existing XDG file is consulted.
+func Discover(dir string) ([]Location, error) {
+	locations := []Location{locate(ScopeSystem, SystemPath)}
+
+	userDir, err := UserDir()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range userFiles {
+		loc := locate(ScopeUser, filepath.Join(userDir, name))
+		locations = append(locations, loc)
+		if loc.Exists {
+			break
+		}
+	}
+
+	legacy, err := LegacyPath()
+	if err != nil {
+		return nil, err
+	}
+	locations = append(locations, locate(ScopeLegacy, legacy))
+
+	if project := findProjectFile(dir, legacy); project != "" {
+		locations = append(locations, locate(ScopeProject, project))
+	}
+	return locations, nil
+}
+
+// Load reads the config files found by Discover from dir, or only path
+// when it is not empty. Files that cannot be read are skipped and reported
+// in their location's Err.
+func Load(path, dir string) (Layers, []Location, error) {
+	var locations []Location
+	if path != "" {
+		locations = []Location{locate(ScopeFlag, path)}
+	} else {
+		var err error
+		if locations, err = Discover(dir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var layers Layers
+	for i, loc := range locations {
+		if !loc.Exists {
+			continue
+		}
+		file, err := ReadFile(loc.Path)
+		if err != nil {
+			locations[i].Err = err
+			continue
+		}
+		file.Scope = loc.Scope
+		layers = append(layers, file)
+	}
+	return layers, locations, nil
+}
+
+// UserPath returns the XDG config file,
+// $XDG_CONFIG_HOME/hello-world-cli/config.yaml
+func UserPath() (string, error) {
+	dir, err := UserDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, userFiles[0]), nil
+}
+
+// LegacyPath returns the config file in the home directory used before
+// XDG support, $HOME/.hello-world-cli.yaml
+func LegacyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, errors.CodeConfig, "cannot locate the home directory")
+	}
+	return filepath.Join(home, "."+AppName+".yaml"), nil
+}
+
+// DefaultPath returns the config file edited when --config is not given:
+// the existing XDG config file, else the legacy dotfile if it exists, else
+// the XDG config.yaml
+func DefaultPath() (string, error) {
+	dir, err := UserDir()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range userFiles {
+		if path := filepath.Join(dir, name); exists(path) {
+			return path, nil
+		}
+	}
+	if legacy, err := LegacyPath(); err == nil && exists(legacy) {
+		return legacy, nil
+	}
+	return filepath.Join(dir, userFiles[0]), nil
+}
+
+// UserDir returns the user config directory, $XDG_CONFIG_HOME/hello-world-cli,
+// defaulting XDG_CONFIG_HOME to ~/.config
+func UserDir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", errors.Wrap(err, errors.CodeConfig, "cannot locate the home directory")
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, AppName), nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func locate(scope Scope, path string) Location {
+	return Location{Scope: scope, Path: path, Exists: exists(path)}
+}
+
+// findProjectFile returns the nearest project file at or above dir, not
+// counting the legacy dotfile in the home directory
+func findProjectFile(dir, legacy string) string {
+	if dir == "" {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if path != legacy && exists(path) {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Values returns the contents of all layers merged, later layers winning
+func (l Layers) Values() map[string]any {
+	merged := map[string]any{}
+	for _, file := range l {
+		mergeInto(merged, file.Values())
+	}
+	return merged
+}
+
+// project returns the path of a project file, whose secret references
+// are not resolved, or "" for the other layers
+func (f *File) project() string {
+	if f.Scope == ScopeProject {
+		return f.Path
+	}
+	return ""
+}
+
+// mergeInto deep-merges src into dst, copying nested maps so src is not
+// shared
+func mergeInto(dst, src map[string]any) {
+	for key, value := range src {
+		nested, ok := value.(map[string]any)
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		target, ok := dst[key].(map[string]any)
+		if !ok {
+			target = map[string]any{}
+			dst[key] = target
+		}
+		mergeInto(target, nested)
+	}
+}
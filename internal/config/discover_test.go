@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates files with the given contents under root
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", filepath.Join(root, "home"))
+	t.Setenv("XDG_CONFIG_HOME", "")
+	systemPath := SystemPath
+	SystemPath = filepath.Join(root, "etc", "config.yaml")
+	t.Cleanup(func() { SystemPath = systemPath })
+
+	writeFiles(t, root, map[string]string{
+		"etc/config.yaml":                           "log:\n  level: debug\n  format: json\nverbose: true\n",
+		"home/.config/hello-world-cli/config.toml":  "[log]\nlevel = \"info\"\n",
+		"home/.hello-world-cli.yaml":                "greeting:\n  language: fr\n",
+		"home/work/.hello-world-cli.yaml":           "log:\n  level: warn\n",
+		"home/work/app/.hello-world-cli.yaml":       "log: [",
+		"home/work/app/src/.keep":                   "",
+		"home/work/other/.hello-world-cli.yaml.bak": "",
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		dir        string
+		wantScopes []Scope
+		wantValues map[string]any
+		wantErrAt  string
+	}{
+		{
+			name:       "all layers",
+			dir:        filepath.Join(root, "home", "work", "other"),
+			wantScopes: []Scope{ScopeSystem, ScopeUser, ScopeUser, ScopeLegacy, ScopeProject},
+			wantValues: map[string]any{
+				"log":      map[string]any{"level": "warn", "format": "json"},
+				"verbose":  true,
+				"greeting": map[string]any{"language": "fr"},
+			},
+		},
+		{
+			name:       "unreadable project file is skipped",
+			dir:        filepath.Join(root, "home", "work", "app", "src"),
+			wantScopes: []Scope{ScopeSystem, ScopeUser, ScopeUser, ScopeLegacy, ScopeProject},
+			wantValues: map[string]any{
+				"log":      map[string]any{"level": "info", "format": "json"},
+				"verbose":  true,
+				"greeting": map[string]any{"language": "fr"},
+			},
+			wantErrAt: filepath.Join(root, "home", "work", "app", ".hello-world-cli.yaml"),
+		},
+		{
+			name:       "home dotfile is not a project file",
+			dir:        filepath.Join(root, "home"),
+			wantScopes: []Scope{ScopeSystem, ScopeUser, ScopeUser, ScopeLegacy},
+			wantValues: map[string]any{
+				"log":      map[string]any{"level": "info", "format": "json"},
+				"verbose":  true,
+				"greeting": map[string]any{"language": "fr"},
+			},
+		},
+		{
+			name:       "explicit file is read alone",
+			path:       filepath.Join(root, "home", "work", ".hello-world-cli.yaml"),
+			dir:        filepath.Join(root, "home", "work"),
+			wantScopes: []Scope{ScopeFlag},
+			wantValues: map[string]any{"log": map[string]any{"level": "warn"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layers, locations, err := Load(tt.path, tt.dir)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			var scopes []Scope
+			for _, loc := range locations {
+				scopes = append(scopes, loc.Scope)
+				if (loc.Err != nil) != (loc.Path == tt.wantErrAt) {
+					t.Errorf("location %s error = %v", loc.Path, loc.Err)
+				}
+			}
+			if !reflect.DeepEqual(scopes, tt.wantScopes) {
+				t.Errorf("Load() scopes = %v, want %v", scopes, tt.wantScopes)
+			}
+			if got := layers.Values(); !reflect.DeepEqual(got, tt.wantValues) {
+				t.Errorf("Load() values = %v, want %v", got, tt.wantValues)
+			}
+		})
+	}
+}
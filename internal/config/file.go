@@ -0,0 +1,322 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// File is a config file opened for reading and editing. YAML files keep
+// their comments and key order when saved.
+type File struct {
+	Path   string
+	Exists bool  // Whether the file existed when read
+	Scope  Scope // Layer the file was loaded as, if read by Load
+
+	format string
+	doc    *yaml.Node     // YAML document
+	values map[string]any // JSON and TOML contents
+}
+
+// ReadFile reads a YAML, JSON or TOML config file, chosen by extension. A
+// missing file reads as empty so it can be created by Save.
+func ReadFile(path string) (*File, error) {
+	f := &File{Path: path, format: formatOf(path)}
+
+	data, err := os.ReadFile(path) //nolint:gosec // Config paths are user-supplied by design
+	if stderrors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, &errors.FileError{Path: path, Operation: "read", Err: err}
+	}
+	f.Exists = true
+
+	switch f.format {
+	case "json":
+		err = json.Unmarshal(data, &f.values)
+	case "toml":
+		err = toml.Unmarshal(data, &f.values)
+	default:
+		var doc yaml.Node
+		err = yaml.Unmarshal(data, &doc)
+		if err == nil && doc.Kind != 0 && (len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode) {
+			err = stderrors.New("top level must be a mapping of keys")
+		}
+		f.doc = &doc
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeConfigParse, "cannot parse config file %s", path)
+	}
+	return f, nil
+}
+
+// formatOf returns the config format for a file name, defaulting to YAML
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	default:
+		return "yaml"
+	}
+}
+
+// Values returns the file contents as nested maps
+func (f *File) Values() map[string]any {
+	if f.format != "yaml" {
+		if f.values == nil {
+			return map[string]any{}
+		}
+		return f.values
+	}
+
+	values := map[string]any{}
+	if f.doc != nil && f.doc.Kind != 0 {
+		_ = f.doc.Decode(&values)
+	}
+	return values
+}
+
+// Get returns the value of a dotted key
+func (f *File) Get(name string) (any, bool) {
+	return lookup(f.Values(), name)
+}
+
+// lookup returns the value of a dotted key in nested maps
+func lookup(values map[string]any, name string) (any, bool) {
+	var current any = values
+	for _, part := range strings.Split(name, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Set sets a dotted key, creating parent tables as needed
+func (f *File) Set(name string, value any) error {
+	parts := strings.Split(name, ".")
+	if f.format != "yaml" {
+		if f.values == nil {
+			f.values = map[string]any{}
+		}
+		setNested(f.values, parts, value)
+		return nil
+	}
+
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return errors.Wrapf(err, errors.CodeInvalidArgument, "cannot encode value for %s", name)
+	}
+
+	mapping := f.rootMapping()
+	for i, part := range parts {
+		// Write into empty tables like {} in block style
+		if len(mapping.Content) == 0 {
+			mapping.Style &^= yaml.FlowStyle
+		}
+		last := i == len(parts)-1
+		idx := mappingIndex(mapping, part)
+		switch {
+		case idx < 0 && last:
+			mapping.Content = append(mapping.Content, scalarKey(part), &node)
+		case idx < 0:
+			child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			mapping.Content = append(mapping.Content, scalarKey(part), child)
+			mapping = child
+		case last:
+			// Keep comments attached to the old value
+			old := mapping.Content[idx+1]
+			node.HeadComment, node.LineComment, node.FootComment = old.HeadComment, old.LineComment, old.FootComment
+			mapping.Content[idx+1] = &node
+		default:
+			child := mapping.Content[idx+1]
+			if child.Kind != yaml.MappingNode {
+				child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				mapping.Content[idx+1] = child
+			}
+			mapping = child
+		}
+	}
+	return nil
+}
+
+// Unset removes a dotted key and any tables it leaves empty, reporting
+// whether the key was present
+func (f *File) Unset(name string) bool {
+	parts := strings.Split(name, ".")
+	if f.format != "yaml" {
+		return unsetNested(f.values, parts)
+	}
+	if f.doc == nil || f.doc.Kind == 0 {
+		return false
+	}
+	return unsetNode(f.rootMapping(), parts)
+}
+
+// Save writes the file atomically, so readers never see a partial file
+// and a failed write leaves the old contents in place
+func (f *File) Save() error {
+	var (
+		data []byte
+		err  error
+	)
+	switch f.format {
+	case "json":
+		data, err = json.MarshalIndent(f.Values(), "", "  ")
+		data = append(data, '\n')
+	case "toml":
+		data, err = toml.Marshal(f.Values())
+	default:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if f.doc != nil && f.doc.Kind != 0 {
+			err = enc.Encode(f.doc)
+		}
+		if err == nil {
+			err = enc.Close()
+		}
+		data = buf.Bytes()
+	}
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "cannot encode config file %s", f.Path)
+	}
+
+	if err := WriteFileAtomic(f.Path, data); err != nil {
+		return err
+	}
+	f.Exists = true
+	return nil
+}
+
+// WriteFileAtomic writes data to a temporary file next to path and renames
+// it into place, keeping the permissions of an existing file
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return &errors.FileError{Path: dir, Operation: "create", Err: err}
+	}
+
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return &errors.FileError{Path: path, Operation: "write", Err: err}
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // Already renamed on success
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return &errors.FileError{Path: path, Operation: "write", Err: err}
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return &errors.FileError{Path: path, Operation: "write", Err: err}
+	}
+	if err := tmp.Close(); err != nil {
+		return &errors.FileError{Path: path, Operation: "write", Err: err}
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return &errors.FileError{Path: path, Operation: "write", Err: err}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return &errors.FileError{Path: path, Operation: "write", Err: err}
+	}
+	return nil
+}
+
+// rootMapping returns the top-level mapping of the YAML document,
+// creating it for an empty file
+func (f *File) rootMapping() *yaml.Node {
+	if f.doc == nil || f.doc.Kind == 0 {
+		f.doc = &yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+	return f.doc.Content[0]
+}
+
+// mappingIndex returns the index of a key in a mapping node's content, or -1
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func scalarKey(name string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+}
+
+func unsetNode(mapping *yaml.Node, parts []string) bool {
+	idx := mappingIndex(mapping, parts[0])
+	if idx < 0 {
+		return false
+	}
+	if len(parts) == 1 {
+		mapping.Content = append(mapping.Content[:idx], mapping.Content[idx+2:]...)
+		return true
+	}
+
+	child := mapping.Content[idx+1]
+	if child.Kind != yaml.MappingNode || !unsetNode(child, parts[1:]) {
+		return false
+	}
+	if len(child.Content) == 0 {
+		mapping.Content = append(mapping.Content[:idx], mapping.Content[idx+2:]...)
+	}
+	return true
+}
+
+func setNested(values map[string]any, parts []string, value any) {
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := values[part].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			values[part] = child
+		}
+		values = child
+	}
+	values[parts[len(parts)-1]] = value
+}
+
+func unsetNested(values map[string]any, parts []string) bool {
+	if values == nil {
+		return false
+	}
+	if len(parts) == 1 {
+		_, ok := values[parts[0]]
+		delete(values, parts[0])
+		return ok
+	}
+
+	child, ok := values[parts[0]].(map[string]any)
+	if !ok || !unsetNested(child, parts[1:]) {
+		return false
+	}
+	if len(child) == 0 {
+		delete(values, parts[0])
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+func TestFileEditYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := "# Settings\nlog:\n  # How much to log\n  level: info # default\nverbose: true\n"
+	if err := os.WriteFile(path, []byte(original), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if err := f.Set("log.level", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("locales.dir", "/tmp/locales"); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Unset("verbose") {
+		t.Error("Unset(verbose) = false, want true")
+	}
+	if f.Unset("log.format") {
+		t.Error("Unset(log.format) = true, want false")
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Settings\nlog:\n  # How much to log\n  level: debug # default\nlocales:\n  dir: /tmp/locales\n"
+	if string(data) != want {
+		t.Errorf("saved file = %q, want %q", data, want)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("mode = %v, want 0640", info.Mode().Perm())
+	}
+}
+
+func TestFileEditFormats(t *testing.T) {
+	for _, name := range []string{"config.json", "config.toml", "new/config.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+
+			f, err := ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if f.Exists {
+				t.Error("Exists = true for a missing file")
+			}
+			if err := f.Set("log.level", "warn"); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.Set("debug", true); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.Save(); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			f, err = ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() after save error = %v", err)
+			}
+			if got, _ := f.Get("log.level"); got != "warn" {
+				t.Errorf("Get(log.level) = %v, want warn", got)
+			}
+			if got, _ := f.Get("debug"); got != true {
+				t.Errorf("Get(debug) = %v, want true", got)
+			}
+
+			if !f.Unset("log.level") {
+				t.Error("Unset(log.level) = false, want true")
+			}
+			if _, ok := f.Get("log"); ok {
+				t.Error("empty log table was not removed")
+			}
+		})
+	}
+}
+
+func TestReadFileInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "config.yaml", content: "log: [unclosed"},
+		{name: "list.yaml", content: "- debug\n- verbose\n"},
+		{name: "config.json", content: "{"},
+		{name: "config.toml", content: "log = "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ReadFile(path)
+			if got := errors.GetCode(err); got != errors.CodeConfigParse {
+				t.Errorf("ReadFile() code = %v, want %v (error %v)", got, errors.CodeConfigParse, err)
+			}
+		})
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	f, err := Scaffold(path, map[string]any{"log.level": "warn", "greeting.emoji": true})
+	if err != nil {
+		t.Fatalf("Scaffold() error = %v", err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Configuration for hello-world-cli",
+		"greeting:\n  # Include emoji in greetings\n  emoji: true\n",
+		"log:\n  # Minimum log level: debug, info, warn, error\n  level: warn\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("scaffold = %q, want substring %q", data, want)
+		}
+	}
+
+	f, err = ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if problems := Validate(f.Values()); len(problems) > 0 {
+		t.Errorf("Validate() = %v, want no problems", problems)
+	}
+}
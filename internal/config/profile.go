@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/spf13/pflag"
+)
+
+// ProfilesKey is the config file table holding named profiles. Each
+// profile sets keys like the top level of the file and may name another
+// profile to inherit from with extends; the top level is the base of
+// every profile.
+const ProfilesKey = "profiles"
+
+// extendsKey names the profile a profile inherits from
+const extendsKey = "extends"
+
+// ProfileFlag is the global flag selecting a profile
+const ProfileFlag = "profile"
+
+// ProfileName returns the selected profile: the --profile flag, then
+// HELLO_WORLD_CLI_PROFILE. It is empty when no profile is selected.
+func ProfileName(flags *pflag.FlagSet, lookupEnv func(string) (string, bool)) string {
+	if flags != nil {
+		if f := flags.Lookup(ProfileFlag); f != nil && f.Value.String() != "" {
+			return f.Value.String()
+		}
+	}
+	if lookupEnv != nil {
+		if name, ok := lookupEnv(EnvVar(ProfileFlag)); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// ProfileChain returns the named profile followed by the profiles it
+// extends, most specific first. Profiles may be defined in any layer.
+func (l Layers) ProfileChain(name string) ([]string, error) {
+	profiles, _ := l.Values()[ProfilesKey].(map[string]any)
+	return profileChain(profiles, name)
+}
+
+// ProfileValues returns the keys set by a profile and the profiles it
+// extends, without the top level of the files
+func (l Layers) ProfileValues(name string) (map[string]any, error) {
+	chain, err := l.ProfileChain(name)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, _ := l.Values()[ProfilesKey].(map[string]any)
+	merged := map[string]any{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		values, _ := profiles[chain[i]].(map[string]any)
+		mergeInto(merged, settingsOf(values))
+	}
+	return merged, nil
+}
+
+// profile returns the keys set directly by a profile
+func (f *File) profile(name string) map[string]any {
+	profiles, _ := f.Values()[ProfilesKey].(map[string]any)
+	values, _ := profiles[name].(map[string]any)
+	return settingsOf(values)
+}
+
+// settingsOf returns the keys of a profile table other than extends
+func settingsOf(profile map[string]any) map[string]any {
+	settings := make(map[string]any, len(profile))
+	for key, value := range profile {
+		if key != extendsKey {
+			settings[key] = value
+		}
+	}
+	return settings
+}
+
+func profileChain(profiles map[string]any, name string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+	for name != "" {
+		values, ok := profiles[name].(map[string]any)
+		if !ok {
+			return nil, unknownProfile(name)
+		}
+		if seen[name] {
+			return nil, &errors.ConfigError{
+				Key:     ProfilesKey + "." + chain[len(chain)-1] + "." + extendsKey,
+				Value:   name,
+				Message: "profiles extend each other in a cycle",
+			}
+		}
+		seen[name] = true
+		chain = append(chain, name)
+		name, _ = values[extendsKey].(string)
+	}
+	return chain, nil
+}
+
+func unknownProfile(name string) *errors.ConfigError {
+	return &errors.ConfigError{Key: ProfileFlag, Value: name, Message: fmt.Sprintf("%q is not defined in the config file", name)}
+}
+
+// validateProfiles checks the profiles table of a config file
+func validateProfiles(raw any) []*errors.ConfigError {
+	profiles, ok := raw.(map[string]any)
+	if !ok {
+		return []*errors.ConfigError{{Key: ProfilesKey, Value: fmt.Sprint(raw), Message: "must be a table of named profiles"}}
+	}
+
+	var problems []*errors.ConfigError
+	for name, rawProfile := range profiles {
+		prefix := ProfilesKey + "." + name + "."
+		values, ok := rawProfile.(map[string]any)
+		if !ok {
+			problems = append(problems, &errors.ConfigError{
+				Key: ProfilesKey + "." + name, Value: fmt.Sprint(rawProfile), Message: "must be a table of settings",
+			})
+			continue
+		}
+
+		problems = append(problems, validateValues(prefix, settingsOf(values))...)
+
+		extends, ok := values[extendsKey]
+		if !ok {
+			continue
+		}
+		parent, isString := extends.(string)
+		switch _, exists := profiles[parent]; {
+		case !isString:
+			problems = append(problems, &errors.ConfigError{Key: prefix + extendsKey, Value: fmt.Sprint(extends), Message: "must be a profile name"})
+		case !exists:
+			problems = append(problems, &errors.ConfigError{Key: prefix + extendsKey, Value: parent, Message: fmt.Sprintf("unknown profile %q", parent)})
+		case loops(profiles, name):
+			problems = append(problems, &errors.ConfigError{Key: prefix + extendsKey, Value: parent, Message: "profiles extend each other in a cycle"})
+		}
+	}
+	return problems
+}
+
+// loops reports whether following extends from a profile never ends
+func loops(profiles map[string]any, name string) bool {
+	seen := make(map[string]bool)
+	for name != "" {
+		if seen[name] {
+			return true
+		}
+		seen[name] = true
+		values, _ := profiles[name].(map[string]any)
+		name, _ = values[extendsKey].(string)
+	}
+	return false
+}
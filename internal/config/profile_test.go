@@ -0,0 +1,124 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// profileFile is a config file with a chain of profiles
+func profileFile() *File {
+	return &File{Path: "config.json", format: "json", values: map[string]any{
+		"log": map[string]any{"level": "info"},
+		"profiles": map[string]any{
+			"base": map[string]any{"log": map[string]any{"format": "json"}},
+			"ci": map[string]any{
+				"extends":  "base",
+				"greeting": map[string]any{"language": "de"},
+			},
+			"prod": map[string]any{
+				"extends": "ci",
+				"log":     map[string]any{"level": "warn"},
+			},
+			"loop": map[string]any{"extends": "loop"},
+		},
+	}}
+}
+
+func TestProfileValues(t *testing.T) {
+	tests := []struct {
+		profile string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			profile: "base",
+			want:    map[string]any{"log": map[string]any{"format": "json"}},
+		},
+		{
+			profile: "prod",
+			want: map[string]any{
+				"log":      map[string]any{"format": "json", "level": "warn"},
+				"greeting": map[string]any{"language": "de"},
+			},
+		},
+		{profile: "staging", wantErr: true},
+		{profile: "loop", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			got, err := Layers{profileFile()}.ProfileValues(tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProfileValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProfileValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileName(t *testing.T) {
+	env := func(string) (string, bool) { return "ci", true }
+	noEnv := func(string) (string, bool) { return "", false }
+
+	if got := ProfileName(nil, env); got != "ci" {
+		t.Errorf("ProfileName() from env = %q, want ci", got)
+	}
+	if got := ProfileName(nil, noEnv); got != "" {
+		t.Errorf("ProfileName() without a profile = %q, want empty", got)
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	settings, err := Resolve(Sources{Files: Layers{profileFile()}, Profile: "prod"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := map[string]Setting{
+		"log.level":         {Key: "log.level", Value: "warn", Source: SourceProfile, Origin: "profiles.prod in config.json"},
+		"log.format":        {Key: "log.format", Value: "json", Source: SourceProfile, Origin: "profiles.base in config.json"},
+		"greeting.language": {Key: "greeting.language", Value: "de", Source: SourceProfile, Origin: "profiles.ci in config.json"},
+		"debug":             {Key: "debug", Value: false, Source: SourceDefault},
+	}
+	for _, s := range settings {
+		if w, ok := want[s.Key]; ok && !reflect.DeepEqual(s, w) {
+			t.Errorf("Resolve() %s = %+v, want %+v", s.Key, s, w)
+		}
+	}
+
+	if _, err := Resolve(Sources{Profile: "prod"}); err == nil {
+		t.Error("Resolve() with a profile and no file succeeded")
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	values := map[string]any{
+		"profiles": map[string]any{
+			"ci":      map[string]any{"extends": "missing", "log": map[string]any{"level": "loud"}},
+			"a":       map[string]any{"extends": "b"},
+			"b":       map[string]any{"extends": "a"},
+			"flat":    "json",
+			"numeric": map[string]any{"extends": 3},
+			"ok":      map[string]any{"extends": "a", "verbose": true},
+		},
+	}
+
+	var keys []string
+	for _, problem := range Validate(values) {
+		keys = append(keys, problem.Key)
+	}
+	want := []string{
+		"profiles.a.extends",
+		"profiles.b.extends",
+		"profiles.ci.extends",
+		"profiles.ci.log.level",
+		"profiles.flat",
+		"profiles.numeric.extends",
+		"profiles.ok.extends",
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("Validate() problem keys = %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// scaffoldHeader opens config files written by Scaffold
+const scaffoldHeader = `Configuration for ` + AppName + `, created by '` + AppName + ` init'.
+` + EnvPrefix + `_* environment variables and command-line flags
+override these values. Change them with '` + AppName + ` config set'.`
+
+// Scaffold returns a new config file at path holding values. YAML files
+// describe every key in a comment.
+func Scaffold(path string, values map[string]any) (*File, error) {
+	f := &File{Path: path, format: formatOf(path)}
+	for _, key := range Keys {
+		value, ok := values[key.Name]
+		if !ok {
+			continue
+		}
+		if err := f.Set(key.Name, value); err != nil {
+			return nil, err
+		}
+		if f.format == "yaml" {
+			f.keyNode(key.Name).HeadComment = describe(key)
+		}
+	}
+
+	if f.doc != nil {
+		f.doc.HeadComment = scaffoldHeader
+	}
+	return f, nil
+}
+
+// keyNode returns the YAML key node of a dotted key that is set
+func (f *File) keyNode(name string) *yaml.Node {
+	mapping := f.rootMapping()
+	parts := strings.Split(name, ".")
+	for _, part := range parts[:len(parts)-1] {
+		mapping = mapping.Content[mappingIndex(mapping, part)+1]
+	}
+	return mapping.Content[mappingIndex(mapping, parts[len(parts)-1])]
+}
+
+// describe returns the comment documenting a key
+func describe(key Key) string {
+	comment := key.Description
+	if len(key.Allowed) > 0 {
+		comment += ": " + strings.Join(key.Allowed, ", ")
+	}
+	return comment
+}
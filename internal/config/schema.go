@@ -0,0 +1,90 @@
+package config
+
+import "strings"
+
+// SchemaURI is the JSON Schema dialect of Schema
+const SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns a JSON Schema describing config files, generated from
+// Keys. Editors use it to complete and check keys and values.
+func Schema() map[string]any {
+	profile := settingsSchema()
+	profile["properties"].(map[string]any)[extendsKey] = map[string]any{
+		"type":        "string",
+		"description": "Profile to inherit settings from",
+	}
+
+	schema := settingsSchema()
+	schema["$schema"] = SchemaURI
+	schema["title"] = AppName + " configuration"
+	schema["properties"].(map[string]any)[ProfilesKey] = map[string]any{
+		"type":                 "object",
+		"description":          "Named profiles selected with --" + ProfileFlag,
+		"additionalProperties": profile,
+	}
+	return schema
+}
+
+// settingsSchema returns the schema of a table of settings: the top level
+// of a config file or a profile
+func settingsSchema() map[string]any {
+	root := objectSchema()
+	for _, key := range Keys {
+		parent := root
+		parts := strings.Split(key.Name, ".")
+		for _, part := range parts[:len(parts)-1] {
+			properties := parent["properties"].(map[string]any)
+			child, ok := properties[part].(map[string]any)
+			if !ok {
+				child = objectSchema()
+				properties[part] = child
+			}
+			parent = child
+		}
+		parent["properties"].(map[string]any)[parts[len(parts)-1]] = keySchema(key)
+	}
+	return root
+}
+
+func objectSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{},
+		"additionalProperties": false,
+	}
+}
+
+func keySchema(key Key) map[string]any {
+	schema := map[string]any{
+		"description": key.Description,
+		"default":     key.Default,
+	}
+	switch key.Type {
+	case TypeBool:
+		schema["type"] = "boolean"
+	case TypeInt:
+		schema["type"] = "integer"
+		schema["minimum"] = 0
+	case TypeList:
+		items := objectSchema()
+		for _, field := range key.Fields {
+			items["properties"].(map[string]any)[field.Name] = keySchema(field)
+		}
+		schema["type"] = "array"
+		schema["items"] = items
+	case TypeMap:
+		values := map[string]any{"type": "string"}
+		if len(key.Allowed) > 0 {
+			values["enum"] = key.Allowed
+		}
+		schema["type"] = "object"
+		schema["additionalProperties"] = values
+		return schema
+	default:
+		schema["type"] = "string"
+	}
+	if len(key.Allowed) > 0 {
+		schema["enum"] = key.Allowed
+	}
+	return schema
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+// Redacted replaces sensitive values in output
+const Redacted = "[REDACTED]"
+
+// Secret is a value read through a reference such as ${env:TOKEN}. It
+// prints as Redacted in text, JSON and logs; Reveal returns the value.
+type Secret string
+
+// Reveal returns the secret value
+func (s Secret) Reveal() string { return string(s) }
+
+// String returns Redacted
+func (s Secret) String() string { return Redacted }
+
+// GoString returns Redacted, quoted, for %#v
+func (s Secret) GoString() string { return fmt.Sprintf("%q", Redacted) }
+
+// MarshalJSON encodes Redacted
+func (s Secret) MarshalJSON() ([]byte, error) { return json.Marshal(Redacted) }
+
+// LogValue logs Redacted
+func (s Secret) LogValue() slog.Value { return slog.StringValue(Redacted) }
+
+// reference matches ${kind:argument} in config values
+var reference = regexp.MustCompile(`\$\{([a-z]+):([^}]*)\}`)
+
+// hasReference reports whether a value holds a secret reference
+func hasReference(value string) bool {
+	return reference.MatchString(value)
+}
+
+// holdsReference reports whether a setting value is a string holding a
+// secret reference
+func holdsReference(value any) bool {
+	s, ok := value.(string)
+	return ok && hasReference(s)
+}
+
+// referenceError reports a secret reference in the value of a key that
+// is not a string
+func referenceError(key Key, value string) *errors.ConfigError {
+	return &errors.ConfigError{Key: key.Name, Value: value, Message: "must be " + typeName(key) + "; secret references are only resolved in string values"}
+}
+
+// resolution resolves the references of a setting once, however many
+// configurations share it
+type resolution struct {
+	lookupEnv func(string) (string, bool)
+
+	once     sync.Once
+	resolved Setting
+	err      error
+}
+
+// Expand resolves the secret references in the value of s, as described
+// at expand. References set by a project file are not resolved, since
+// any checkout may hold one; they are a ConfigError naming the file. A
+// setting returned by Resolve is resolved once, on first use.
+func (s Setting) Expand() (Setting, error) {
+	if !holdsReference(s.Value) {
+		return s, nil
+	}
+	r := s.resolution
+	if r == nil {
+		r = &resolution{}
+	}
+	r.once.Do(func() {
+		if s.project != "" {
+			r.err = &errors.ConfigError{
+				Key:     s.Key,
+				Value:   s.Value.(string),
+				Message: "secret references are not resolved in project config file " + s.project + "; set the value in the user config file, with --config or in the environment",
+			}
+			return
+		}
+		r.resolved, r.err = expand(s, r.lookupEnv)
+	})
+	return r.resolved, r.err
+}
+
+// commandTimeout bounds how long a ${cmd:...} reference may run
+const commandTimeout = 10 * time.Second
+
+// expand resolves the references in a string value: ${env:NAME} reads an
+// environment variable, ${file:PATH} a file and ${cmd:COMMAND} the output
+// of a shell command, each without its trailing newline. Values with
+// references become a Secret; others are returned unchanged.
+func expand(s Setting, lookupEnv func(string) (string, bool)) (Setting, error) {
+	raw, ok := s.Value.(string)
+	if !ok || !hasReference(raw) {
+		return s, nil
+	}
+
+	var expandErr error
+	value := reference.ReplaceAllStringFunc(raw, func(ref string) string {
+		if expandErr != nil {
+			return ""
+		}
+		match := reference.FindStringSubmatch(ref)
+		resolved, err := resolveReference(match[1], match[2], lookupEnv)
+		if err != nil {
+			expandErr = &errors.ConfigError{Key: s.Key, Value: ref, Message: err.Error()}
+		}
+		return resolved
+	})
+	if expandErr != nil {
+		return s, expandErr
+	}
+
+	s.Value = Secret(value)
+	return s, nil
+}
+
+func resolveReference(kind, arg string, lookupEnv func(string) (string, bool)) (string, error) {
+	switch kind {
+	case "env":
+		if lookupEnv == nil {
+			lookupEnv = os.LookupEnv
+		}
+		value, ok := lookupEnv(arg)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", arg)
+		}
+		return value, nil
+	case "file":
+		data, err := os.ReadFile(arg)
+		if err != nil {
+			return "", fmt.Errorf("cannot read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case "cmd":
+		return runCommand(arg)
+	default:
+		return "", fmt.Errorf("unknown reference type %q, use env, file or cmd", kind)
+	}
+}
+
+// runCommand runs a shell command and returns its output. Only the exit
+// status is reported on failure; the output may hold part of the secret.
+func runCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("command failed: %w", err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"TOKEN": "from-env"}
+	lookupEnv := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	type secretTest struct {
+		name    string
+		key     string
+		value   string
+		want    any
+		wantErr bool
+	}
+	tests := []secretTest{
+		{name: "plain value", key: "locales.dir", value: "/srv/locales", want: "/srv/locales"},
+		{name: "env", key: "locales.dir", value: "${env:TOKEN}", want: Secret("from-env")},
+		{name: "file", key: "locales.dir", value: "${file:" + secretFile + "}", want: Secret("from-file")},
+		{name: "embedded", key: "locales.dir", value: "/srv/${env:TOKEN}/locales", want: Secret("/srv/from-env/locales")},
+		{name: "unset variable", key: "locales.dir", value: "${env:MISSING}", wantErr: true},
+		{name: "missing file", key: "locales.dir", value: "${file:" + secretFile + ".missing}", wantErr: true},
+		{name: "unknown type", key: "locales.dir", value: "${vault:token}", wantErr: true},
+	}
+	if runtime.GOOS != "windows" {
+		tests = append(tests,
+			secretTest{name: "command", key: "locales.dir", value: "${cmd:echo from-cmd}", want: Secret("from-cmd")},
+			secretTest{name: "failing command", key: "locales.dir", value: "${cmd:exit 3}", wantErr: true},
+		)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &File{Path: "config.json", format: "json", values: map[string]any{}}
+			if err := file.Set(tt.key, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			settings, err := Resolve(Sources{LookupEnv: lookupEnv, Files: Layers{file}})
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			for _, s := range settings {
+				if s.Key != tt.key {
+					continue
+				}
+				got, err := s.Expand()
+				if tt.wantErr {
+					if !errors.IsConfig(err) {
+						t.Fatalf("Expand() error = %v, want a ConfigError", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Expand() error = %v", err)
+				}
+				if got.Value != tt.want {
+					t.Errorf("Expand() %s = %#v, want %#v", tt.key, got.Value, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandOnlyReadKeys(t *testing.T) {
+	lookups := map[string]int{}
+	lookupEnv := func(name string) (string, bool) {
+		lookups[name]++
+		return "fr", name == "LANGUAGE"
+	}
+	user := &File{Path: "config.json", Scope: ScopeUser, format: "json", values: map[string]any{}}
+	if err := user.Set("greeting.language", "${env:LANGUAGE}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := user.Set("log.output", "${env:MISSING}"); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := Resolve(Sources{LookupEnv: lookupEnv, Files: Layers{user}})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	cfg, err := FromSettings(settings)
+	if err != nil {
+		t.Fatalf("FromSettings() error = %v", err)
+	}
+	if lookups["LANGUAGE"] != 0 || lookups["MISSING"] != 0 {
+		t.Errorf("references resolved before Expand: %v", lookups)
+	}
+
+	greeting, err := cfg.Expand("greeting")
+	if err != nil {
+		t.Fatalf("Expand(greeting) error = %v", err)
+	}
+	if greeting.Greeting.Language != "fr" || !greeting.Sensitive("greeting.language") {
+		t.Errorf("Expand(greeting) language = %q, want a sensitive fr", greeting.Greeting.Language)
+	}
+	if cfg.Greeting.Language != "" {
+		t.Errorf("Expand() changed the original configuration")
+	}
+	if _, err := cfg.Expand("greeting"); err != nil || lookups["LANGUAGE"] != 1 {
+		t.Errorf("second Expand() error = %v, LANGUAGE looked up %d times, want once", err, lookups["LANGUAGE"])
+	}
+	if _, err := cfg.Expand("log"); !errors.IsConfig(err) {
+		t.Errorf("Expand(log) error = %v, want a ConfigError", err)
+	}
+}
+
+func TestExpandProjectFile(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	project := &File{Path: filepath.Join("checkout", ProjectFile), Scope: ScopeProject, format: "json", values: map[string]any{}}
+	if err := project.Set("greeting.language", "${cmd:touch "+marker+"; echo en}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := project.Set("profiles.dev.greeting.language", "${env:HOME}"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range []string{"", "dev"} {
+		settings, err := Resolve(Sources{LookupEnv: os.LookupEnv, Files: Layers{project}, Profile: profile})
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		cfg, err := FromSettings(settings)
+		if err != nil {
+			t.Fatalf("FromSettings() error = %v", err)
+		}
+		_, err = cfg.Expand("greeting")
+		if cfgErr, ok := err.(*errors.ConfigError); !ok || !strings.Contains(cfgErr.Message, project.Path) {
+			t.Errorf("Expand() with profile %q error = %v, want a ConfigError naming %s", profile, err, project.Path)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("the command of the project file ran")
+	}
+}
+
+func TestSecretRedaction(t *testing.T) {
+	const token = "s3cret-token"
+	secret := Secret(token)
+
+	if got := secret.Reveal(); got != token {
+		t.Errorf("Reveal() = %q, want %q", got, token)
+	}
+
+	var logs bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logs, nil)).Info("loaded", "token", secret)
+	data, err := json.Marshal(map[string]any{"token": secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var presented bytes.Buffer
+	handler := &errors.Handler{Output: &presented, Debug: true}
+	handler.Present(errors.New(errors.CodeAuth, "login failed").WithDetails("token", secret))
+
+	outputs := map[string]string{
+		"%v":     fmt.Sprintf("%v", secret),
+		"%#v":    fmt.Sprintf("%#v", secret),
+		"json":   string(data),
+		"slog":   logs.String(),
+		"errors": presented.String(),
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, token) || !strings.Contains(out, Redacted) {
+			t.Errorf("%s output = %q, want the token redacted", name, out)
+		}
+	}
+}
+
+func TestFromSettingsSecret(t *testing.T) {
+	cfg, err := FromSettings([]Setting{{Key: "greeting.language", Value: Secret("fr"), Source: SourceFile}})
+	if err != nil {
+		t.Fatalf("FromSettings() error = %v", err)
+	}
+	if cfg.Greeting.Language != "fr" || !cfg.Sensitive("greeting.language") {
+		t.Errorf("FromSettings() language = %q, sensitive = %v, want fr, true", cfg.Greeting.Language, cfg.Sensitive("greeting.language"))
+	}
+	if got := cfg.Values()["greeting.language"]; got != Secret("fr") {
+		t.Errorf("Values() greeting.language = %#v, want a Secret", got)
+	}
+
+	_, err = FromSettings([]Setting{{Key: "log.level", Value: Secret("s3cret"), Source: SourceFile}})
+	cfgErr, ok := err.(*errors.ConfigError)
+	if !ok || cfgErr.Value != Redacted {
+		t.Errorf("FromSettings() error = %#v, want a ConfigError with the value redacted", err)
+	}
+}
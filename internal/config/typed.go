@@ -0,0 +1,431 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+)
+
+// Config is the typed configuration of the CLI. The json tags of the
+// fields, joined with dots, are the names in Keys; set and Values find
+// fields by them.
+type Config struct {
+	Debug     bool            `json:"debug"`
+	Verbose   bool            `json:"verbose"`
+	Log       LogConfig       `json:"log"`
+	Greeting  GreetingConfig  `json:"greeting"`
+	Locales   LocalesConfig   `json:"locales"`
+	Telemetry TelemetryConfig `json:"telemetry"`
+
+	// sensitive holds the keys set from a secret reference
+	sensitive map[string]bool
+	// pending holds the settings whose secret references Expand has not
+	// resolved; their fields keep the default
+	pending map[string]Setting
+}
+
+// LogConfig holds the log.* keys
+type LogConfig struct {
+	Level          string `json:"level"`
+	Format         string `json:"format"`
+	Output         string `json:"output"`
+	MaxSize        string `json:"max_size"`
+	MaxAge         string `json:"max_age"`
+	MaxBackups     int    `json:"max_backups"`
+	Compress       bool   `json:"compress"`
+	RotateInterval string `json:"rotate_interval"`
+
+	Levels   map[string]string `json:"levels"` // Level by component
+	Sampling SamplingConfig    `json:"sampling"`
+	Sinks    []SinkConfig      `json:"sinks"`
+	Redact   []RedactConfig    `json:"redact"`
+}
+
+// SamplingConfig holds the log.sampling.* keys
+type SamplingConfig struct {
+	First      int    `json:"first"`
+	Thereafter int    `json:"thereafter"`
+	Interval   string `json:"interval"`
+	Rate       int    `json:"rate"`
+	Burst      int    `json:"burst"`
+}
+
+// SinkConfig holds a table of log.sinks
+type SinkConfig struct {
+	Level          string `json:"level"`
+	Format         string `json:"format"`
+	Output         string `json:"output"`
+	MaxSize        string `json:"max_size"`
+	MaxAge         string `json:"max_age"`
+	MaxBackups     int    `json:"max_backups"`
+	Compress       bool   `json:"compress"`
+	RotateInterval string `json:"rotate_interval"`
+}
+
+// RedactConfig holds a table of log.redact
+type RedactConfig struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Action string `json:"action"`
+}
+
+// GreetingConfig holds the greeting.* keys
+type GreetingConfig struct {
+	Language string `json:"language"`
+	Emoji    bool   `json:"emoji"`
+}
+
+// LocalesConfig holds the locales.* keys
+type LocalesConfig struct {
+	Dir string `json:"dir"`
+}
+
+// TelemetryConfig holds the telemetry.* keys
+type TelemetryConfig struct {
+	Exporter string `json:"exporter"`
+	Endpoint string `json:"endpoint"`
+	Protocol string `json:"protocol"`
+	Insecure bool   `json:"insecure"`
+	File     string `json:"file"`
+}
+
+// Default returns the configuration with every key at its default
+func Default() *Config {
+	cfg := &Config{}
+	for _, key := range Keys {
+		// Every key has a field, as TestKeysHaveFields checks
+		_ = cfg.set(key, key.Default)
+	}
+	return cfg
+}
+
+// FromSettings builds a Config from resolved settings, checking each
+// value against the rules of its key. Problems name the flag, variable or
+// file that set the value. Fields set from a Secret hold its value and are
+// marked sensitive. String values holding secret references are checked
+// once Expand resolves them; other keys cannot hold references.
+func FromSettings(settings []Setting) (*Config, error) {
+	cfg := Default()
+	for _, s := range settings {
+		key, ok := Lookup(s.Key)
+		if !ok {
+			return nil, &errors.ConfigError{Key: s.Key, Value: fmt.Sprint(s.Value), Message: "unknown configuration key"}
+		}
+		if holdsReference(s.Value) {
+			if key.Type != TypeString {
+				err := referenceError(key, s.Value.(string))
+				if s.Origin != "" {
+					err.Message += " (set by " + s.Origin + ")"
+				}
+				return nil, err
+			}
+			if cfg.pending == nil {
+				cfg.pending = make(map[string]Setting)
+			}
+			cfg.pending[key.Name] = s
+			continue
+		}
+		if err := cfg.apply(key, s); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// Expand returns the configuration with the secret references of the
+// named keys resolved, as well as those of the keys under them: "log"
+// names every log.* key. Commands expand the keys they read before
+// reading them. c is returned unchanged if none of them holds a
+// reference.
+func (c *Config) Expand(names ...string) (*Config, error) {
+	expanded := c
+	for _, key := range Keys {
+		s, ok := c.pending[key.Name]
+		if !ok || !underAny(key.Name, names) {
+			continue
+		}
+		resolved, err := s.Expand()
+		if err != nil {
+			return nil, err
+		}
+		if expanded == c {
+			expanded = c.clone()
+		}
+		delete(expanded.pending, key.Name)
+		if err := expanded.apply(key, resolved); err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// underAny reports whether name is one of names or a key under one
+func underAny(name string, names []string) bool {
+	for _, n := range names {
+		if name == n || strings.HasPrefix(name, n+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy of c that can be changed without changing c
+func (c *Config) clone() *Config {
+	cfg := *c
+	cfg.sensitive = maps.Clone(c.sensitive)
+	cfg.pending = maps.Clone(c.pending)
+	return &cfg
+}
+
+// apply checks the value of a setting and assigns it to the field of key
+func (c *Config) apply(key Key, s Setting) error {
+	if err := checkType(key, s.Value); err != nil {
+		if s.Origin != "" {
+			err.Message += " (set by " + s.Origin + ")"
+		}
+		return err
+	}
+	if secret, ok := s.Value.(Secret); ok {
+		if c.sensitive == nil {
+			c.sensitive = make(map[string]bool)
+		}
+		c.sensitive[key.Name] = true
+		return c.set(key, secret.Reveal())
+	}
+	return c.set(key, s.Value)
+}
+
+// Sensitive reports whether a key was set from a secret reference
+func (c *Config) Sensitive(name string) bool {
+	return c.sensitive[name]
+}
+
+// Validate checks every field against the rules of its key and returns
+// the first problem, in key order. Keys whose references are not yet
+// resolved are skipped.
+func (c *Config) Validate() error {
+	values := c.Values()
+	for _, key := range Keys {
+		if _, ok := c.pending[key.Name]; ok {
+			continue
+		}
+		if err := checkType(key, values[key.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Values returns the fields by dotted key name. Sensitive values are
+// returned as a Secret so they are redacted when printed or logged, and
+// values not yet expanded as written.
+func (c *Config) Values() map[string]any {
+	values := make(map[string]any, len(Keys))
+	for _, key := range Keys {
+		if field, err := c.field(key.Name); err == nil {
+			values[key.Name] = encode(field, key)
+		}
+	}
+	for name := range c.sensitive {
+		if s, ok := values[name].(string); ok {
+			values[name] = Secret(s)
+		}
+	}
+	for name, s := range c.pending {
+		values[name] = s.Value
+	}
+	return values
+}
+
+// set assigns the field of a key from a value of the key's type
+func (c *Config) set(key Key, value any) error {
+	field, err := c.field(key.Name)
+	if err != nil {
+		return err
+	}
+	return decode(field, key, value)
+}
+
+// field returns the field of c holding a key, found by following the json
+// tags of the dotted name
+func (c *Config) field(name string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+	for _, part := range strings.Split(name, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, &errors.ConfigError{Key: name, Message: "no configuration field for key"}
+		}
+		var ok bool
+		if v, ok = structField(v, part); !ok {
+			return reflect.Value{}, &errors.ConfigError{Key: name, Message: "no configuration field for key"}
+		}
+	}
+	return v, nil
+}
+
+// structField returns the exported field of v whose json tag is name
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.IsExported() && tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// decode assigns a checked value of key to field
+func decode(field reflect.Value, key Key, value any) error {
+	mismatch := &errors.ConfigError{Key: key.Name, Message: fmt.Sprintf("cannot be stored in a %s field", field.Type())}
+	switch field.Kind() {
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return mismatch
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, ok := toInt(value)
+		if !ok {
+			return mismatch
+		}
+		field.SetInt(int64(n))
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return mismatch
+		}
+		field.SetString(s)
+	case reflect.Map:
+		table, ok := value.(map[string]any)
+		if !ok || field.Type().Elem().Kind() != reflect.String {
+			return mismatch
+		}
+		m := reflect.MakeMapWithSize(field.Type(), len(table))
+		for name, v := range table {
+			s, ok := v.(string)
+			if !ok {
+				return mismatch
+			}
+			m.SetMapIndex(reflect.ValueOf(name), reflect.ValueOf(s))
+		}
+		field.Set(m)
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok || field.Type().Elem().Kind() != reflect.Struct {
+			return mismatch
+		}
+		if len(items) == 0 {
+			field.SetZero()
+			return nil
+		}
+		list := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			table, _ := item.(map[string]any)
+			for _, f := range key.Fields {
+				v, ok := table[f.Name]
+				if !ok {
+					v = f.Default
+				}
+				elem, ok := structField(list.Index(i), f.Name)
+				if !ok {
+					return &errors.ConfigError{Key: fmt.Sprintf("%s[%d].%s", key.Name, i, f.Name), Message: "no configuration field for key"}
+				}
+				if err := decode(elem, f, v); err != nil {
+					return err
+				}
+			}
+		}
+		field.Set(list)
+	default:
+		return mismatch
+	}
+	return nil
+}
+
+// encode returns field as a value of key, as in a config file
+func encode(field reflect.Value, key Key) any {
+	switch field.Kind() {
+	case reflect.Map:
+		values := make(map[string]any, field.Len())
+		iter := field.MapRange()
+		for iter.Next() {
+			values[iter.Key().String()] = iter.Value().Interface()
+		}
+		return values
+	case reflect.Slice:
+		return encodeList(field, key.Fields)
+	default:
+		return field.Interface()
+	}
+}
+
+// encodeList returns a slice of structs as a list of tables of fields
+func encodeList(list reflect.Value, fields []Key) []any {
+	values := make([]any, 0, list.Len())
+	for i := range list.Len() {
+		table := make(map[string]any, len(fields))
+		for _, f := range fields {
+			if elem, ok := structField(list.Index(i), f.Name); ok {
+				table[f.Name] = encode(elem, f)
+			}
+		}
+		values = append(values, table)
+	}
+	return values
+}
+
+type configKey struct{}
+
+// provider is stored in contexts: a fixed Config or a Watcher
+type provider interface {
+	config() *Config
+}
+
+func (c *Config) config() *Config  { return c }
+func (w *Watcher) config() *Config { return w.Current() }
+
+// WithContext returns a new context carrying cfg
+func WithContext(ctx context.Context, cfg *Config) context.Context {
+	return context.WithValue(ctx, configKey{}, provider(cfg))
+}
+
+// WithWatcher returns a new context carrying w; FromContext returns its
+// current configuration
+func WithWatcher(ctx context.Context, w *Watcher) context.Context {
+	return context.WithValue(ctx, configKey{}, provider(w))
+}
+
+// FromContext returns the configuration stored in ctx, or the defaults
+func FromContext(ctx context.Context) *Config {
+	if p, ok := ctx.Value(configKey{}).(provider); ok {
+		return p.config()
+	}
+	return Default()
+}
+
+// DefaultLanguage returns a function reporting greeting.language in the
+// configuration stored in ctx, for servers following config changes. A
+// reference that cannot be resolved leaves the language unset and is
+// logged once for each configuration.
+func DefaultLanguage(ctx context.Context, log logger.Logger) func() string {
+	var failed atomic.Pointer[Config]
+	return func() string {
+		current := FromContext(ctx)
+		cfg, err := current.Expand("greeting.language")
+		if err != nil {
+			if failed.Swap(current) != current {
+				log.Warn("cannot resolve greeting.language, using the default", "error", err)
+			}
+			return ""
+		}
+		return cfg.Greeting.Language
+	}
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+func TestDefault(t *testing.T) {
+	values := Default().Values()
+	for _, key := range Keys {
+		if got := values[key.Name]; !reflect.DeepEqual(got, key.Default) {
+			t.Errorf("Default() %s = %v, want %v", key.Name, got, key.Default)
+		}
+	}
+	if len(values) != len(Keys) {
+		t.Errorf("Values() has %d keys, want %d", len(values), len(Keys))
+	}
+}
+
+func TestKeysHaveFields(t *testing.T) {
+	cfg := &Config{}
+	for _, key := range Keys {
+		if err := cfg.set(key, key.Default); err != nil {
+			t.Errorf("set(%s) error = %v", key.Name, err)
+		}
+	}
+
+	for _, name := range []string{"greeting.name", "log.sampling", "debug.level"} {
+		err := cfg.set(Key{Name: name, Type: TypeString}, "x")
+		if cfgErr, ok := err.(*errors.ConfigError); !ok || cfgErr.Key != name {
+			t.Errorf("set(%s) error = %v, want a ConfigError for the key", name, err)
+		}
+	}
+}
+
+func TestFromSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings []Setting
+		want     *Config
+		wantKey  string // Key of the expected ConfigError
+	}{
+		{
+			name: "defaults",
+			want: Default(),
+		},
+		{
+			name: "typed values",
+			settings: []Setting{
+				{Key: "log.level", Value: "warn", Source: SourceFile, Origin: "config.yaml"},
+				{Key: "greeting.language", Value: "fr", Source: SourceEnv, Origin: "HELLO_WORLD_CLI_GREETING_LANGUAGE"},
+				{Key: "greeting.emoji", Value: true, Source: SourceFlag, Origin: "--emoji"},
+			},
+			want: &Config{
+				Log:       LogConfig{Level: "warn", Format: "text", Output: "stderr", MaxSize: "100MB", MaxBackups: 5, Levels: map[string]string{}, Sampling: SamplingConfig{Interval: "1s"}, Redact: Default().Log.Redact},
+				Greeting:  GreetingConfig{Language: "fr", Emoji: true},
+				Telemetry: TelemetryConfig{Exporter: "none", Protocol: "http"},
+			},
+		},
+		{
+			name: "sinks with defaults",
+			settings: []Setting{{Key: "log.sinks", Value: []any{
+				map[string]any{"output": "debug.log", "level": "debug", "max_backups": float64(2)},
+			}, Source: SourceFile}},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Log.Sinks = []SinkConfig{{Level: "debug", Format: "text", Output: "debug.log", MaxSize: "100MB", MaxBackups: 2}}
+				return cfg
+			}(),
+		},
+		{
+			name: "redaction rules with defaults",
+			settings: []Setting{{Key: "log.redact", Value: []any{
+				map[string]any{"key": "name", "action": "hash"},
+				map[string]any{"value": "[0-9]{16}"},
+			}, Source: SourceFile}},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Log.Redact = []RedactConfig{{Key: "name", Action: "hash"}, {Value: "[0-9]{16}", Action: "mask"}}
+				return cfg
+			}(),
+		},
+		{
+			name:     "component levels",
+			settings: []Setting{{Key: "log.levels", Value: map[string]any{"greeting": "debug", "cli": "warn"}, Source: SourceFile}},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Log.Levels = map[string]string{"greeting": "debug", "cli": "warn"}
+				return cfg
+			}(),
+		},
+		{
+			name: "sampling",
+			settings: []Setting{
+				{Key: "log.sampling.first", Value: 10, Source: SourceFile},
+				{Key: "log.sampling.thereafter", Value: float64(100), Source: SourceFile},
+				{Key: "log.sampling.interval", Value: "1m", Source: SourceFile},
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Log.Sampling = SamplingConfig{First: 10, Thereafter: 100, Interval: "1m"}
+				return cfg
+			}(),
+		},
+		{
+			name:     "negative sampling rate",
+			settings: []Setting{{Key: "log.sampling.rate", Value: -1, Source: SourceFile}},
+			wantKey:  "log.sampling.rate",
+		},
+		{
+			name:     "unknown component level",
+			settings: []Setting{{Key: "log.levels", Value: map[string]any{"greeting": "loud"}, Source: SourceFile}},
+			wantKey:  "log.levels.greeting",
+		},
+		{
+			name:     "redaction rule without a pattern",
+			settings: []Setting{{Key: "log.redact", Value: []any{map[string]any{"action": "drop"}}, Source: SourceFile}},
+			wantKey:  "log.redact[0]",
+		},
+		{
+			name:     "bool that did not parse",
+			settings: []Setting{{Key: "debug", Value: "yes", Source: SourceEnv, Origin: "HELLO_WORLD_CLI_DEBUG"}},
+			wantKey:  "debug",
+		},
+		{
+			name:     "secret reference for a bool",
+			settings: []Setting{{Key: "greeting.emoji", Value: "${env:EMOJI}", Source: SourceFile, Origin: "config.yaml"}},
+			wantKey:  "greeting.emoji",
+		},
+		{
+			name:     "table for a string",
+			settings: []Setting{{Key: "log.level", Value: map[string]any{"min": "info"}, Source: SourceFile}},
+			wantKey:  "log.level",
+		},
+		{
+			name:     "value not allowed",
+			settings: []Setting{{Key: "log.level", Value: "verbose", Source: SourceLegacyEnv, Origin: "LOG_LEVEL"}},
+			wantKey:  "log.level",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromSettings(tt.settings)
+			if tt.wantKey != "" {
+				cfgErr, ok := err.(*errors.ConfigError)
+				if !ok || cfgErr.Key != tt.wantKey {
+					t.Fatalf("FromSettings() error = %v, want a ConfigError for %s", err, tt.wantKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromSettings() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(*Config)
+		wantKey   string
+		wantValue string
+	}{
+		{name: "defaults", modify: func(*Config) {}},
+		{name: "unknown level", modify: func(c *Config) { c.Log.Level = "verbose" }, wantKey: "log.level", wantValue: "verbose"},
+		{name: "unknown format", modify: func(c *Config) { c.Log.Format = "xml" }, wantKey: "log.format", wantValue: "xml"},
+		{name: "bad language", modify: func(c *Config) { c.Greeting.Language = "not a tag" }, wantKey: "greeting.language", wantValue: "not a tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantKey == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			cfgErr, ok := err.(*errors.ConfigError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want a ConfigError", err)
+			}
+			if cfgErr.Key != tt.wantKey || cfgErr.Value != tt.wantValue {
+				t.Errorf("Validate() = %+v, want key %s and value %q", cfgErr, tt.wantKey, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+	properties := schema["properties"].(map[string]any)
+
+	log := properties["log"].(map[string]any)["properties"].(map[string]any)
+	level := log["level"].(map[string]any)
+	if !reflect.DeepEqual(level["enum"], []string{"debug", "info", "warn", "error"}) {
+		t.Errorf("log.level enum = %v", level["enum"])
+	}
+	if level["default"] != "info" || level["type"] != "string" {
+		t.Errorf("log.level schema = %v", level)
+	}
+	if properties["debug"].(map[string]any)["type"] != "boolean" {
+		t.Errorf("debug schema = %v", properties["debug"])
+	}
+
+	profile := properties[ProfilesKey].(map[string]any)["additionalProperties"].(map[string]any)
+	profileProperties := profile["properties"].(map[string]any)
+	if _, ok := profileProperties[extendsKey]; !ok {
+		t.Error("profile schema has no extends property")
+	}
+	if _, ok := profileProperties[ProfilesKey]; ok {
+		t.Error("profile schema allows nested profiles")
+	}
+	if _, ok := profileProperties["log"]; !ok {
+		t.Error("profile schema has no log property")
+	}
+}
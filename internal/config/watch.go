@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+// Change is a key whose value differs between two configurations
+type Change struct {
+	Key string `json:"key"`
+	Old any    `json:"old"`
+	New any    `json:"new"`
+}
+
+// Diff returns the keys whose values differ between old and cur, in key
+// order
+func Diff(old, cur *Config) []Change {
+	oldValues, curValues := old.Values(), cur.Values()
+	var changes []Change
+	for _, key := range Keys {
+		if !reflect.DeepEqual(oldValues[key.Name], curValues[key.Name]) {
+			changes = append(changes, Change{Key: key.Name, Old: oldValues[key.Name], New: curValues[key.Name]})
+		}
+	}
+	return changes
+}
+
+// watchDelay is how long Watcher waits for more file events before
+// reloading, so an editor's write and rename cause one reload
+const watchDelay = 100 * time.Millisecond
+
+// Watcher reloads the configuration when config files change. The new
+// configuration replaces the current one only if it loads and validates;
+// otherwise the last good configuration stays in effect.
+type Watcher struct {
+	paths []string
+	load  func() (*Config, error)
+
+	current atomic.Pointer[Config]
+
+	mu       sync.Mutex // Serializes reloads and guards the callbacks
+	onChange []func(old, cur *Config, changes []Change)
+	onError  []func(error)
+}
+
+// NewWatcher creates a watcher starting from initial. load reads the
+// config files at paths again and resolves the configuration.
+func NewWatcher(initial *Config, paths []string, load func() (*Config, error)) *Watcher {
+	w := &Watcher{paths: paths, load: load}
+	w.current.Store(initial)
+	return w
+}
+
+// Current returns the configuration in effect
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// OnChange registers fn to be called after the configuration changes
+func (w *Watcher) OnChange(fn func(old, cur *Config, changes []Change)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, fn)
+}
+
+// OnError registers fn to be called when a reload fails and the current
+// configuration is kept
+func (w *Watcher) OnError(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = append(w.onError, fn)
+}
+
+// Reload loads the configuration and swaps it in if it is valid and
+// differs from the current one
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cfg, err := w.load()
+	if err != nil {
+		for _, fn := range w.onError {
+			fn(err)
+		}
+		return err
+	}
+
+	old := w.current.Load()
+	changes := Diff(old, cfg)
+	if len(changes) == 0 {
+		return nil
+	}
+	w.current.Store(cfg)
+	for _, fn := range w.onChange {
+		fn(old, cfg, changes)
+	}
+	return nil
+}
+
+// Run reloads the configuration whenever one of the watched files is
+// written, created, renamed or removed, until ctx is canceled
+func (w *Watcher) Run(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, errors.CodeConfig, "cannot watch config files")
+	}
+	defer fsw.Close()
+
+	// Watch directories rather than files so files replaced by a rename,
+	// or created later, are noticed
+	watched := make(map[string]bool)
+	for _, path := range w.paths {
+		watched[filepath.Clean(path)] = true
+		dir := filepath.Dir(path)
+		if exists(dir) {
+			if err := fsw.Add(dir); err != nil {
+				return errors.Wrapf(err, errors.CodeConfig, "cannot watch %s", dir)
+			}
+		}
+	}
+
+	timer := time.NewTimer(watchDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if watched[filepath.Clean(event.Name)] && !event.Has(fsnotify.Chmod) {
+				timer.Reset(watchDelay)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			w.reportError(errors.Wrap(err, errors.CodeConfig, "error watching config files"))
+		case <-timer.C:
+			_ = w.Reload()
+		}
+	}
+}
+
+func (w *Watcher) reportError(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, fn := range w.onError {
+		fn(err)
+	}
+}
+
+// StartWatching runs the Watcher carried by ctx, if any, in the background
+// until ctx is canceled. Long-running commands call it to apply config
+// changes without a restart.
+func StartWatching(ctx context.Context) {
+	w, ok := ctx.Value(configKey{}).(*Watcher)
+	if !ok {
+		return
+	}
+	go func() {
+		if err := w.Run(ctx); err != nil {
+			w.reportError(err)
+		}
+	}()
+}
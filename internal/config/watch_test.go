@@ -0,0 +1,133 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	old := Default()
+	cur := Default()
+	cur.Log.Level = "debug"
+	cur.Greeting.Emoji = true
+
+	want := []Change{
+		{Key: "greeting.emoji", Old: false, New: true},
+		{Key: "log.level", Old: "info", New: "debug"},
+	}
+	if got := Diff(old, cur); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+	if got := Diff(old, Default()); got != nil {
+		t.Errorf("Diff() of equal configs = %+v, want none", got)
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	next := Default()
+	var loadErr error
+	w := NewWatcher(Default(), nil, func() (*Config, error) {
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		return next, nil
+	})
+
+	var changes [][]Change
+	var failures []error
+	w.OnChange(func(_, _ *Config, c []Change) { changes = append(changes, c) })
+	w.OnError(func(err error) { failures = append(failures, err) })
+
+	// Nothing changed
+	if err := w.Reload(); err != nil || len(changes) != 0 {
+		t.Fatalf("Reload() = %v with changes %v, want no change", err, changes)
+	}
+
+	// A valid change is swapped in
+	next = Default()
+	next.Log.Format = "json"
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if w.Current().Log.Format != "json" || len(changes) != 1 || changes[0][0].Key != "log.format" {
+		t.Errorf("after reload format = %s, changes = %v", w.Current().Log.Format, changes)
+	}
+
+	// A failed load keeps the last good configuration
+	loadErr = fmt.Errorf("cannot parse")
+	if err := w.Reload(); err == nil {
+		t.Error("Reload() error = nil, want the load error")
+	}
+	if w.Current().Log.Format != "json" || len(failures) != 1 {
+		t.Errorf("after failure format = %s, failures = %v", w.Current().Log.Format, failures)
+	}
+
+	ctx := WithWatcher(context.Background(), w)
+	if FromContext(ctx) != w.Current() {
+		t.Error("FromContext() is not the watcher's current configuration")
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := WriteFileAtomic(path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("log:\n  level: info\n")
+
+	load := func() (*Config, error) {
+		file, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		settings, err := Resolve(Sources{Files: Layers{file}})
+		if err != nil {
+			return nil, err
+		}
+		return FromSettings(settings)
+	}
+	initial, err := load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWatcher(initial, []string{path}, load)
+	changed := make(chan []Change, 1)
+	failed := make(chan error, 1)
+	w.OnChange(func(_, _ *Config, c []Change) { changed <- c })
+	w.OnError(func(err error) { failed <- err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = w.Run(ctx) }()
+	time.Sleep(50 * time.Millisecond) // Let the watch start
+
+	write("log:\n  level: debug\n")
+	select {
+	case c := <-changed:
+		if len(c) != 1 || c[0].Key != "log.level" || c[0].New != "debug" {
+			t.Errorf("changes = %+v, want log.level to debug", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after the file changed")
+	}
+
+	for _, content := range []string{"log: [", "log:\n  level: verbose\n"} {
+		write(content)
+		select {
+		case <-failed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no error after writing %q", content)
+		}
+		if got := w.Current().Log.Level; got != "debug" {
+			t.Errorf("level after %q = %s, want the last good debug", content, got)
+		}
+	}
+}
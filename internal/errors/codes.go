@@ -1,6 +1,9 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"io/fs"
+)
 
 // ErrorCode represents a machine-readable error code
 type ErrorCode string
@@ -166,8 +169,9 @@ func GetExitCode(err error) ExitCode {
 	if IsConfig(err) {
 		return ExitConfig
 	}
-	if IsFile(err) {
-		return ExitIOError
+	var fileErr *FileError
+	if errors.As(err, &fileErr) {
+		return fileExitCode(fileErr)
 	}
 	if IsNetwork(err) {
 		return ExitUnavailable
@@ -177,6 +181,23 @@ func GetExitCode(err error) ExitCode {
 	return ExitGeneralError
 }
 
+// fileExitCode derives an exit code from the cause and operation of a
+// file error
+func fileExitCode(err *FileError) ExitCode {
+	switch {
+	case errors.Is(err.Err, fs.ErrPermission):
+		return ExitNoPerm
+	case errors.Is(err.Err, fs.ErrExist):
+		return ExitCantCreate
+	case errors.Is(err.Err, fs.ErrNotExist) && err.Operation == "read":
+		return ExitNoInput
+	case err.Operation == "write" || err.Operation == "create":
+		return ExitCantCreate
+	default:
+		return ExitIOError
+	}
+}
+
 // String returns a human-readable description of the exit code
 func (e ExitCode) String() string {
 	return getExitCodeString(e)
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"fmt"
+	"io/fs"
 	"testing"
 )
 
@@ -31,6 +32,21 @@ func TestGetExitCode(t *testing.T) {
 			err:      &FileError{Path: "test", Operation: "read"},
 			wantCode: ExitIOError,
 		},
+		{
+			name:     "existing file returns cannot create",
+			err:      &FileError{Path: "test", Operation: "create", Err: fs.ErrExist},
+			wantCode: ExitCantCreate,
+		},
+		{
+			name:     "unwritable file returns no permission",
+			err:      &FileError{Path: "test", Operation: "write", Err: fs.ErrPermission},
+			wantCode: ExitNoPerm,
+		},
+		{
+			name:     "missing input file returns no input",
+			err:      &FileError{Path: "test", Operation: "read", Err: fs.ErrNotExist},
+			wantCode: ExitNoInput,
+		},
 		{
 			name:     "network error returns unavailable",
 			err:      &NetworkError{URL: "test", Operation: "GET"},
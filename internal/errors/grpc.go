@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcErrorDomain identifies this application in google.rpc.ErrorInfo details
+const grpcErrorDomain = "hello-world-cli" // TODO: Replace with your app name
+
+// codeToGRPC maps error codes to gRPC status codes
+var codeToGRPC = map[ErrorCode]codes.Code{
+	// General
+	CodeUnknown:        codes.Unknown,
+	CodeInternal:       codes.Internal,
+	CodeNotImplemented: codes.Unimplemented,
+	CodeTimeout:        codes.DeadlineExceeded,
+	CodeCanceled:       codes.Canceled,
+
+	// Input/Validation
+	CodeInvalidInput:    codes.InvalidArgument,
+	CodeMissingArgument: codes.InvalidArgument,
+	CodeInvalidArgument: codes.InvalidArgument,
+	CodeValidation:      codes.InvalidArgument,
+
+	// Configuration problems are on the server side
+	CodeConfig:         codes.Internal,
+	CodeConfigNotFound: codes.Internal,
+	CodeConfigInvalid:  codes.Internal,
+	CodeConfigParse:    codes.Internal,
+
+	// File/IO
+	CodeFile:           codes.Internal,
+	CodeFileNotFound:   codes.Internal,
+	CodeFilePermission: codes.Internal,
+	CodeFileRead:       codes.Internal,
+	CodeFileWrite:      codes.Internal,
+	CodeFileCreate:     codes.Internal,
+
+	// Network
+	CodeNetwork:        codes.Unavailable,
+	CodeNetworkTimeout: codes.DeadlineExceeded,
+	CodeNetworkDNS:     codes.Unavailable,
+	CodeNetworkConnect: codes.Unavailable,
+
+	// Auth
+	CodeAuth:         codes.Unauthenticated,
+	CodeUnauthorized: codes.Unauthenticated,
+	CodeForbidden:    codes.PermissionDenied,
+
+	// Resources
+	CodeNotFound:          codes.NotFound,
+	CodeAlreadyExists:     codes.AlreadyExists,
+	CodeResourceExhausted: codes.ResourceExhausted,
+}
+
+// GRPCCode returns the appropriate gRPC status code for an error
+func GRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if code, ok := codeToGRPC[GetCode(err)]; ok {
+		return code
+	}
+	return codes.Unknown
+}
+
+// GRPCStatus converts an error to a gRPC status carrying the user-friendly
+// message. The application error code and details are attached as a
+// google.rpc.ErrorInfo so clients can recover them; details are redacted
+// by the redactor set with SetRedactor.
+func GRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	st := status.New(GRPCCode(err), Message(err))
+	info := &errdetails.ErrorInfo{
+		Reason: string(GetCode(err)),
+		Domain: grpcErrorDomain,
+	}
+
+	var appErr *Error
+	if errors.As(err, &appErr) && len(appErr.Details) > 0 {
+		details := redactDetails(defaultHandler.Redactor, appErr.Details)
+		info.Metadata = make(map[string]string, len(details))
+		for k, v := range details {
+			info.Metadata[k] = fmt.Sprint(v)
+		}
+	}
+
+	if detailed, detailErr := st.WithDetails(info); detailErr == nil {
+		return detailed
+	}
+	return st
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGRPCCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "nil error", err: nil, want: codes.OK},
+		{name: "validation error", err: &ValidationError{Field: "lang"}, want: codes.InvalidArgument},
+		{name: "not found", err: New(CodeNotFound, "missing"), want: codes.NotFound},
+		{name: "already exists", err: New(CodeAlreadyExists, "dup"), want: codes.AlreadyExists},
+		{name: "unauthorized", err: New(CodeUnauthorized, "who"), want: codes.Unauthenticated},
+		{name: "forbidden", err: New(CodeForbidden, "no"), want: codes.PermissionDenied},
+		{name: "timeout", err: New(CodeTimeout, "slow"), want: codes.DeadlineExceeded},
+		{name: "canceled", err: New(CodeCanceled, "stop"), want: codes.Canceled},
+		{name: "network error", err: &NetworkError{URL: "x"}, want: codes.Unavailable},
+		{name: "config error is internal", err: &ConfigError{Key: "x"}, want: codes.Internal},
+		{name: "plain error", err: fmt.Errorf("boom"), want: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GRPCCode(tt.err); got != tt.want {
+				t.Errorf("GRPCCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	err := New(CodeNotFound, "language not found").WithDetails("lang", "tlh")
+
+	st := GRPCStatus(err)
+	if st.Code() != codes.NotFound {
+		t.Errorf("Code() = %v, want %v", st.Code(), codes.NotFound)
+	}
+	if st.Message() != "language not found" {
+		t.Errorf("Message() = %q, want %q", st.Message(), "language not found")
+	}
+
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("Details() = %v, want one ErrorInfo", details)
+	}
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("Details()[0] = %T, want *errdetails.ErrorInfo", details[0])
+	}
+	if info.GetReason() != string(CodeNotFound) || info.GetMetadata()["lang"] != "tlh" {
+		t.Errorf("ErrorInfo = %v, want reason %s with lang metadata", info, CodeNotFound)
+	}
+}
+
+func TestGRPCStatusRedactsDetails(t *testing.T) {
+	err := New(CodeAuth, "login failed").
+		WithDetails("api_token", "abc123").
+		WithDetails("note", "sent to ana@example.com").
+		WithDetails("lang", "en")
+
+	details := GRPCStatus(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("Details() = %v, want one ErrorInfo", details)
+	}
+	metadata := details[0].(*errdetails.ErrorInfo).GetMetadata()
+	want := map[string]string{"api_token": "[REDACTED]", "note": "sent to [REDACTED]", "lang": "en"}
+	if !reflect.DeepEqual(metadata, want) {
+		t.Errorf("Metadata = %v, want %v", metadata, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"runtime/debug"
+	"sort"
 	"strings"
 
 	"github.com/go-cli-template/hello-world-cli/internal/logger"
@@ -13,17 +14,19 @@ import (
 
 // Handler manages error presentation and recovery
 type Handler struct {
-	Output io.Writer
-	Debug  bool
-	Color  bool
+	Output   io.Writer
+	Debug    bool
+	Color    bool
+	Redactor *logger.Redactor // applied to error details
 }
 
 // NewHandler creates a new error handler
 func NewHandler() *Handler {
 	return &Handler{
-		Output: os.Stderr,
-		Debug:  false,
-		Color:  true,
+		Output:   os.Stderr,
+		Debug:    false,
+		Color:    true,
+		Redactor: logger.DefaultRedactor(),
 	}
 }
 
@@ -89,8 +92,14 @@ func (h *Handler) Present(err error) {
 		var appErr *Error
 		if errors.As(err, &appErr) && appErr.Details != nil {
 			msg.WriteString("Details:\n")
-			for k, v := range appErr.Details {
-				msg.WriteString(fmt.Sprintf("  %s: %v\n", k, v))
+			details := redactDetails(h.Redactor, appErr.Details)
+			keys := make([]string, 0, len(details))
+			for k := range details {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				msg.WriteString(fmt.Sprintf("  %s: %v\n", k, details[k]))
 			}
 		}
 	}
@@ -98,6 +107,18 @@ func (h *Handler) Present(err error) {
 	_, _ = fmt.Fprintln(h.Output, msg.String())
 }
 
+// redactDetails returns the error details r lets through, with their
+// values redacted
+func redactDetails(r *logger.Redactor, details map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(details))
+	for k, v := range details {
+		if v, ok := r.Redact(k, v); ok {
+			redacted[k] = v
+		}
+	}
+	return redacted
+}
+
 // getMessage extracts the user-friendly message from an error
 func (h *Handler) getMessage(err error) string {
 	// Check for our custom error type first
@@ -114,11 +135,17 @@ func (h *Handler) getMessage(err error) string {
 
 	var cfgErr *ConfigError
 	if errors.As(err, &cfgErr) {
+		if cfgErr.Value != "" {
+			return fmt.Sprintf("Invalid value %q for %s: %s", cfgErr.Value, cfgErr.Key, cfgErr.Message)
+		}
 		return cfgErr.Error()
 	}
 
 	var fileErr *FileError
 	if errors.As(err, &fileErr) {
+		if errors.Is(fileErr.Err, os.ErrExist) {
+			return fmt.Sprintf("File '%s' already exists", fileErr.Path)
+		}
 		switch fileErr.Operation {
 		case "read":
 			return fmt.Sprintf("Cannot read file '%s'", fileErr.Path)
@@ -179,6 +206,17 @@ func (h *Handler) getSuggestion(err error) string {
 		return "Check the input format and try again"
 	}
 
+	// File errors
+	var fileErr *FileError
+	if errors.As(err, &fileErr) {
+		if errors.Is(fileErr.Err, os.ErrExist) {
+			return "Use --force to overwrite it"
+		}
+		if errors.Is(fileErr.Err, os.ErrPermission) {
+			return "Check file permissions or run with appropriate privileges"
+		}
+	}
+
 	// Network errors
 	var netErr *NetworkError
 	if errors.As(err, &netErr) {
@@ -214,6 +252,7 @@ func PanicHandler() {
 
 		// Present the error
 		handler := NewHandler()
+		handler.Redactor = defaultHandler.Redactor
 		handler.Present(err)
 
 		// Exit with internal error code
@@ -232,4 +271,10 @@ func SetDebug(debugMode bool) {
 	defaultHandler.Debug = debugMode
 }
 
+// SetRedactor sets the redactor applied to error details, when presented
+// and when sent to gRPC clients
+func SetRedactor(r *logger.Redactor) {
+	defaultHandler.Redactor = r
+}
+
 var defaultHandler = NewHandler()
@@ -6,6 +6,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
 )
 
 func TestHandler_Present(t *testing.T) {
@@ -97,6 +99,37 @@ func TestHandler_Present(t *testing.T) {
 	}
 }
 
+func TestHandler_PresentRedactsDetails(t *testing.T) {
+	redactor, err := logger.NewRedactor([]logger.RedactRule{
+		{Key: "*token*"},
+		{Key: "name", Action: logger.RedactHash},
+		{Key: "body", Action: logger.RedactDrop},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	h := &Handler{Output: &buf, Debug: true, Redactor: redactor}
+	h.Present(New(CodeInternal, "Internal error").
+		WithDetails("api_token", "abc123").
+		WithDetails("name", "Alice").
+		WithDetails("body", "secret text").
+		WithDetails("component", "database"))
+
+	output := buf.String()
+	for _, want := range []string{"api_token: [REDACTED]", "name: sha256:", "component: database"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\nGot: %s", want, output)
+		}
+	}
+	for _, leaked := range []string{"abc123", "Alice", "body:"} {
+		if strings.Contains(output, leaked) {
+			t.Errorf("output contains %q\nGot: %s", leaked, output)
+		}
+	}
+}
+
 func TestHandler_GetMessage(t *testing.T) {
 	h := &Handler{}
 
@@ -115,6 +148,16 @@ func TestHandler_GetMessage(t *testing.T) {
 			err:  &ValidationError{Field: "age", Message: "must be positive"},
 			want: "Invalid age: must be positive",
 		},
+		{
+			name: "config error with value",
+			err:  &ConfigError{Key: "log.level", Value: "verbose", Message: "must be one of debug, info"},
+			want: `Invalid value "verbose" for log.level: must be one of debug, info`,
+		},
+		{
+			name: "existing file",
+			err:  &FileError{Path: "config.yaml", Operation: "create", Err: os.ErrExist},
+			want: "File 'config.yaml' already exists",
+		},
 		{
 			name: "os.ErrNotExist",
 			err:  os.ErrNotExist,
@@ -174,6 +217,11 @@ func TestHandler_GetSuggestion(t *testing.T) {
 			err:  &ValidationError{Field: "test", Message: "invalid"},
 			want: "Check the input format and try again",
 		},
+		{
+			name: "file exists",
+			err:  &FileError{Path: "config.yaml", Operation: "create", Err: os.ErrExist},
+			want: "Use --force to overwrite it",
+		},
 		{
 			name: "network 404",
 			err:  &NetworkError{StatusCode: 404},
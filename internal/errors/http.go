@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// statusClientClosedRequest is the non-standard status used when the client
+// cancels a request (nginx convention)
+const statusClientClosedRequest = 499
+
+// codeToHTTP maps error codes to HTTP status codes
+var codeToHTTP = map[ErrorCode]int{
+	// General
+	CodeUnknown:        http.StatusInternalServerError,
+	CodeInternal:       http.StatusInternalServerError,
+	CodeNotImplemented: http.StatusNotImplemented,
+	CodeTimeout:        http.StatusGatewayTimeout,
+	CodeCanceled:       statusClientClosedRequest,
+
+	// Input/Validation
+	CodeInvalidInput:    http.StatusBadRequest,
+	CodeMissingArgument: http.StatusBadRequest,
+	CodeInvalidArgument: http.StatusBadRequest,
+	CodeValidation:      http.StatusBadRequest,
+
+	// Configuration problems are on the server side
+	CodeConfig:         http.StatusInternalServerError,
+	CodeConfigNotFound: http.StatusInternalServerError,
+	CodeConfigInvalid:  http.StatusInternalServerError,
+	CodeConfigParse:    http.StatusInternalServerError,
+
+	// File/IO
+	CodeFile:           http.StatusInternalServerError,
+	CodeFileNotFound:   http.StatusInternalServerError,
+	CodeFilePermission: http.StatusInternalServerError,
+	CodeFileRead:       http.StatusInternalServerError,
+	CodeFileWrite:      http.StatusInternalServerError,
+	CodeFileCreate:     http.StatusInternalServerError,
+
+	// Network
+	CodeNetwork:        http.StatusBadGateway,
+	CodeNetworkTimeout: http.StatusGatewayTimeout,
+	CodeNetworkDNS:     http.StatusBadGateway,
+	CodeNetworkConnect: http.StatusBadGateway,
+
+	// Auth
+	CodeAuth:         http.StatusUnauthorized,
+	CodeUnauthorized: http.StatusUnauthorized,
+	CodeForbidden:    http.StatusForbidden,
+
+	// Resources
+	CodeNotFound:          http.StatusNotFound,
+	CodeAlreadyExists:     http.StatusConflict,
+	CodeResourceExhausted: http.StatusTooManyRequests,
+}
+
+// GetCode returns the error code for an error, deriving one from the
+// specific error types when it is not an *Error
+func GetCode(err error) ErrorCode {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+
+	switch {
+	case IsValidation(err):
+		return CodeValidation
+	case IsConfig(err):
+		return CodeConfig
+	case IsFile(err):
+		return CodeFile
+	case IsNetwork(err):
+		return CodeNetwork
+	default:
+		return CodeUnknown
+	}
+}
+
+// HTTPStatus returns the appropriate HTTP status code for an error
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if status, ok := codeToHTTP[GetCode(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Message returns the user-friendly message for an error, as shown by the
+// error handler
+func Message(err error) string {
+	return (&Handler{}).getMessage(err)
+}
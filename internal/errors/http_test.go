@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{name: "application error", err: New(CodeNotFound, "missing"), want: CodeNotFound},
+		{name: "wrapped application error", err: fmt.Errorf("outer: %w", New(CodeTimeout, "slow")), want: CodeTimeout},
+		{name: "validation error", err: &ValidationError{Field: "name"}, want: CodeValidation},
+		{name: "config error", err: &ConfigError{Key: "log.level"}, want: CodeConfig},
+		{name: "file error", err: &FileError{Path: "x", Operation: "read"}, want: CodeFile},
+		{name: "network error", err: &NetworkError{URL: "x"}, want: CodeNetwork},
+		{name: "plain error", err: fmt.Errorf("boom"), want: CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetCode(tt.err); got != tt.want {
+				t.Errorf("GetCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil error", err: nil, want: http.StatusOK},
+		{name: "validation error", err: &ValidationError{Field: "lang"}, want: http.StatusBadRequest},
+		{name: "invalid argument", err: New(CodeInvalidArgument, "bad"), want: http.StatusBadRequest},
+		{name: "not found", err: New(CodeNotFound, "missing"), want: http.StatusNotFound},
+		{name: "already exists", err: New(CodeAlreadyExists, "dup"), want: http.StatusConflict},
+		{name: "unauthorized", err: New(CodeUnauthorized, "who"), want: http.StatusUnauthorized},
+		{name: "forbidden", err: New(CodeForbidden, "no"), want: http.StatusForbidden},
+		{name: "exhausted", err: New(CodeResourceExhausted, "slow down"), want: http.StatusTooManyRequests},
+		{name: "not implemented", err: New(CodeNotImplemented, "todo"), want: http.StatusNotImplemented},
+		{name: "timeout", err: New(CodeTimeout, "slow"), want: http.StatusGatewayTimeout},
+		{name: "config error is internal", err: &ConfigError{Key: "x"}, want: http.StatusInternalServerError},
+		{name: "plain error is internal", err: fmt.Errorf("boom"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPStatus(tt.err); got != tt.want {
+				t.Errorf("HTTPStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	err := &ValidationError{Field: "emoji", Value: "maybe", Message: "must be true or false"}
+	if got, want := Message(err), "Invalid emoji: must be true or false"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+}
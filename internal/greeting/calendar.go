@@ -0,0 +1,119 @@
+package greeting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// Times of day, available to templates as the {period} variable
+const (
+	PeriodMorning   = "morning"   // 05:00 to 11:59
+	PeriodAfternoon = "afternoon" // 12:00 to 17:59
+	PeriodEvening   = "evening"   // 18:00 to 21:59
+	PeriodNight     = "night"     // 22:00 to 04:59
+)
+
+// PeriodOf returns the time of day for t in its own location
+func PeriodOf(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 12:
+		return PeriodMorning
+	case hour >= 12 && hour < 18:
+		return PeriodAfternoon
+	case hour >= 18 && hour < 22:
+		return PeriodEvening
+	default:
+		return PeriodNight
+	}
+}
+
+// Calendar is a source of holidays, available to templates as the
+// {holiday} variable
+type Calendar interface {
+	// Holiday returns the holiday key for the date of t, or "" if it is
+	// not a holiday
+	Holiday(t time.Time) string
+}
+
+// CalendarFunc adapts a function to the Calendar interface
+type CalendarFunc func(t time.Time) string
+
+// Holiday calls f(t)
+func (f CalendarFunc) Holiday(t time.Time) string {
+	return f(t)
+}
+
+// FixedCalendar maps dates to holiday keys. Keys are either MM-DD for
+// holidays on the same date every year or YYYY-MM-DD for a single date,
+// which wins over MM-DD.
+type FixedCalendar map[string]string
+
+// Holiday returns the holiday on the date of t
+func (c FixedCalendar) Holiday(t time.Time) string {
+	if key, ok := c[t.Format(time.DateOnly)]; ok {
+		return key
+	}
+	return c[t.Format("01-02")]
+}
+
+// DefaultCalendar holds the fixed-date holidays the embedded templates
+// know about
+var DefaultCalendar = FixedCalendar{
+	"01-01": "new_year",
+	"12-25": "christmas",
+}
+
+// LoadCalendar reads a FixedCalendar from a YAML, JSON or TOML file
+// mapping dates to holiday keys
+func LoadCalendar(file string) (FixedCalendar, error) {
+	data, err := os.ReadFile(file) //nolint:gosec // Calendar paths are user-supplied by design
+	if err != nil {
+		return nil, &errors.FileError{Path: file, Operation: "read", Err: err}
+	}
+
+	calendar := make(FixedCalendar)
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		err = json.Unmarshal(data, &calendar)
+	case ".toml":
+		err = toml.NewDecoder(bytes.NewReader(data)).Decode(&calendar)
+	default:
+		err = yaml.Unmarshal(data, &calendar)
+	}
+	if err != nil {
+		return nil, &errors.ConfigError{Key: file, Message: fmt.Sprintf("malformed calendar file: %v", err)}
+	}
+
+	for date, key := range calendar {
+		if !validCalendarDate(date) {
+			return nil, &errors.ConfigError{
+				Key:     file,
+				Value:   date,
+				Message: "calendar dates must be MM-DD or YYYY-MM-DD",
+			}
+		}
+		if key == "" {
+			return nil, &errors.ConfigError{Key: file, Value: date, Message: "holiday key is required"}
+		}
+	}
+	return calendar, nil
+}
+
+// validCalendarDate reports whether date is MM-DD or YYYY-MM-DD
+func validCalendarDate(date string) bool {
+	if _, err := time.Parse(time.DateOnly, date); err == nil {
+		return true
+	}
+	// Parse MM-DD in a leap year so 02-29 is accepted
+	_, err := time.Parse(time.DateOnly, "2000-"+date)
+	return err == nil && len(date) == len("01-02")
+}
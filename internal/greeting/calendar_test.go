@@ -0,0 +1,150 @@
+package greeting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+func TestPeriodOf(t *testing.T) {
+	tests := []struct {
+		hour int
+		want string
+	}{
+		{hour: 0, want: PeriodNight},
+		{hour: 4, want: PeriodNight},
+		{hour: 5, want: PeriodMorning},
+		{hour: 11, want: PeriodMorning},
+		{hour: 12, want: PeriodAfternoon},
+		{hour: 17, want: PeriodAfternoon},
+		{hour: 18, want: PeriodEvening},
+		{hour: 21, want: PeriodEvening},
+		{hour: 22, want: PeriodNight},
+	}
+
+	for _, tt := range tests {
+		at := time.Date(2026, time.March, 10, tt.hour, 30, 0, 0, time.UTC)
+		if got := PeriodOf(at); got != tt.want {
+			t.Errorf("PeriodOf(%02d:30) = %q, want %q", tt.hour, got, tt.want)
+		}
+	}
+}
+
+func TestFixedCalendar(t *testing.T) {
+	calendar := FixedCalendar{
+		"12-25":      "christmas",
+		"2026-12-25": "christmas_2026",
+		"2026-05-01": "may_day",
+	}
+
+	tests := []struct {
+		date string
+		want string
+	}{
+		{date: "2025-12-25", want: "christmas"},
+		{date: "2026-12-25", want: "christmas_2026"},
+		{date: "2026-05-01", want: "may_day"},
+		{date: "2027-05-01", want: ""},
+	}
+
+	for _, tt := range tests {
+		at, err := time.Parse(time.DateOnly, tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := calendar.Holiday(at); got != tt.want {
+			t.Errorf("Holiday(%s) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCalendar(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		want     FixedCalendar
+		wantCode errors.ErrorCode
+	}{
+		{
+			name:    "yaml",
+			file:    "holidays.yaml",
+			content: "\"07-14\": bastille_day\n\"2028-02-29\": leap_day\n",
+			want:    FixedCalendar{"07-14": "bastille_day", "2028-02-29": "leap_day"},
+		},
+		{
+			name:    "json",
+			file:    "holidays.json",
+			content: `{"02-29": "leap_day"}`,
+			want:    FixedCalendar{"02-29": "leap_day"},
+		},
+		{
+			name:    "toml",
+			file:    "holidays.toml",
+			content: "\"10-03\" = \"unity_day\"\n",
+			want:    FixedCalendar{"10-03": "unity_day"},
+		},
+		{
+			name:     "invalid date",
+			file:     "holidays.yaml",
+			content:  "\"13-01\": nope\n",
+			wantCode: errors.CodeConfig,
+		},
+		{
+			name:     "not a leap year",
+			file:     "holidays.yaml",
+			content:  "\"2026-02-29\": leap_day\n",
+			wantCode: errors.CodeConfig,
+		},
+		{
+			name:     "empty holiday key",
+			file:     "holidays.yaml",
+			content:  "\"01-01\": \"\"\n",
+			wantCode: errors.CodeConfig,
+		},
+		{
+			name:     "malformed file",
+			file:     "holidays.json",
+			content:  "{",
+			wantCode: errors.CodeConfig,
+		},
+		{
+			name:     "missing file",
+			file:     "missing.yaml",
+			wantCode: errors.CodeFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadCalendar(path)
+			if tt.wantCode != "" {
+				if got := errors.GetCode(err); err == nil || got != tt.wantCode {
+					t.Fatalf("LoadCalendar() error = %v (code %s), want code %s", err, got, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadCalendar() unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("LoadCalendar() = %v, want %v", got, tt.want)
+			}
+			for date, key := range tt.want {
+				if got[date] != key {
+					t.Errorf("LoadCalendar()[%q] = %q, want %q", date, got[date], key)
+				}
+			}
+		})
+	}
+}
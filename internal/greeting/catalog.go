@@ -0,0 +1,371 @@
+package greeting
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultLanguage is used when a requested language is not in the catalog
+const defaultLanguage = "en"
+
+//go:embed locales/*.yaml
+var embeddedLocales embed.FS
+
+// Translation holds the localized strings for a single language
+type Translation struct {
+	Template string `json:"template,omitempty" yaml:"template,omitempty" toml:"template,omitempty"` // Personalized greeting message; see templateVariables
+	Hello    string `json:"hello,omitempty" yaml:"hello,omitempty" toml:"hello,omitempty"`          // Greeting used when no name is given; see helloVariables
+	Emoji    string `json:"emoji,omitempty" yaml:"emoji,omitempty" toml:"emoji,omitempty"`          // Emoji prefix used with --emoji
+	And      string `json:"and,omitempty" yaml:"and,omitempty" toml:"and,omitempty"`                // Word joining the last two names of a group
+}
+
+// templateVariables are the variables available to greeting templates
+var templateVariables = map[string]bool{
+	"name":    true, // The person greeted, or the joined names of a group
+	"count":   true, // Number of people greeted
+	"gender":  true, // female, male or other
+	"period":  true, // morning, afternoon, evening or night
+	"holiday": true, // Holiday key from the calendar, or empty
+}
+
+// helloVariables are the variables available to hello messages
+var helloVariables = map[string]bool{
+	"period":  true,
+	"holiday": true,
+}
+
+// messages holds the compiled messages of a translation
+type messages struct {
+	template *Message
+	hello    *Message
+}
+
+// Catalog is a set of translations keyed by language code.
+//
+// Locale files are named after their BCP 47 language tag (es.yaml,
+// pt-BR.json, zh_TW.toml) and may override only some fields; missing fields are kept
+// from the translation loaded before them.
+type Catalog struct {
+	translations map[string]Translation
+	messages     map[string]messages
+}
+
+// NewCatalog creates an empty catalog
+func NewCatalog() *Catalog {
+	return &Catalog{
+		translations: make(map[string]Translation),
+		messages:     make(map[string]messages),
+	}
+}
+
+// LoadCatalog builds a catalog from the embedded defaults and then merges
+// locale files from each directory in order, so later directories win.
+// Directories that do not exist are skipped.
+func LoadCatalog(dirs ...string) (*Catalog, error) {
+	c := NewCatalog()
+	if err := c.loadFS(embeddedLocales, "locales", "embedded"); err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if err := c.LoadDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadDir merges every locale file in dir into the catalog, in lexical order
+func (c *Catalog) LoadDir(dir string) error {
+	if _, err := os.Stat(dir); stderrors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return c.loadFS(os.DirFS(dir), ".", dir)
+}
+
+// LoadFile merges a single locale file into the catalog
+func (c *Catalog) LoadFile(file string) error {
+	data, err := os.ReadFile(file) //nolint:gosec // Locale paths are user-supplied by design
+	if err != nil {
+		return &errors.FileError{Path: file, Operation: "read", Err: err}
+	}
+	return c.merge(file, data)
+}
+
+// loadFS merges locale files from root inside fsys, reporting paths
+// relative to shown in errors
+func (c *Catalog) loadFS(fsys fs.FS, root, shown string) error {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return &errors.FileError{Path: shown, Operation: "read", Err: err}
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isLocaleFile(entry.Name()) {
+			continue
+		}
+		name := filepath.Join(shown, entry.Name())
+		data, err := fs.ReadFile(fsys, path.Join(root, entry.Name()))
+		if err != nil {
+			return &errors.FileError{Path: name, Operation: "read", Err: err}
+		}
+		if err := c.merge(name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge decodes a locale file and overlays its fields onto the catalog
+func (c *Catalog) merge(file string, data []byte) error {
+	var t Translation
+	if err := decodeLocale(file, data, &t); err != nil {
+		return &errors.ConfigError{
+			Key:     file,
+			Message: fmt.Sprintf("malformed locale file: %v", err),
+		}
+	}
+
+	tag, err := ParseTag(languageFromFile(file))
+	if err != nil {
+		return &errors.ConfigError{
+			Key:     file,
+			Message: fmt.Sprintf("locale file name is not a language tag: %v", err),
+		}
+	}
+	lang := tag.String()
+
+	if isLegacyTemplate(t.Template) {
+		upgraded, err := upgradeLegacyTemplate(t.Template)
+		if err != nil {
+			return &errors.ConfigError{
+				Key:     lang + ".template",
+				Value:   t.Template,
+				Message: fmt.Sprintf("%v (in %s)", err, file),
+			}
+		}
+		t.Template = upgraded
+	}
+
+	existing := c.translations[lang]
+	if t.Template != "" {
+		existing.Template = t.Template
+	}
+	if t.Hello != "" {
+		existing.Hello = t.Hello
+	}
+	if t.Emoji != "" {
+		existing.Emoji = t.Emoji
+	}
+	if t.And != "" {
+		existing.And = t.And
+	}
+
+	compiled, cfgErr := compileTranslation(lang, existing)
+	if cfgErr != nil {
+		cfgErr.Message = fmt.Sprintf("%s (in %s)", cfgErr.Message, file)
+		return cfgErr
+	}
+	c.translations[lang] = existing
+	c.messages[lang] = compiled
+	return nil
+}
+
+// compileTranslation parses the messages of a translation, checking that
+// they only use the variables available to them
+func compileTranslation(lang string, t Translation) (messages, *errors.ConfigError) {
+	var m messages
+	if t.Template != "" {
+		tmpl, err := ParseMessage(t.Template)
+		if err == nil {
+			err = validateTemplate(tmpl)
+		}
+		if err != nil {
+			return m, &errors.ConfigError{Key: lang + ".template", Value: t.Template, Message: err.Error()}
+		}
+		m.template = tmpl
+	}
+
+	if t.Hello != "" {
+		hello, err := ParseMessage(t.Hello)
+		if err == nil {
+			for _, name := range hello.Variables() {
+				if !helloVariables[name] {
+					err = fmt.Errorf("hello message uses unknown variable {%s}", name)
+					break
+				}
+			}
+		}
+		if err != nil {
+			return m, &errors.ConfigError{Key: lang + ".hello", Value: t.Hello, Message: err.Error()}
+		}
+		m.hello = hello
+	}
+	return m, nil
+}
+
+// Validate checks that every language has a usable template and hello message
+func (c *Catalog) Validate() error {
+	if _, ok := c.translations[defaultLanguage]; !ok {
+		return &errors.ConfigError{
+			Key:     defaultLanguage,
+			Message: "default language is missing from the catalog",
+		}
+	}
+
+	for _, lang := range c.Languages() {
+		t := c.translations[lang]
+		if t.Template == "" {
+			return &errors.ConfigError{Key: lang + ".template", Message: "template is required"}
+		}
+		if t.Hello == "" {
+			return &errors.ConfigError{Key: lang + ".hello", Message: "hello is required"}
+		}
+	}
+	return nil
+}
+
+// Lookup returns the translation for an exact language tag
+func (c *Catalog) Lookup(lang string) (Translation, bool) {
+	if tag, err := ParseTag(lang); err == nil {
+		lang = tag.String()
+	}
+	t, ok := c.translations[lang]
+	return t, ok
+}
+
+// Languages returns the language codes in the catalog, sorted
+func (c *Catalog) Languages() []string {
+	languages := make([]string, 0, len(c.translations))
+	for code := range c.translations {
+		languages = append(languages, code)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// validateTemplate ensures a template uses {name} and no unknown variables
+func validateTemplate(tmpl *Message) error {
+	usesName := false
+	for _, name := range tmpl.Variables() {
+		if !templateVariables[name] {
+			return fmt.Errorf("template uses unknown variable {%s}", name)
+		}
+		usesName = usesName || name == "name"
+	}
+	if !usesName {
+		return fmt.Errorf("template must use the {name} variable")
+	}
+	return nil
+}
+
+// isLegacyTemplate reports whether a template uses the old fmt syntax
+func isLegacyTemplate(tmpl string) bool {
+	return strings.Contains(tmpl, "%") && !strings.ContainsAny(tmpl, "{}")
+}
+
+// upgradeLegacyTemplate converts an old template with exactly one %s to a
+// message using {name}. Deprecated: locale files should use {name} directly.
+func upgradeLegacyTemplate(tmpl string) (string, error) {
+	var b strings.Builder
+	placeholders := 0
+	for i := 0; i < len(tmpl); i++ {
+		if tmpl[i] != '%' {
+			b.WriteByte(tmpl[i])
+			continue
+		}
+		if i+1 >= len(tmpl) {
+			return "", fmt.Errorf("template ends with a dangling %%")
+		}
+		i++
+		switch tmpl[i] {
+		case '%':
+			b.WriteByte('%')
+		case 's':
+			placeholders++
+			b.WriteString("{name}")
+		default:
+			return "", fmt.Errorf("template contains unsupported verb %%%c", tmpl[i])
+		}
+	}
+
+	if placeholders != 1 {
+		return "", fmt.Errorf("template must contain exactly one %%s placeholder, found %d", placeholders)
+	}
+	return strings.ReplaceAll(b.String(), "'", "''"), nil
+}
+
+// decodeLocale unmarshals data according to the file extension
+func decodeLocale(file string, data []byte, t *Translation) error {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(t)
+	case ".toml":
+		return toml.NewDecoder(bytes.NewReader(data)).DisallowUnknownFields().Decode(t)
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(t); err != nil && !stderrors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	}
+}
+
+// isLocaleFile reports whether name has a supported locale file extension
+func isLocaleFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json", ".toml":
+		return true
+	default:
+		return false
+	}
+}
+
+// languageFromFile derives the language code from a locale file name
+func languageFromFile(file string) string {
+	base := filepath.Base(file)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// defaultCatalog holds the catalog used by Generate
+var defaultCatalog atomic.Pointer[Catalog]
+
+func init() {
+	c, err := LoadCatalog()
+	if err != nil {
+		panic(fmt.Sprintf("embedded greeting catalog is invalid: %v", err))
+	}
+	defaultCatalog.Store(c)
+}
+
+// SetDefaultCatalog replaces the catalog used by Generate
+func SetDefaultCatalog(c *Catalog) {
+	defaultCatalog.Store(c)
+}
+
+// DefaultCatalog returns the catalog used by Generate
+func DefaultCatalog() *Catalog {
+	return defaultCatalog.Load()
+}
@@ -0,0 +1,252 @@
+package greeting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+func writeLocale(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+// Embedded messages, repeated here to keep the expectations readable
+const (
+	enTemplate = "{holiday, select, christmas{Merry Christmas} new_year{Happy New Year} other{{period, select, morning{Good morning} afternoon{Good afternoon} evening{Good evening} night{{count, plural, one{Hello there} other{Hello}}} other{{count, plural, one{Hello there} other{Hello}}}}}}" +
+		"{count, plural, one{, {name}} =2{ to you both, {name}} other{ to all # of you, {name}}}! 🎉"
+	enHello    = "{holiday, select, christmas{Merry Christmas, World!} new_year{Happy New Year, World!} other{{period, select, morning{Good morning, World!} afternoon{Good afternoon, World!} evening{Good evening, World!} night{Hello, World!} other{Hello, World!}}}}"
+	esTemplate = "{holiday, select, christmas{¡Feliz Navidad} new_year{¡Feliz Año Nuevo} other{{period, select, morning{¡Buenos días} afternoon{¡Buenas tardes} evening{¡Buenas noches} night{¡Buenas noches} other{¡Hola}}}}" +
+		"{count, plural, one{, {name}!} other{ a {gender, select, female{todas} other{todos}}, {name}!}}"
+	esHello    = "{holiday, select, christmas{¡Feliz Navidad, Mundo!} new_year{¡Feliz Año Nuevo, Mundo!} other{{period, select, morning{¡Buenos días, Mundo!} afternoon{¡Buenas tardes, Mundo!} evening{¡Buenas noches, Mundo!} night{¡Buenas noches, Mundo!} other{¡Hola, Mundo!}}}}"
+	frTemplate = "{holiday, select, christmas{Joyeux Noël} new_year{Bonne année} other{{period, select, evening{Bonsoir} night{Bonsoir} other{Bonjour}}}}" +
+		"{count, plural, one{, {name}!} other{ à {gender, select, female{toutes} other{tous}}, {name}!}}"
+	deTemplate = "{holiday, select, christmas{Frohe Weihnachten} new_year{Frohes neues Jahr} other{{period, select, morning{Guten Morgen} afternoon{Guten Tag} evening{Guten Abend} night{Hallo} other{Hallo}}}}" +
+		"{count, plural, one{, {name}!} other{ zusammen, {name}!}}"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		lang      string
+		want      Translation
+		wantError bool
+	}{
+		{
+			name: "embedded defaults",
+			lang: "es",
+			want: Translation{Template: esTemplate, Hello: esHello, Emoji: "👋", And: "y"},
+		},
+		{
+			name: "new language from yaml",
+			files: map[string]string{
+				"pt.yaml": "template: \"Olá, {name}!\"\nhello: \"Olá, Mundo!\"\nand: \"e\"\n",
+			},
+			lang: "pt",
+			want: Translation{Template: "Olá, {name}!", Hello: "Olá, Mundo!", And: "e"},
+		},
+		{
+			name: "legacy %s template is upgraded",
+			files: map[string]string{
+				"it.yaml": "template: \"Ciao, %s, com'è?\"\nhello: \"Ciao, mondo!\"\n",
+			},
+			lang: "it",
+			want: Translation{Template: "Ciao, {name}, com''è?", Hello: "Ciao, mondo!"},
+		},
+		{
+			name: "partial override from json keeps other fields",
+			files: map[string]string{
+				"es.json": `{"template": "¡Buenas, {name}!"}`,
+			},
+			lang: "es",
+			want: Translation{Template: "¡Buenas, {name}!", Hello: esHello, Emoji: "👋", And: "y"},
+		},
+		{
+			name: "override from toml",
+			files: map[string]string{
+				"de.toml": "hello = \"Servus, Welt!\"\n",
+			},
+			lang: "de",
+			want: Translation{Template: deTemplate, Hello: "Servus, Welt!", Emoji: "👋", And: "und"},
+		},
+		{
+			name: "lexical order decides merge",
+			files: map[string]string{
+				"fr.json": `{"hello": "Salut!"}`,
+				"fr.yaml": "hello: \"Coucou!\"\n",
+			},
+			lang: "fr",
+			want: Translation{Template: frTemplate, Hello: "Coucou!", Emoji: "👋", And: "et"},
+		},
+		{
+			name: "unrelated files are ignored",
+			files: map[string]string{
+				"README.md": "# not a locale",
+			},
+			lang: "en",
+			want: Translation{Template: enTemplate, Hello: enHello, Emoji: "👋", And: "and"},
+		},
+		{
+			name: "malformed yaml",
+			files: map[string]string{
+				"it.yaml": "template: [unterminated\n",
+			},
+			wantError: true,
+		},
+		{
+			name: "unknown field",
+			files: map[string]string{
+				"it.yaml": "greeting: \"Ciao, %s!\"\n",
+			},
+			wantError: true,
+		},
+		{
+			name: "template without placeholder",
+			files: map[string]string{
+				"it.yaml": "template: \"Ciao!\"\nhello: \"Ciao, mondo!\"\n",
+			},
+			wantError: true,
+		},
+		{
+			name: "template with unknown variable",
+			files: map[string]string{
+				"it.yaml": "template: \"Ciao, {nome}!\"\nhello: \"Ciao, mondo!\"\n",
+			},
+			wantError: true,
+		},
+		{
+			name: "template with syntax error",
+			files: map[string]string{
+				"it.yaml": "template: \"{count, plural, one{Ciao, {name}!}}\"\nhello: \"Ciao, mondo!\"\n",
+			},
+			wantError: true,
+		},
+		{
+			name: "hello with variable",
+			files: map[string]string{
+				"it.yaml": "template: \"Ciao, {name}!\"\nhello: \"Ciao, {name}!\"\n",
+			},
+			wantError: true,
+		},
+		{
+			name: "new language missing hello",
+			files: map[string]string{
+				"it.yaml": "template: \"Ciao, %s!\"\n",
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeLocale(t, dir, name, content)
+			}
+
+			catalog, err := LoadCatalog(dir)
+			if tt.wantError {
+				if !errors.IsConfig(err) {
+					t.Fatalf("LoadCatalog() error = %v, want ConfigError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadCatalog() unexpected error: %v", err)
+			}
+
+			got, ok := catalog.Lookup(tt.lang)
+			if !ok {
+				t.Fatalf("Lookup(%q) not found", tt.lang)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup(%q) = %+v, want %+v", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCatalogMissingDir(t *testing.T) {
+	catalog, err := LoadCatalog(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("LoadCatalog() unexpected error: %v", err)
+	}
+	if len(catalog.Languages()) != 6 {
+		t.Errorf("Languages() = %v, want the 6 embedded languages", catalog.Languages())
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		wantErr bool
+	}{
+		{tmpl: "Hello, {name}!"},
+		{tmpl: "{count, plural, one{Hi, {name}} other{Hi, all # of you}}"},
+		{tmpl: "{gender, select, female{Bienvenida, {name}} other{Bienvenido, {name}}}"},
+		{tmpl: "Hello!", wantErr: true},
+		{tmpl: "Hello, {nombre}!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			msg, err := ParseMessage(tt.tmpl)
+			if err != nil {
+				t.Fatalf("ParseMessage(%q) unexpected error: %v", tt.tmpl, err)
+			}
+			err = validateTemplate(msg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTemplate(%q) error = %v, wantErr %v", tt.tmpl, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpgradeLegacyTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{tmpl: "Hello, %s!", want: "Hello, {name}!"},
+		{tmpl: "100%% sure, %s", want: "100% sure, {name}"},
+		{tmpl: "Hello!", wantErr: true},
+		{tmpl: "%s and %s", wantErr: true},
+		{tmpl: "Hello, %d", wantErr: true},
+		{tmpl: "Hello, %s %", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			got, err := upgradeLegacyTemplate(tt.tmpl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("upgradeLegacyTemplate(%q) error = %v, wantErr %v", tt.tmpl, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("upgradeLegacyTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetDefaultCatalog(t *testing.T) {
+	original := DefaultCatalog()
+	t.Cleanup(func() { SetDefaultCatalog(original) })
+
+	dir := t.TempDir()
+	writeLocale(t, dir, "en.yaml", "template: \"Hi, {name}.\"\n")
+	catalog, err := LoadCatalog(dir)
+	if err != nil {
+		t.Fatalf("LoadCatalog() unexpected error: %v", err)
+	}
+	SetDefaultCatalog(catalog)
+
+	if got := Generate(Options{Name: "Ada", Language: "en"}).Message; got != "Hi, Ada." {
+		t.Errorf("Generate() message = %q, want %q", got, "Hi, Ada.")
+	}
+}
@@ -2,73 +2,206 @@ package greeting
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
 )
 
 // Greeting represents a greeting message
 type Greeting struct {
-	Message   string    `json:"message"`
-	Language  string    `json:"language,omitempty"`
-	Emoji     string    `json:"emoji,omitempty"`
-	Timestamp time.Time `json:"timestamp"`
+	Message           string     `json:"message"`
+	Language          string     `json:"language,omitempty"`
+	RequestedLanguage string     `json:"requestedLanguage,omitempty"`
+	Confidence        Confidence `json:"confidence,omitempty"`
+	Emoji             string     `json:"emoji,omitempty"`
+	Period            string     `json:"period,omitempty"`
+	Holiday           string     `json:"holiday,omitempty"`
+	Timestamp         time.Time  `json:"timestamp"`
+}
+
+// String returns the greeting message
+func (g *Greeting) String() string {
+	return g.Message
 }
 
+// Grammatical genders accepted in Options.Gender, selected on by templates
+// through the {gender} variable
+const (
+	GenderFemale = "female"
+	GenderMale   = "male"
+	GenderOther  = "other"
+)
+
 // Options configures how a greeting is generated
 type Options struct {
 	Name         string
-	Language     string
+	Names        []string // Greet a group of people; used instead of Name when set
+	Gender       string   // female, male or other (the default)
+	Language     string   // BCP 47 tag or POSIX locale name
+	Fallbacks    []string // Languages to try when Language does not match
 	IncludeEmoji bool
+
+	Clock    func() time.Time // Returns the current time; defaults to time.Now
+	Location *time.Location   // Time zone deciding the time of day; defaults to the clock's
+	Calendar Calendar         // Holiday source; nil disables holiday greetings
+}
+
+// now returns the current time in the configured location
+func (o Options) now() time.Time {
+	clock := o.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	now := clock()
+	if o.Location != nil {
+		now = now.In(o.Location)
+	}
+	return now
 }
 
-// Language translations
-var translations = map[string]struct {
-	template string
-	hello    string
-	emoji    string
-}{
-	"en": {"Hello there, %s! 🎉", "Hello, World!", "👋"},
-	"es": {"¡Hola, %s!", "¡Hola, Mundo!", "👋"},
-	"fr": {"Bonjour, %s!", "Bonjour le monde!", "👋"},
-	"de": {"Hallo, %s!", "Hallo, Welt!", "👋"},
-	"ja": {"こんにちは、%sさん！", "こんにちは、世界！", "🇯🇵"},
-	"zh": {"你好，%s！", "你好，世界！", "🇨🇳"},
+// people returns everyone to greet
+func (o Options) people() []string {
+	if len(o.Names) > 0 {
+		return o.Names
+	}
+	if o.Name != "" {
+		return []string{o.Name}
+	}
+	return nil
+}
+
+// ParseGender normalizes a gender, returning GenderOther for an empty string
+func ParseGender(s string) (string, error) {
+	switch g := strings.ToLower(strings.TrimSpace(s)); g {
+	case "":
+		return GenderOther, nil
+	case GenderFemale, GenderMale, GenderOther:
+		return g, nil
+	default:
+		return "", fmt.Errorf("unknown gender %q (want %s, %s or %s)", s, GenderFemale, GenderMale, GenderOther)
+	}
 }
 
 // Generate creates a greeting based on the given options
 func Generate(opts Options) *Greeting {
+	// Negotiate the language, falling back to English if nothing matches
+	catalog := DefaultCatalog()
+	match := catalog.Match(opts.Language, opts.Fallbacks)
+	lang, _ := catalog.Lookup(match.Tag)
+
+	now := opts.now()
 	greeting := &Greeting{
-		Timestamp: time.Now(),
-		Language:  opts.Language,
+		Timestamp:         now,
+		Language:          match.Tag,
+		RequestedLanguage: opts.Language,
+		Confidence:        match.Confidence,
+		Period:            PeriodOf(now),
+	}
+	if opts.Calendar != nil {
+		greeting.Holiday = opts.Calendar.Holiday(now)
 	}
 
-	// Get language data, default to English if not found
-	lang, ok := translations[opts.Language]
-	if !ok {
-		lang = translations["en"]
-		greeting.Language = "en"
+	gender, err := ParseGender(opts.Gender)
+	if err != nil {
+		gender = GenderOther
 	}
 
 	// Generate the message
-	if opts.Name != "" {
-		greeting.Message = fmt.Sprintf(lang.template, opts.Name)
-	} else {
-		greeting.Message = lang.hello
-	}
+	greeting.Message = catalog.render(match.Tag, lang, opts.people(), Args{
+		"gender":  gender,
+		"period":  greeting.Period,
+		"holiday": greeting.Holiday,
+	})
 
 	// Add emoji if requested
 	if opts.IncludeEmoji {
-		greeting.Emoji = lang.emoji
+		greeting.Emoji = lang.Emoji
 		greeting.Message = fmt.Sprintf("%s %s", greeting.Emoji, greeting.Message)
 	}
 
 	return greeting
 }
 
+// render formats the template for people, or the hello message when there
+// is nobody to greet by name. vars holds the other template variables.
+func (c *Catalog) render(lang string, t Translation, people []string, vars Args) string {
+	compiled := c.messages[lang]
+	tag := language.Make(lang)
+
+	args := make(Args, len(vars)+2)
+	for k, v := range vars {
+		args[k] = v
+	}
+
+	// Variables are checked when the catalog is loaded, so formatting only
+	// fails if the catalog was built without validation
+	if len(people) == 0 || compiled.template == nil {
+		if compiled.hello == nil {
+			return t.Hello
+		}
+		message, err := compiled.hello.Format(tag, args)
+		if err != nil {
+			return t.Hello
+		}
+		return message
+	}
+
+	args["name"] = joinNames(tag, people, t.And)
+	args["count"] = len(people)
+	message, err := compiled.template.Format(tag, args)
+	if err != nil {
+		return t.Hello
+	}
+	return message
+}
+
+// joinNames lists names as "Ana, Bob and Chloé", using the language's
+// conjunction when it has one. Languages written without spaces between
+// words join with "、" and no spaces.
+func joinNames(tag language.Tag, names []string, and string) string {
+	sep, space := ", ", " "
+	if script, _ := tag.Script(); script == language.MustParseScript("Jpan") ||
+		script == language.MustParseScript("Hans") || script == language.MustParseScript("Hant") {
+		sep, space = "、", ""
+	}
+
+	if len(names) < 2 || and == "" {
+		return strings.Join(names, sep)
+	}
+	last := len(names) - 1
+	return strings.Join(names[:last], sep) + space + and + space + names[last]
+}
+
 // GetSupportedLanguages returns all supported language codes
 func GetSupportedLanguages() []string {
-	languages := make([]string, 0, len(translations))
-	for code := range translations {
-		languages = append(languages, code)
+	return DefaultCatalog().Languages()
+}
+
+// LanguageInfo describes a supported language
+type LanguageInfo struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Hello string `json:"hello"`
+}
+
+// DescribeLanguages returns every supported language with its name in
+// that language and its hello message, in its form for no particular time
+// of day
+func DescribeLanguages() []LanguageInfo {
+	catalog := DefaultCatalog()
+	codes := catalog.Languages()
+	langs := make([]LanguageInfo, 0, len(codes))
+	for _, code := range codes {
+		info := LanguageInfo{Code: code}
+		if tag, err := language.Parse(code); err == nil {
+			info.Name = display.Self.Name(tag)
+		}
+		if t, ok := catalog.Lookup(code); ok {
+			info.Hello = catalog.render(code, t, nil, Args{"period": "", "holiday": ""})
+		}
+		langs = append(langs, info)
 	}
-	return languages
+	return langs
 }
@@ -2,8 +2,19 @@ package greeting
 
 import (
 	"testing"
+	"time"
 )
 
+// fixedClock returns a clock stopped at the RFC 3339 time value
+func fixedClock(t *testing.T, value string) func() time.Time {
+	t.Helper()
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func() time.Time { return at }
+}
+
 func TestGenerate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -39,7 +50,7 @@ func TestGenerate(t *testing.T) {
 				Name:     "Carlos",
 				Language: "es",
 			},
-			wantMsg: "¡Hola, Carlos!",
+			wantMsg: "¡Buenas noches, Carlos!",
 		},
 		{
 			name: "japanese greeting with emoji",
@@ -48,7 +59,7 @@ func TestGenerate(t *testing.T) {
 				Language:     "ja",
 				IncludeEmoji: true,
 			},
-			wantMsg: "🇯🇵 こんにちは、Tanakaさん！",
+			wantMsg: "🇯🇵 こんばんは、Tanakaさん！",
 		},
 		{
 			name: "unknown language falls back to english",
@@ -62,6 +73,7 @@ func TestGenerate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Clock = fixedClock(t, "2026-03-10T23:00:00Z")
 			greeting := Generate(tt.opts)
 			if greeting.Message != tt.wantMsg {
 				t.Errorf("Generate() message = %v, want %v", greeting.Message, tt.wantMsg)
@@ -73,6 +85,160 @@ func TestGenerate(t *testing.T) {
 	}
 }
 
+func TestGenerateGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantMsg string
+	}{
+		{
+			name:    "names replace name",
+			opts:    Options{Name: "Ignored", Names: []string{"Ana"}, Language: "en"},
+			wantMsg: "Hello there, Ana! 🎉",
+		},
+		{
+			name:    "two people",
+			opts:    Options{Names: []string{"Ana", "Bob"}, Language: "en"},
+			wantMsg: "Hello to you both, Ana and Bob! 🎉",
+		},
+		{
+			name:    "many people",
+			opts:    Options{Names: []string{"Ana", "Bob", "Chloé"}, Language: "en"},
+			wantMsg: "Hello to all 3 of you, Ana, Bob and Chloé! 🎉",
+		},
+		{
+			name:    "spanish masculine plural by default",
+			opts:    Options{Names: []string{"Ana", "Bob"}, Language: "es"},
+			wantMsg: "¡Buenas noches a todos, Ana y Bob!",
+		},
+		{
+			name:    "spanish feminine plural",
+			opts:    Options{Names: []string{"Ana", "Eva"}, Gender: GenderFemale, Language: "es"},
+			wantMsg: "¡Buenas noches a todas, Ana y Eva!",
+		},
+		{
+			name:    "japanese joins without spaces",
+			opts:    Options{Names: []string{"田中", "鈴木", "佐藤"}, Language: "ja"},
+			wantMsg: "こんばんは、田中、鈴木と佐藤さん！",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Clock = fixedClock(t, "2026-03-10T23:00:00Z")
+			if got := Generate(tt.opts).Message; got != tt.wantMsg {
+				t.Errorf("Generate() message = %q, want %q", got, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestGenerateTimeOfDay(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		at          string
+		opts        Options
+		wantMsg     string
+		wantPeriod  string
+		wantHoliday string
+	}{
+		{
+			name:       "morning",
+			at:         "2026-03-10T08:00:00Z",
+			opts:       Options{Name: "Ana", Language: "es"},
+			wantMsg:    "¡Buenos días, Ana!",
+			wantPeriod: PeriodMorning,
+		},
+		{
+			name:       "location decides the period",
+			at:         "2026-03-10T08:00:00Z",
+			opts:       Options{Name: "Ana", Language: "es", Location: tokyo},
+			wantMsg:    "¡Buenas tardes, Ana!",
+			wantPeriod: PeriodAfternoon,
+		},
+		{
+			name:       "english says hello at night",
+			at:         "2026-03-10T23:00:00Z",
+			opts:       Options{Name: "Ana", Language: "en"},
+			wantMsg:    "Hello there, Ana! 🎉",
+			wantPeriod: PeriodNight,
+		},
+		{
+			name:       "holidays need a calendar",
+			at:         "2026-12-25T19:00:00Z",
+			opts:       Options{Language: "de"},
+			wantMsg:    "Guten Abend, Welt!",
+			wantPeriod: PeriodEvening,
+		},
+		{
+			name:        "holiday from calendar",
+			at:          "2026-12-25T19:00:00Z",
+			opts:        Options{Names: []string{"Ana", "Bob"}, Language: "en", Calendar: DefaultCalendar},
+			wantMsg:     "Merry Christmas to you both, Ana and Bob! 🎉",
+			wantPeriod:  PeriodEvening,
+			wantHoliday: "christmas",
+		},
+		{
+			name:        "holiday unknown to the template",
+			at:          "2026-07-14T10:00:00Z",
+			opts:        Options{Name: "Marie", Language: "fr", Calendar: FixedCalendar{"07-14": "bastille_day"}},
+			wantMsg:     "Bonjour, Marie!",
+			wantPeriod:  PeriodMorning,
+			wantHoliday: "bastille_day",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Clock = fixedClock(t, tt.at)
+			got := Generate(tt.opts)
+			if got.Message != tt.wantMsg {
+				t.Errorf("Generate() message = %q, want %q", got.Message, tt.wantMsg)
+			}
+			if got.Period != tt.wantPeriod {
+				t.Errorf("Generate() period = %q, want %q", got.Period, tt.wantPeriod)
+			}
+			if got.Holiday != tt.wantHoliday {
+				t.Errorf("Generate() holiday = %q, want %q", got.Holiday, tt.wantHoliday)
+			}
+			if want := tt.opts.Clock(); !got.Timestamp.Equal(want) {
+				t.Errorf("Generate() timestamp = %v, want %v", got.Timestamp, want)
+			}
+		})
+	}
+}
+
+func TestParseGender(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "", want: GenderOther},
+		{input: "Female", want: GenderFemale},
+		{input: "male", want: GenderMale},
+		{input: "other", want: GenderOther},
+		{input: "robot", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseGender(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGender(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseGender(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetSupportedLanguages(t *testing.T) {
 	langs := GetSupportedLanguages()
 	if len(langs) == 0 {
@@ -0,0 +1,152 @@
+package greeting
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Confidence describes how closely a resolved language matches the request
+type Confidence string
+
+// Match confidence levels
+const (
+	ConfidenceExact Confidence = "exact" // The requested tag is in the catalog
+	ConfidenceHigh  Confidence = "high"  // Same language and script, different region
+	ConfidenceLow   Confidence = "low"   // Same language, different script
+	ConfidenceNone  Confidence = "none"  // Nothing matched; a fallback language was used
+)
+
+// Match is the result of negotiating a requested language against a catalog
+type Match struct {
+	Tag        string
+	Confidence Confidence
+}
+
+// localeEnvVars lists the locale environment variables in POSIX precedence order
+var localeEnvVars = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// ParseTag parses a BCP 47 language tag, also accepting POSIX locale names
+// such as pt_BR.UTF-8 or de_DE@euro
+func ParseTag(s string) (language.Tag, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, ".@"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.ReplaceAll(s, "_", "-")
+
+	if s == "" || s == "C" || s == "POSIX" {
+		return language.Und, fmt.Errorf("no language in locale %q", s)
+	}
+
+	tag, err := language.Parse(s)
+	if err != nil {
+		return language.Und, fmt.Errorf("invalid language tag %q: %w", s, err)
+	}
+	return tag, nil
+}
+
+// DetectLanguage returns the language tag from the locale environment
+// variables, or an empty string if none is set to a real language
+func DetectLanguage(getenv func(string) string) string {
+	for _, key := range localeEnvVars {
+		value := getenv(key)
+		if value == "" {
+			continue
+		}
+		// The first variable that is set wins, even if it names the C locale
+		tag, err := ParseTag(value)
+		if err != nil {
+			return ""
+		}
+		return tag.String()
+	}
+	return ""
+}
+
+// Match negotiates the requested language against the catalog. If neither
+// the request nor any fallback matches, the default language is used.
+func (c *Catalog) Match(requested string, fallbacks []string) Match {
+	if requested != "" {
+		if m, ok := c.matchTag(requested); ok {
+			return m
+		}
+	}
+
+	for _, fallback := range fallbacks {
+		if m, ok := c.matchTag(fallback); ok {
+			return Match{Tag: m.Tag, Confidence: ConfidenceNone}
+		}
+	}
+
+	return Match{Tag: defaultLanguage, Confidence: ConfidenceNone}
+}
+
+// Preferred returns the first requested tag that the catalog can serve,
+// for choosing among an ordered list such as an Accept-Language header.
+// It returns an empty string if none of them match.
+func (c *Catalog) Preferred(requested []string) string {
+	for _, tag := range requested {
+		if _, ok := c.matchTag(tag); ok {
+			return tag
+		}
+	}
+	return ""
+}
+
+// matchTag walks the fallback chain of a single tag
+func (c *Catalog) matchTag(requested string) (Match, bool) {
+	tag, err := ParseTag(requested)
+	if err != nil {
+		return Match{}, false
+	}
+
+	for _, candidate := range fallbackChain(tag) {
+		if _, ok := c.translations[candidate.Tag]; ok {
+			return candidate, true
+		}
+	}
+	return Match{}, false
+}
+
+// fallbackChain lists the tags to try for a request, most specific first.
+// For zh-Hant this yields zh-Hant, zh-Hant-TW, zh-TW and finally zh, with
+// the last one marked low confidence because zh implies Simplified script.
+func fallbackChain(tag language.Tag) []Match {
+	base, _ := tag.Base()
+	script, _ := tag.Script()
+	region, _ := tag.Region()
+
+	candidates := []Match{
+		{Tag: tag.String(), Confidence: ConfidenceExact},
+		{Tag: join(base.String(), script.String(), region.String()), Confidence: ConfidenceHigh},
+		{Tag: join(base.String(), region.String()), Confidence: scriptConfidence(join(base.String(), region.String()), script)},
+		{Tag: join(base.String(), script.String()), Confidence: ConfidenceHigh},
+		{Tag: base.String(), Confidence: scriptConfidence(base.String(), script)},
+	}
+
+	chain := make([]Match, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		candidate.Tag = language.Make(candidate.Tag).String()
+		if seen[candidate.Tag] {
+			continue
+		}
+		seen[candidate.Tag] = true
+		chain = append(chain, candidate)
+	}
+	return chain
+}
+
+// scriptConfidence rates a candidate by whether its likely script matches
+func scriptConfidence(candidate string, script language.Script) Confidence {
+	if likely, _ := language.Make(candidate).Script(); likely == script {
+		return ConfidenceHigh
+	}
+	return ConfidenceLow
+}
+
+func join(subtags ...string) string {
+	return strings.Join(subtags, "-")
+}
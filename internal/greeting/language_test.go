@@ -0,0 +1,149 @@
+package greeting
+
+import (
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "es", want: "es"},
+		{input: "es-MX", want: "es-MX"},
+		{input: "pt_BR.UTF-8", want: "pt-BR"},
+		{input: "de_DE@euro", want: "de-DE"},
+		{input: "zh-hant-tw", want: "zh-Hant-TW"},
+		{input: "C", wantErr: true},
+		{input: "POSIX", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "not a tag", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tag, err := ParseTag(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTag(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && tag.String() != tt.want {
+				t.Errorf("ParseTag(%q) = %v, want %v", tt.input, tag, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "nothing set", env: map[string]string{}, want: ""},
+		{name: "LANG", env: map[string]string{"LANG": "fr_FR.UTF-8"}, want: "fr-FR"},
+		{
+			name: "LC_MESSAGES overrides LANG",
+			env:  map[string]string{"LANG": "fr_FR.UTF-8", "LC_MESSAGES": "de_DE.UTF-8"},
+			want: "de-DE",
+		},
+		{
+			name: "LC_ALL overrides everything",
+			env:  map[string]string{"LANG": "fr_FR", "LC_MESSAGES": "de_DE", "LC_ALL": "ja_JP.UTF-8"},
+			want: "ja-JP",
+		},
+		{name: "C locale", env: map[string]string{"LC_ALL": "C.UTF-8", "LANG": "fr_FR"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectLanguage(func(key string) string { return tt.env[key] })
+			if got != tt.want {
+				t.Errorf("DetectLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogMatch(t *testing.T) {
+	dir := t.TempDir()
+	writeLocale(t, dir, "zh-TW.yaml", "template: \"你好，{name}！\"\nhello: \"你好，世界！\"\n")
+	writeLocale(t, dir, "pt_BR.yaml", "template: \"Olá, {name}!\"\nhello: \"Olá, Mundo!\"\n")
+	catalog, err := LoadCatalog(dir)
+	if err != nil {
+		t.Fatalf("LoadCatalog() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		requested string
+		fallbacks []string
+		want      Match
+	}{
+		{name: "exact", requested: "fr", want: Match{Tag: "fr", Confidence: ConfidenceExact}},
+		{name: "region stripped", requested: "es-MX", want: Match{Tag: "es", Confidence: ConfidenceHigh}},
+		{name: "posix locale", requested: "de_AT.UTF-8", want: Match{Tag: "de", Confidence: ConfidenceHigh}},
+		{name: "script resolves to region", requested: "zh-Hant", want: Match{Tag: "zh-TW", Confidence: ConfidenceHigh}},
+		{name: "simplified script", requested: "zh-Hans-SG", want: Match{Tag: "zh", Confidence: ConfidenceHigh}},
+		{name: "region file name normalized", requested: "pt-BR", want: Match{Tag: "pt-BR", Confidence: ConfidenceExact}},
+		{name: "unsupported uses fallback list", requested: "gl", fallbacks: []string{"it", "es"}, want: Match{Tag: "es", Confidence: ConfidenceNone}},
+		{name: "unsupported without fallback", requested: "gl", want: Match{Tag: "en", Confidence: ConfidenceNone}},
+		{name: "invalid tag", requested: "unknown", want: Match{Tag: "en", Confidence: ConfidenceNone}},
+		{name: "empty request", requested: "", want: Match{Tag: "en", Confidence: ConfidenceNone}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := catalog.Match(tt.requested, tt.fallbacks)
+			if got != tt.want {
+				t.Errorf("Match(%q, %v) = %+v, want %+v", tt.requested, tt.fallbacks, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallbackChainScriptMismatch(t *testing.T) {
+	tag, err := ParseTag("zh-Hant")
+	if err != nil {
+		t.Fatalf("ParseTag() unexpected error: %v", err)
+	}
+
+	chain := fallbackChain(tag)
+	want := []Match{
+		{Tag: "zh-Hant", Confidence: ConfidenceExact},
+		{Tag: "zh-Hant-TW", Confidence: ConfidenceHigh},
+		{Tag: "zh-TW", Confidence: ConfidenceHigh},
+		{Tag: "zh", Confidence: ConfidenceLow},
+	}
+	if len(chain) != len(want) {
+		t.Fatalf("fallbackChain() = %+v, want %+v", chain, want)
+	}
+	for i := range want {
+		if chain[i] != want[i] {
+			t.Errorf("fallbackChain()[%d] = %+v, want %+v", i, chain[i], want[i])
+		}
+	}
+}
+
+func TestCatalogPreferred(t *testing.T) {
+	catalog := DefaultCatalog()
+
+	tests := []struct {
+		name      string
+		requested []string
+		want      string
+	}{
+		{name: "first match wins", requested: []string{"gl", "es-MX", "fr"}, want: "es-MX"},
+		{name: "invalid tags are skipped", requested: []string{"not a tag", "de"}, want: "de"},
+		{name: "no match", requested: []string{"gl", "eu"}, want: ""},
+		{name: "empty", requested: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := catalog.Preferred(tt.requested); got != tt.want {
+				t.Errorf("Preferred(%v) = %q, want %q", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
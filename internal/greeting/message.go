@@ -0,0 +1,462 @@
+package greeting
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// Message is a compiled ICU MessageFormat-style message.
+//
+// The supported syntax is a subset of ICU MessageFormat:
+//
+//	{name}                                         variable
+//	{count, plural, =0{...} one{...} other{...}}   CLDR cardinal plural rules
+//	{count, plural, offset:1 one{...} other{...}}  plural with an offset
+//	{count, selectordinal, one{#st} other{#th}}    CLDR ordinal plural rules
+//	{gender, select, female{...} other{...}}       select on a string
+//	#                                              the plural number, inside plural cases
+//	'{literal}' and ''                             quoting and a literal apostrophe
+//
+// Every plural and select must have an other case.
+type Message struct {
+	parts []part
+}
+
+// Args holds the values substituted into a message
+type Args map[string]any
+
+// part is one piece of a compiled message
+type part interface {
+	format(b *strings.Builder, s *formatState) error
+}
+
+// formatState carries the arguments and innermost plural number while
+// formatting
+type formatState struct {
+	tag    language.Tag
+	args   Args
+	number *int
+}
+
+type textPart string
+
+func (p textPart) format(b *strings.Builder, _ *formatState) error {
+	b.WriteString(string(p))
+	return nil
+}
+
+// argPart substitutes a variable
+type argPart struct {
+	name string
+}
+
+func (p argPart) format(b *strings.Builder, s *formatState) error {
+	value, ok := s.args[p.name]
+	if !ok {
+		return fmt.Errorf("missing argument %q", p.name)
+	}
+	fmt.Fprint(b, value)
+	return nil
+}
+
+// numberPart is # inside a plural case
+type numberPart struct{}
+
+func (numberPart) format(b *strings.Builder, s *formatState) error {
+	b.WriteString(strconv.Itoa(*s.number))
+	return nil
+}
+
+// pluralPart chooses a case by exact value or CLDR plural category
+type pluralPart struct {
+	name    string
+	offset  int
+	ordinal bool
+	cases   map[string][]part
+}
+
+func (p pluralPart) format(b *strings.Builder, s *formatState) error {
+	value, ok := s.args[p.name]
+	if !ok {
+		return fmt.Errorf("missing argument %q", p.name)
+	}
+	n, ok := toInt(value)
+	if !ok {
+		return fmt.Errorf("argument %q must be an integer for plural, got %T", p.name, value)
+	}
+
+	parts, ok := p.cases["="+strconv.Itoa(n)]
+	if !ok {
+		rules := plural.Cardinal
+		if p.ordinal {
+			rules = plural.Ordinal
+		}
+		parts, ok = p.cases[pluralCategory(rules, s.tag, n-p.offset)]
+	}
+	if !ok {
+		parts = p.cases["other"]
+	}
+
+	number := n - p.offset
+	inner := &formatState{tag: s.tag, args: s.args, number: &number}
+	return formatParts(b, parts, inner)
+}
+
+// selectPart chooses a case by string value
+type selectPart struct {
+	name  string
+	cases map[string][]part
+}
+
+func (p selectPart) format(b *strings.Builder, s *formatState) error {
+	value, ok := s.args[p.name]
+	if !ok {
+		return fmt.Errorf("missing argument %q", p.name)
+	}
+	parts, ok := p.cases[fmt.Sprint(value)]
+	if !ok {
+		parts = p.cases["other"]
+	}
+	return formatParts(b, parts, s)
+}
+
+// ParseMessage compiles a message
+func ParseMessage(src string) (*Message, error) {
+	p := &messageParser{src: []rune(src)}
+	parts, err := p.parseParts(false, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected '}'")
+	}
+	return &Message{parts: parts}, nil
+}
+
+// Format renders the message for a language with the given arguments
+func (m *Message) Format(tag language.Tag, args Args) (string, error) {
+	var b strings.Builder
+	if err := formatParts(&b, m.parts, &formatState{tag: tag, args: args}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Variables returns the names of the variables used anywhere in the
+// message, sorted
+func (m *Message) Variables() []string {
+	seen := make(map[string]bool)
+	collectVariables(m.parts, seen)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func collectVariables(parts []part, seen map[string]bool) {
+	for _, p := range parts {
+		switch p := p.(type) {
+		case argPart:
+			seen[p.name] = true
+		case pluralPart:
+			seen[p.name] = true
+			for _, c := range p.cases {
+				collectVariables(c, seen)
+			}
+		case selectPart:
+			seen[p.name] = true
+			for _, c := range p.cases {
+				collectVariables(c, seen)
+			}
+		}
+	}
+}
+
+func formatParts(b *strings.Builder, parts []part, s *formatState) error {
+	for _, p := range parts {
+		if err := p.format(b, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pluralCategory returns the CLDR plural category keyword for an integer
+func pluralCategory(rules *plural.Rules, tag language.Tag, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch rules.MatchPlural(tag, n, 0, 0, 0, 0) {
+	case plural.Zero:
+		return "zero"
+	case plural.One:
+		return "one"
+	case plural.Two:
+		return "two"
+	case plural.Few:
+		return "few"
+	case plural.Many:
+		return "many"
+	default:
+		return "other"
+	}
+}
+
+// toInt converts integer argument values
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint:
+		return int(n), true //nolint:gosec // Counts are small
+	default:
+		return 0, false
+	}
+}
+
+// messageParser is a recursive descent parser for messages
+type messageParser struct {
+	src []rune
+	pos int
+}
+
+func (p *messageParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("message offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// parseParts reads text and arguments until an unmatched '}' or the end.
+// nested reports whether the parts are a plural or select case, and
+// inPlural whether # stands for the plural number.
+func (p *messageParser) parseParts(nested, inPlural bool) ([]part, error) {
+	var parts []part
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, textPart(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		switch {
+		case r == '\'':
+			p.parseQuote(&text, inPlural)
+		case r == '{':
+			flush()
+			arg, err := p.parseArgument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, arg)
+		case r == '}':
+			if !nested {
+				return nil, p.errorf("unexpected '}'")
+			}
+			flush()
+			return parts, nil
+		case r == '#' && inPlural:
+			flush()
+			parts = append(parts, numberPart{})
+			p.pos++
+		default:
+			text.WriteRune(r)
+			p.pos++
+		}
+	}
+
+	if nested {
+		return nil, p.errorf("unterminated case, missing '}'")
+	}
+	flush()
+	return parts, nil
+}
+
+// parseQuote handles an apostrophe. Two apostrophes in a row are a literal
+// apostrophe; an apostrophe before a syntax character starts a quoted
+// literal up to the next single apostrophe; any other apostrophe is
+// literal, so text like "l'ami" needs no escaping.
+func (p *messageParser) parseQuote(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos >= len(p.src) {
+		text.WriteRune('\'')
+		return
+	}
+
+	switch next := p.src[p.pos]; {
+	case next == '\'':
+		text.WriteRune('\'')
+		p.pos++
+		return
+	case next == '{' || next == '}' || (next == '#' && inPlural):
+	default:
+		text.WriteRune('\'')
+		return
+	}
+
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		p.pos++
+		if r != '\'' {
+			text.WriteRune(r)
+			continue
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+			text.WriteRune('\'')
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+// parseArgument reads {name}, {name, plural, ...}, {name, selectordinal, ...}
+// or {name, select, ...}; the opening brace is at the current position
+func (p *messageParser) parseArgument(inPlural bool) (part, error) {
+	p.pos++ // {
+	p.skipSpace()
+	name := p.parseIdentifier()
+	if name == "" {
+		return nil, p.errorf("expected argument name")
+	}
+	p.skipSpace()
+
+	if p.consume('}') {
+		return argPart{name: name}, nil
+	}
+	if !p.consume(',') {
+		return nil, p.errorf("expected ',' or '}' after argument %q", name)
+	}
+
+	p.skipSpace()
+	kind := p.parseIdentifier()
+	p.skipSpace()
+	if !p.consume(',') {
+		return nil, p.errorf("expected ',' after %s", kind)
+	}
+
+	switch kind {
+	case "plural", "selectordinal":
+		offset, cases, err := p.parseCases(true)
+		if err != nil {
+			return nil, err
+		}
+		return pluralPart{name: name, offset: offset, ordinal: kind == "selectordinal", cases: cases}, nil
+	case "select":
+		_, cases, err := p.parseCases(inPlural)
+		if err != nil {
+			return nil, err
+		}
+		return selectPart{name: name, cases: cases}, nil
+	default:
+		return nil, p.errorf("unsupported argument type %q", kind)
+	}
+}
+
+// parseCases reads the cases of a plural or select up to and including
+// the closing brace of the argument
+func (p *messageParser) parseCases(inPlural bool) (int, map[string][]part, error) {
+	offset := 0
+	cases := make(map[string][]part)
+
+	p.skipSpace()
+	if inPlural && strings.HasPrefix(string(p.src[p.pos:]), "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		n, err := strconv.Atoi(string(p.src[start:p.pos]))
+		if err != nil {
+			return 0, nil, p.errorf("invalid plural offset")
+		}
+		offset = n
+	}
+
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			break
+		}
+
+		key := p.parseSelector()
+		if key == "" {
+			return 0, nil, p.errorf("expected case selector")
+		}
+		if _, dup := cases[key]; dup {
+			return 0, nil, p.errorf("duplicate case %q", key)
+		}
+
+		p.skipSpace()
+		if !p.consume('{') {
+			return 0, nil, p.errorf("expected '{' after case %q", key)
+		}
+		parts, err := p.parseParts(true, inPlural)
+		if err != nil {
+			return 0, nil, err
+		}
+		p.pos++ // }
+		cases[key] = parts
+	}
+
+	if _, ok := cases["other"]; !ok {
+		return 0, nil, p.errorf("missing other case")
+	}
+	return offset, cases, nil
+}
+
+// parseSelector reads a case keyword or an exact value such as =0
+func (p *messageParser) parseSelector() string {
+	if p.pos < len(p.src) && p.src[p.pos] == '=' {
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		if p.pos == start+1 {
+			return ""
+		}
+		return string(p.src[start:p.pos])
+	}
+	return p.parseIdentifier()
+}
+
+func (p *messageParser) parseIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		if r != '_' && r != '-' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *messageParser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\n\r", p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *messageParser) consume(r rune) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == r {
+		p.pos++
+		return true
+	}
+	return false
+}
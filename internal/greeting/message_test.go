@@ -0,0 +1,177 @@
+package greeting
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestMessageFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		lang string
+		args Args
+		want string
+	}{
+		{name: "plain text", src: "Hello, World!", want: "Hello, World!"},
+		{name: "variable", src: "Hello, {name}!", args: Args{"name": "Ana"}, want: "Hello, Ana!"},
+		{name: "reordered variables", src: "{b} then {a}", args: Args{"a": 1, "b": 2}, want: "2 then 1"},
+		{
+			name: "english plural one",
+			src:  "{count, plural, one{# person} other{# people}}",
+			args: Args{"count": 1},
+			want: "1 person",
+		},
+		{
+			name: "english plural other",
+			src:  "{count, plural, one{# person} other{# people}}",
+			args: Args{"count": 0},
+			want: "0 people",
+		},
+		{
+			name: "exact match wins over category",
+			src:  "{count, plural, =1{just you} one{# person} other{# people}}",
+			args: Args{"count": 1},
+			want: "just you",
+		},
+		{
+			name: "french treats zero as one",
+			src:  "{count, plural, one{# personne} other{# personnes}}",
+			lang: "fr",
+			args: Args{"count": 0},
+			want: "0 personne",
+		},
+		{
+			name: "russian few",
+			src:  "{count, plural, one{# гость} few{# гостя} many{# гостей} other{# гостя}}",
+			lang: "ru",
+			args: Args{"count": 22},
+			want: "22 гостя",
+		},
+		{
+			name: "russian many",
+			src:  "{count, plural, one{# гость} few{# гостя} many{# гостей} other{# гостя}}",
+			lang: "ru",
+			args: Args{"count": 11},
+			want: "11 гостей",
+		},
+		{
+			name: "offset",
+			src:  "{count, plural, offset:1 =0{nobody} =1{{name}} one{{name} and # other} other{{name} and # others}}",
+			args: Args{"count": 3, "name": "Ana"},
+			want: "Ana and 2 others",
+		},
+		{
+			name: "selectordinal",
+			src:  "{n, selectordinal, one{#st} two{#nd} few{#rd} other{#th}}",
+			args: Args{"n": 22},
+			want: "22nd",
+		},
+		{
+			name: "select",
+			src:  "{gender, select, female{Bienvenida} male{Bienvenido} other{Bienvenide}}",
+			args: Args{"gender": "female"},
+			want: "Bienvenida",
+		},
+		{
+			name: "select falls back to other",
+			src:  "{gender, select, female{Bienvenida} other{Hola}}",
+			args: Args{"gender": "unknown"},
+			want: "Hola",
+		},
+		{
+			name: "select nested in plural keeps #",
+			src:  "{count, plural, one{hi} other{{gender, select, female{todas las #} other{todos los #}}}}",
+			args: Args{"count": 3, "gender": "female"},
+			want: "todas las 3",
+		},
+		{name: "apostrophe in text", src: "l'ami {name}", args: Args{"name": "Jo"}, want: "l'ami Jo"},
+		{name: "doubled apostrophe", src: "it''s", want: "it's"},
+		{name: "quoted braces", src: "'{name}' is {name}", args: Args{"name": "Jo"}, want: "{name} is Jo"},
+		{name: "hash outside plural", src: "# {name}", args: Args{"name": "Jo"}, want: "# Jo"},
+		{
+			name: "quoted hash inside plural",
+			src:  "{count, plural, other{'#'#}}",
+			args: Args{"count": 5},
+			want: "#5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseMessage(tt.src)
+			if err != nil {
+				t.Fatalf("ParseMessage(%q) unexpected error: %v", tt.src, err)
+			}
+
+			lang := tt.lang
+			if lang == "" {
+				lang = "en"
+			}
+			got, err := msg.Format(language.MustParse(lang), tt.args)
+			if err != nil {
+				t.Fatalf("Format() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMessageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "unclosed argument", src: "Hello, {name"},
+		{name: "unexpected close", src: "Hello}"},
+		{name: "empty argument", src: "{}"},
+		{name: "unknown type", src: "{n, number}"},
+		{name: "missing other", src: "{n, plural, one{x}}"},
+		{name: "duplicate case", src: "{n, plural, one{x} one{y} other{z}}"},
+		{name: "unterminated case", src: "{n, plural, other{x"},
+		{name: "bad selector", src: "{n, plural, ={x} other{y}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMessage(tt.src); err == nil {
+				t.Errorf("ParseMessage(%q) expected error", tt.src)
+			}
+		})
+	}
+}
+
+func TestMessageFormatErrors(t *testing.T) {
+	msg, err := ParseMessage("{count, plural, other{{name}}}")
+	if err != nil {
+		t.Fatalf("ParseMessage() unexpected error: %v", err)
+	}
+
+	if _, err := msg.Format(language.English, Args{"name": "Ana"}); err == nil {
+		t.Error("Format() expected error for missing count")
+	}
+	if _, err := msg.Format(language.English, Args{"count": "three", "name": "Ana"}); err == nil {
+		t.Error("Format() expected error for non-integer count")
+	}
+}
+
+func TestMessageVariables(t *testing.T) {
+	msg, err := ParseMessage("{count, plural, one{{name}} other{{gender, select, other{{names}}}}}")
+	if err != nil {
+		t.Fatalf("ParseMessage() unexpected error: %v", err)
+	}
+
+	got := msg.Variables()
+	want := []string{"count", "gender", "name", "names"}
+	if len(got) != len(want) {
+		t.Fatalf("Variables() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Variables() = %v, want %v", got, want)
+		}
+	}
+}
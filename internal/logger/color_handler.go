@@ -1,11 +1,13 @@
 package logger
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 )
@@ -23,13 +25,15 @@ const (
 	colorWhite  = "\033[97m"
 )
 
-// ColorHandler is a custom handler that adds colors to log output
+// ColorHandler is a custom handler that adds colors to log output.
+// Attributes within groups are written with dotted keys such as
+// http.request.method=GET, as slog.TextHandler does.
 type ColorHandler struct {
 	opts   slog.HandlerOptions
 	out    io.Writer
 	mu     *sync.Mutex
-	groups []string
-	attrs  []slog.Attr
+	groups []string // groups opened with WithGroup
+	attrs  []byte   // attributes added with WithAttrs, already formatted
 }
 
 // NewColorHandler creates a new ColorHandler
@@ -46,66 +50,65 @@ func NewColorHandler(out io.Writer, opts *slog.HandlerOptions) *ColorHandler {
 
 // Enabled reports whether the handler handles records at the given level
 func (h *ColorHandler) Enabled(_ context.Context, level slog.Level) bool {
-	return level >= h.opts.Level.Level()
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
 }
 
 // Handle handles the Record
 //
 //nolint:gocritic // slog.Handler interface requires value receiver
 func (h *ColorHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	h.writeHeader(&buf, &r)
+	h.writeMessage(&buf, &r)
+	h.writeSource(&buf, &r)
+	h.writeAttributes(&buf, &r)
+	buf.WriteString("\n")
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
-
-	if err := h.writeHeader(&r); err != nil {
-		return err
-	}
-
-	if err := h.writeMessage(&r); err != nil {
-		return err
-	}
-
-	if err := h.writeSource(&r); err != nil {
-		return err
-	}
-
-	if err := h.writeAttributes(&r); err != nil {
-		return err
-	}
-
-	_, err := fmt.Fprintln(h.out)
+	_, err := h.out.Write(buf.Bytes())
 	return err
 }
 
-// writeHeader writes the timestamp and log level
-func (h *ColorHandler) writeHeader(r *slog.Record) error {
-	timeStr := r.Time.Format("15:04:05")
-	levelColor, levelText := h.levelColorAndText(r.Level)
+// writeHeader writes the timestamp, unless the record has none, and the
+// log level
+func (h *ColorHandler) writeHeader(buf *bytes.Buffer, r *slog.Record) {
+	if !r.Time.IsZero() {
+		if attr, ok := h.replaceBuiltin(slog.Time(slog.TimeKey, r.Time)); ok {
+			timeStr := attr.Value.String()
+			if attr.Value.Kind() == slog.KindTime {
+				timeStr = attr.Value.Time().Format("15:04:05")
+			}
+			fmt.Fprintf(buf, "%s%s%s ", colorGray, timeStr, colorReset)
+		}
+	}
 
-	_, err := fmt.Fprintf(h.out, "%s%s%s %s%-5s%s ",
-		colorGray, timeStr, colorReset,
-		levelColor, levelText, colorReset)
-	return err
+	if attr, ok := h.replaceBuiltin(slog.Any(slog.LevelKey, r.Level)); ok {
+		levelColor, levelText := colorWhite, attr.Value.String()
+		if level, isLevel := attr.Value.Any().(slog.Level); isLevel {
+			levelColor, levelText = h.levelColorAndText(level)
+		}
+		fmt.Fprintf(buf, "%s%-5s%s ", levelColor, levelText, colorReset)
+	}
 }
 
 // writeMessage writes the log message
-func (h *ColorHandler) writeMessage(r *slog.Record) error {
-	_, err := fmt.Fprintf(h.out, "%s", r.Message)
-	return err
+func (h *ColorHandler) writeMessage(buf *bytes.Buffer, r *slog.Record) {
+	if attr, ok := h.replaceBuiltin(slog.String(slog.MessageKey, r.Message)); ok {
+		buf.WriteString(attr.Value.String())
+	}
 }
 
 // writeSource writes source information if enabled
-func (h *ColorHandler) writeSource(r *slog.Record) error {
+func (h *ColorHandler) writeSource(buf *bytes.Buffer, r *slog.Record) {
 	if !h.opts.AddSource {
-		return nil
+		return
 	}
-
-	sourceStr := h.getSourceString(r)
-	if sourceStr == "" {
-		return nil
-	}
-
-	_, err := fmt.Fprintf(h.out, "%s", sourceStr)
-	return err
+	buf.WriteString(h.getSourceString(r))
 }
 
 // getSourceString formats source information
@@ -116,71 +119,77 @@ func (h *ColorHandler) getSourceString(r *slog.Record) string {
 		return ""
 	}
 
-	if h.opts.ReplaceAttr == nil {
-		return fmt.Sprintf(" %s%s:%d%s", colorGray, f.File, f.Line, colorReset)
-	}
-
 	source := &slog.Source{
 		Function: f.Function,
 		File:     f.File,
 		Line:     f.Line,
 	}
-	attr := slog.Attr{
-		Key:   slog.SourceKey,
-		Value: slog.AnyValue(source),
+	attr, ok := h.replaceBuiltin(slog.Any(slog.SourceKey, source))
+	if !ok {
+		return ""
 	}
-	attr = h.opts.ReplaceAttr(nil, attr)
-	if source, ok := attr.Value.Any().(*slog.Source); ok && source.File != "" {
+	if source, isSource := attr.Value.Any().(*slog.Source); isSource {
+		if source.File == "" {
+			return ""
+		}
 		return fmt.Sprintf(" %s%s:%d%s", colorGray, source.File, source.Line, colorReset)
 	}
-	return ""
+	return fmt.Sprintf(" %s%s%s", colorGray, attr.Value.String(), colorReset)
 }
 
-// writeAttributes writes all attributes
-func (h *ColorHandler) writeAttributes(r *slog.Record) error {
-	if len(h.attrs) == 0 && r.NumAttrs() == 0 {
-		return nil
-	}
-
-	if _, err := fmt.Fprintf(h.out, " %s", colorGray); err != nil {
-		return err
-	}
-
-	// Write handler's attributes
-	for _, attr := range h.attrs {
-		if err := h.writeAttr(attr); err != nil {
-			return err
-		}
+// replaceBuiltin passes a built-in attribute through ReplaceAttr,
+// returning false if it was removed
+func (h *ColorHandler) replaceBuiltin(attr slog.Attr) (slog.Attr, bool) {
+	if h.opts.ReplaceAttr == nil {
+		return attr, true
 	}
+	attr = h.opts.ReplaceAttr(nil, attr)
+	attr.Value = attr.Value.Resolve()
+	return attr, !attr.Equal(slog.Attr{})
+}
 
-	// Write record's attributes
-	var attrErr error
+// writeAttributes writes the handler's and the record's attributes
+func (h *ColorHandler) writeAttributes(buf *bytes.Buffer, r *slog.Record) {
+	var attrs bytes.Buffer
+	attrs.Write(h.attrs)
+	prefix := groupPrefix(h.groups)
 	r.Attrs(func(attr slog.Attr) bool {
-		if err := h.writeAttr(attr); err != nil {
-			attrErr = err
-			return false
-		}
+		h.appendAttr(&attrs, prefix, h.groups, attr)
 		return true
 	})
-	if attrErr != nil {
-		return attrErr
+	if attrs.Len() == 0 {
+		return
 	}
 
-	_, err := fmt.Fprintf(h.out, "%s", colorReset)
-	return err
+	// Every attribute starts with its own separator
+	buf.WriteString(colorGray)
+	buf.Write(attrs.Bytes())
+	buf.WriteString(colorReset)
 }
 
 // WithAttrs returns a new Handler with additional attributes
 func (h *ColorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
 	h2 := *h
-	h2.attrs = append(h2.attrs, attrs...)
+	var buf bytes.Buffer
+	buf.Write(h.attrs)
+	prefix := groupPrefix(h.groups)
+	for _, attr := range attrs {
+		h.appendAttr(&buf, prefix, h.groups, attr)
+	}
+	h2.attrs = buf.Bytes()
 	return &h2
 }
 
 // WithGroup returns a new Handler with the given group name
 func (h *ColorHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
 	h2 := *h
-	h2.groups = append(h2.groups, name)
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
 	return &h2
 }
 
@@ -199,7 +208,42 @@ func (h *ColorHandler) levelColorAndText(level slog.Level) (color, text string)
 	}
 }
 
-func (h *ColorHandler) writeAttr(attr slog.Attr) error {
+// groupPrefix returns the dotted prefix of keys within groups
+func groupPrefix(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return strings.Join(groups, ".") + "."
+}
+
+// appendAttr writes an attribute within groups, whose keys are prefixed
+// with prefix. Values are resolved, empty attributes and groups are left
+// out, and the attributes of groups without a key are written inline.
+func (h *ColorHandler) appendAttr(buf *bytes.Buffer, prefix string, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		if len(members) == 0 {
+			return
+		}
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, member := range members {
+			h.appendAttr(buf, prefix, groups, member)
+		}
+		return
+	}
+
 	// Special handling for certain attribute keys
 	valueColor := colorWhite
 	if attr.Key == "error" {
@@ -207,18 +251,15 @@ func (h *ColorHandler) writeAttr(attr slog.Attr) error {
 	}
 
 	// Format based on value type
+	key := prefix + attr.Key
 	switch v := attr.Value.Any().(type) {
 	case string:
-		_, err := fmt.Fprintf(h.out, " %s=%s%q%s", attr.Key, valueColor, v, colorGray)
-		return err
+		fmt.Fprintf(buf, " %s=%s%q%s", key, valueColor, v, colorGray)
 	case time.Time:
-		_, err := fmt.Fprintf(h.out, " %s=%s%s%s", attr.Key, valueColor, v.Format(time.RFC3339), colorGray)
-		return err
+		fmt.Fprintf(buf, " %s=%s%s%s", key, valueColor, v.Format(time.RFC3339), colorGray)
 	case error:
-		_, err := fmt.Fprintf(h.out, " %s=%s%q%s", attr.Key, colorRed, v.Error(), colorGray)
-		return err
+		fmt.Fprintf(buf, " %s=%s%q%s", key, colorRed, v.Error(), colorGray)
 	default:
-		_, err := fmt.Fprintf(h.out, " %s=%s%v%s", attr.Key, valueColor, v, colorGray)
-		return err
+		fmt.Fprintf(buf, " %s=%s%v%s", key, valueColor, v, colorGray)
 	}
 }
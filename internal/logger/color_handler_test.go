@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+)
+
+// ansi matches the color codes written by ColorHandler
+var ansi = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// parseColorLine turns a line written by ColorHandler into the map
+// slogtest expects, with dotted keys as nested maps
+func parseColorLine(t *testing.T, line string) map[string]any {
+	t.Helper()
+	m := map[string]any{}
+	rest := ansi.ReplaceAllString(line, "")
+
+	field := func() string {
+		rest = strings.TrimLeft(rest, " ")
+		value, after, _ := strings.Cut(rest, " ")
+		rest = after
+		return value
+	}
+	if _, err := time.Parse("15:04:05", strings.SplitN(rest, " ", 2)[0]); err == nil {
+		m[slog.TimeKey] = field()
+	}
+	m[slog.LevelKey] = field()
+	m[slog.MessageKey] = field()
+
+	for rest = strings.TrimLeft(rest, " "); rest != ""; rest = strings.TrimLeft(rest, " ") {
+		key, after, ok := strings.Cut(rest, "=")
+		if !ok {
+			t.Fatalf("no value for %q in %q", rest, line)
+		}
+		rest = after
+		var value string
+		if quoted, err := strconv.QuotedPrefix(rest); err == nil {
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else {
+			value = field()
+		}
+
+		parts := strings.Split(key, ".")
+		group := m
+		for _, name := range parts[:len(parts)-1] {
+			sub, ok := group[name].(map[string]any)
+			if !ok {
+				sub = map[string]any{}
+				group[name] = sub
+			}
+			group = sub
+		}
+		group[parts[len(parts)-1]] = value
+	}
+	return m
+}
+
+func TestColorHandlerSlogtest(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewColorHandler(&buf, nil)
+
+	results := func() []map[string]any {
+		var ms []map[string]any
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			ms = append(ms, parseColorLine(t, line))
+		}
+		return ms
+	}
+	if err := slogtest.TestHandler(h, results); err != nil {
+		t.Error(err)
+	}
+}
+
+// token is a slog.LogValuer resolving to a group
+type token struct{ id, secret string }
+
+func (tk token) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", tk.id), slog.String("secret", tk.secret))
+}
+
+func TestColorHandlerGroups(t *testing.T) {
+	var buf bytes.Buffer
+	var seen []string
+	h := NewColorHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			seen = append(seen, strings.Join(append(groups, a.Key), "."))
+			if a.Key == "secret" {
+				a.Value = slog.StringValue("[REDACTED]")
+			}
+			return a
+		},
+	})
+
+	log := slog.New(h).With("app", "hello").WithGroup("http").With("id", 7)
+	log.Info("request",
+		slog.Group("request", "method", "GET", slog.Group("headers", "accept", "json")),
+		slog.Group("empty"),
+		slog.Group("", "inline", true),
+		"token", token{id: "t1", secret: "s3cr3t"},
+	)
+
+	got := ansi.ReplaceAllString(buf.String(), "")
+	want := `INFO  request app="hello" http.id=7 http.request.method="GET" http.request.headers.accept="json" http.inline=true http.token.id="t1" http.token.secret="[REDACTED]"` + "\n"
+	if got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+
+	wantSeen := []string{"app", "http.id", "level", "msg", "http.request.method", "http.request.headers.accept", "http.inline", "http.token.id", "http.token.secret"}
+	if strings.Join(seen, " ") != strings.Join(wantSeen, " ") {
+		t.Errorf("ReplaceAttr saw %v, want %v", seen, wantSeen)
+	}
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+type fieldsKey struct{}
+
+// WithFields returns a new context carrying fields, as key-value pairs or
+// slog.Attrs, that are added to every record logged with it: through the
+// XxxContext methods, or any method of a logger from FromContext. A field
+// replaces one of the same key already in ctx.
+func WithFields(ctx context.Context, fields ...any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(fields...)
+	added := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		added = append(added, a)
+		return true
+	})
+
+	attrs := slices.DeleteFunc(slices.Clone(ContextFields(ctx)), func(a slog.Attr) bool {
+		return slices.ContainsFunc(added, func(b slog.Attr) bool { return a.Key == b.Key })
+	})
+	return context.WithValue(ctx, fieldsKey{}, append(attrs, added...))
+}
+
+// ContextFields returns the fields added to ctx with WithFields
+func ContextFields(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	return attrs
+}
+
+type componentKey struct{}
+
+// WithComponent returns a new context in which FromContext returns loggers
+// of a component, replacing the one ctx names if any. Levels can set a
+// minimum level for each component.
+func WithComponent(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, componentKey{}, name)
+}
+
+// contextHandler adds the fields of the context records are logged with
+type contextHandler struct {
+	handler slog.Handler
+}
+
+// Enabled reports whether the wrapped handler handles records at level
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle adds the fields of ctx to r and passes it on
+//
+//nolint:gocritic // slog.Handler interface requires value receiver
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := ContextFields(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler adding attrs to the wrapped handler
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler opening a group on the wrapped handler
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{handler: h.handler.WithGroup(name)}
+}
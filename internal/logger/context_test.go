@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// decodeLines decodes the JSON log lines in buf
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("cannot decode %q: %v", line, err)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestWithFields(t *testing.T) {
+	ctx := WithFields(context.Background(), "run_id", "r1", "command", "app greet")
+	ctx = WithFields(ctx, "run_id", "r2", "trace_id", "t1")
+
+	var got []string
+	for _, a := range ContextFields(ctx) {
+		got = append(got, a.String())
+	}
+	want := "command=app greet run_id=r2 trace_id=t1"
+	if strings.Join(got, " ") != want {
+		t.Errorf("ContextFields() = %v, want %s", got, want)
+	}
+	if fields := ContextFields(context.Background()); fields != nil {
+		t.Errorf("ContextFields() of an empty context = %v", fields)
+	}
+}
+
+func TestContextLogging(t *testing.T) {
+	var buf bytes.Buffer
+	reloadable, _ := NewReloadable(Config{Level: "debug", Format: "json", Writer: &buf})
+
+	for name, log := range map[string]Logger{
+		"new":        New(Config{Level: "debug", Format: "json", Writer: &buf}),
+		"reloadable": reloadable,
+	} {
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+			ctx := WithContext(context.Background(), log.With("component", "test"))
+			ctx = WithFields(ctx, "run_id", "r1")
+
+			log.InfoContext(ctx, "explicit context")
+			FromContext(ctx).Warn("taken from the context")
+			log.Error("without a context")
+
+			lines := decodeLines(t, &buf)
+			if len(lines) != 3 {
+				t.Fatalf("logged %d lines, want 3", len(lines))
+			}
+			if lines[0]["run_id"] != "r1" || lines[1]["run_id"] != "r1" {
+				t.Errorf("lines = %v, want run_id=r1 in the first two", lines)
+			}
+			if lines[1]["component"] != "test" {
+				t.Errorf("line = %v, want the logger's fields", lines[1])
+			}
+			if _, ok := lines[2]["run_id"]; ok {
+				t.Errorf("line = %v, want no context fields", lines[2])
+			}
+			source, _ := lines[0]["source"].(map[string]any)
+			if file, _ := source["file"].(string); !strings.HasSuffix(file, "context_test.go") {
+				t.Errorf("source = %v, want the caller", lines[0]["source"])
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation controls when a log file is rotated and how many rotated files
+// are kept. The zero value never rotates.
+type Rotation struct {
+	MaxSize    int64         // Rotate before the file grows past this many bytes; 0 disables
+	Interval   time.Duration // Rotate on the first write of each interval, aligned to UTC; 0 disables
+	MaxAge     time.Duration // Remove rotated files older than this; 0 keeps them
+	MaxBackups int           // Number of rotated files to keep; 0 keeps all
+	Compress   bool          // Gzip rotated files
+}
+
+// backupTimeFormat is the timestamp added to rotated file names,
+// app-2006-01-02T15-04-05.000.log for app.log
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// File is a log file that rotates itself. Rotated files are renamed with
+// a UTC timestamp, then compressed and pruned in the background. It is
+// safe for concurrent use; the file is reopened by the next write after
+// Close.
+type File struct {
+	path string
+
+	mu       sync.Mutex
+	rotation Rotation
+	file     *os.File
+	size     int64
+	period   time.Time // Interval of the last write
+	now      func() time.Time
+	released bool // No longer an output; writes reopen the file only for themselves
+
+	millMu sync.Mutex // Serializes compressing and pruning
+	mills  sync.WaitGroup
+}
+
+// OpenFile opens the log file at path for appending, creating it and its
+// directory if needed
+func OpenFile(path string, rotation Rotation) (*File, error) {
+	f := &File{path: path, rotation: rotation, now: time.Now}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Path returns the path of the log file
+func (f *File) Path() string {
+	return f.path
+}
+
+// SetRotation replaces the rotation settings, applied from the next write
+func (f *File) SetRotation(rotation Rotation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rotation = rotation
+}
+
+// Write appends p to the file, rotating it first if p would take it past
+// MaxSize or starts a new Interval. A single write larger than MaxSize is
+// written whole.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+		if f.released {
+			defer f.closeFile()
+		}
+	}
+
+	now := f.now()
+	if f.size > 0 && f.due(now, int64(len(p))) {
+		if err := f.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if f.rotation.Interval > 0 {
+		f.period = now.Truncate(f.rotation.Interval)
+	}
+	return n, err
+}
+
+// Rotate renames the current file and starts a new one
+func (f *File) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+	return f.rotate(f.now())
+}
+
+// Close closes the file after rotated files are compressed and pruned
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.mills.Wait()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// closeFile closes the open file, if any; f.mu must be held
+func (f *File) closeFile() {
+	if f.file != nil {
+		_ = f.file.Close()
+		f.file = nil
+	}
+}
+
+// due reports whether writing n bytes at now needs a rotation first
+func (f *File) due(now time.Time, n int64) bool {
+	if f.rotation.MaxSize > 0 && f.size+n > f.rotation.MaxSize {
+		return true
+	}
+	return f.rotation.Interval > 0 && now.Truncate(f.rotation.Interval).After(f.period)
+}
+
+// open opens the file for appending. The interval of an existing file is
+// that of its last modification.
+func (f *File) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return fmt.Errorf("cannot create log directory: %w", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("cannot open log file: %w", err)
+	}
+
+	f.file, f.size = file, info.Size()
+	if f.rotation.Interval > 0 {
+		f.period = info.ModTime().Truncate(f.rotation.Interval)
+	}
+	return nil
+}
+
+// rotate renames the file to a backup, opens a new one and starts
+// compressing and pruning the backups
+func (f *File) rotate(now time.Time) error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("cannot close log file: %w", err)
+	}
+	f.file = nil
+
+	if err := os.Rename(f.path, f.backupName(now)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	rotation := f.rotation
+	f.mills.Add(1)
+	go func() {
+		defer f.mills.Done()
+		f.mill(rotation, now)
+	}()
+	return nil
+}
+
+// backupName returns an unused name for a file rotated at t
+func (f *File) backupName(t time.Time) string {
+	dir, prefix, ext := f.nameParts()
+	stamp := t.UTC().Format(backupTimeFormat)
+	name := filepath.Join(dir, prefix+stamp+ext)
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = filepath.Join(dir, prefix+stamp+"."+strconv.Itoa(i)+ext)
+	}
+	return name
+}
+
+// nameParts splits the file name around the backup timestamp
+func (f *File) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(f.path)
+	base := filepath.Base(f.path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// backup is a rotated log file
+type backup struct {
+	path    string
+	rotated time.Time
+	seq     int // Suffix of files rotated within the same millisecond
+}
+
+// backups returns the rotated files of f, newest first
+func (f *File) backups() ([]backup, error) {
+	dir, prefix, ext := f.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		stamp = strings.TrimSuffix(stamp, ext)
+		if len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+		rotated, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)])
+		if err != nil {
+			continue
+		}
+		seq := 0
+		if suffix := stamp[len(backupTimeFormat):]; suffix != "" {
+			if seq, err = strconv.Atoi(strings.TrimPrefix(suffix, ".")); err != nil || suffix[0] != '.' {
+				continue
+			}
+		}
+		found = append(found, backup{path: filepath.Join(dir, name), rotated: rotated, seq: seq})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].rotated.Equal(found[j].rotated) {
+			return found[i].seq > found[j].seq
+		}
+		return found[i].rotated.After(found[j].rotated)
+	})
+	return found, nil
+}
+
+// mill removes the backups beyond MaxBackups or older than MaxAge, then
+// compresses those left. Files left uncompressed by an earlier run are
+// compressed too.
+func (f *File) mill(rotation Rotation, now time.Time) {
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	backups, err := f.backups()
+	if err != nil {
+		return
+	}
+	for i, b := range backups {
+		tooMany := rotation.MaxBackups > 0 && i >= rotation.MaxBackups
+		tooOld := rotation.MaxAge > 0 && now.Sub(b.rotated) > rotation.MaxAge
+		if tooMany || tooOld {
+			_ = os.Remove(b.path)
+			continue
+		}
+		if rotation.Compress && !strings.HasSuffix(b.path, ".gz") {
+			_ = compress(b.path)
+		}
+	}
+}
+
+// compress gzips path to path.gz and removes path
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// OutputPath returns the file named by a log output: a path or a file://
+// URL. It returns "" for stdout and stderr.
+func OutputPath(output string) (string, error) {
+	switch output {
+	case "", "stdout", "stderr":
+		return "", nil
+	}
+	if !strings.HasPrefix(output, "file:") {
+		return output, nil
+	}
+
+	u, err := url.Parse(output)
+	if err != nil {
+		return "", fmt.Errorf("invalid file URL: %w", err)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("file URL %q names a remote host", output)
+	}
+	path := u.Path
+	if path == "" {
+		path = u.Opaque // file:relative/path
+	}
+	if path == "" {
+		return "", fmt.Errorf("file URL %q has no path", output)
+	}
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:] // file:///C:/logs/app.log
+	}
+	return filepath.FromSlash(path), nil
+}
+
+// ParseSize parses a size such as 512KB, 100MB or 1GB. Units are powers
+// of 1024; a number without a unit is in bytes.
+func ParseSize(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		scale  int64
+	}{
+		{"kib", 1 << 10}, {"mib", 1 << 20}, {"gib", 1 << 30},
+		{"kb", 1 << 10}, {"mb", 1 << 20}, {"gb", 1 << 30},
+		{"k", 1 << 10}, {"m", 1 << 20}, {"g", 1 << 30},
+		{"b", 1},
+	}
+
+	number, scale := strings.ToLower(strings.TrimSpace(s)), int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(number, unit.suffix) {
+			number, scale = strings.TrimSpace(strings.TrimSuffix(number, unit.suffix)), unit.scale
+			break
+		}
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 || n > (1<<62)/scale {
+		return 0, fmt.Errorf("invalid size %q, use a number of bytes or a unit such as 100MB", s)
+	}
+	return n * scale, nil
+}
+
+// ParseDuration parses a Go duration such as 12h, also accepting whole
+// days such as 7d
+func ParseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q, use a duration such as 12h or 7d", s)
+	}
+	return d, nil
+}
+
+// files are the log files opened for outputs, by path, so loggers writing
+// to the same file share it
+var (
+	filesMu sync.Mutex
+	files   = map[string]*File{}
+)
+
+// outputKey returns the key of the log file at path in files
+func outputKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// openOutput returns the shared log file at path with the given rotation
+func openOutput(path string, rotation Rotation) (*File, error) {
+	path = outputKey(path)
+
+	filesMu.Lock()
+	defer filesMu.Unlock()
+	if f, ok := files[path]; ok {
+		f.SetRotation(rotation)
+		return f, nil
+	}
+	f, err := OpenFile(path, rotation)
+	if err != nil {
+		return nil, err
+	}
+	files[path] = f
+	return f, nil
+}
+
+// releaseOutputs closes the log files at paths and forgets them, once no
+// logger writes to them. A record still being written to one reopens it
+// for that write only.
+func releaseOutputs(paths []string) {
+	filesMu.Lock()
+	defer filesMu.Unlock()
+	for _, path := range paths {
+		f, ok := files[path]
+		if !ok {
+			continue
+		}
+		delete(files, path)
+		f.mu.Lock()
+		f.released = true
+		f.mu.Unlock()
+		_ = f.Close()
+	}
+}
+
+// Close logs the pending summaries of sampling handlers, then closes the
+// log files opened for file outputs, waiting for rotated files to be
+// compressed. Later writes reopen them.
+func Close() error {
+	flushSamplers()
+
+	filesMu.Lock()
+	defer filesMu.Unlock()
+
+	var firstErr error
+	for _, f := range files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,297 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a time that tests move forward
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func openTestFile(t *testing.T, rotation Rotation) (*File, *fakeClock) {
+	t.Helper()
+	f, err := OpenFile(filepath.Join(t.TempDir(), "logs", "app.log"), rotation)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+
+	clock := &fakeClock{t: time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC)}
+	f.now = clock.now
+	return f, clock
+}
+
+func write(t *testing.T, f *File, s string) {
+	t.Helper()
+	if _, err := f.Write([]byte(s)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+}
+
+// logFiles returns the names in the directory of f, sorted
+func logFiles(t *testing.T, f *File) []string {
+	t.Helper()
+	f.mills.Wait()
+	entries, err := os.ReadDir(filepath.Dir(f.Path()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFileRotatesBySize(t *testing.T) {
+	f, clock := openTestFile(t, Rotation{MaxSize: 10})
+
+	write(t, f, "12345\n")
+	write(t, f, "6789\n") // 11 bytes: rotates first
+	clock.t = clock.t.Add(time.Second)
+	write(t, f, "abcdefghijklmnop\n") // larger than MaxSize: rotates, then written whole
+
+	want := []string{"app-2024-12-25T10-00-00.000.log", "app-2024-12-25T10-00-01.000.log", "app.log"}
+	if got := logFiles(t, f); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	dir := filepath.Dir(f.Path())
+	if got := readFile(t, filepath.Join(dir, want[0])); got != "12345\n" {
+		t.Errorf("first backup = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, want[1])); got != "6789\n" {
+		t.Errorf("second backup = %q", got)
+	}
+	if got := readFile(t, f.Path()); got != "abcdefghijklmnop\n" {
+		t.Errorf("current file = %q", got)
+	}
+}
+
+func TestFileRotatesByInterval(t *testing.T) {
+	f, clock := openTestFile(t, Rotation{Interval: 24 * time.Hour})
+
+	write(t, f, "morning\n")
+	clock.t = clock.t.Add(13 * time.Hour) // 23:00, same day
+	write(t, f, "night\n")
+	clock.t = clock.t.Add(2 * time.Hour) // 01:00 the next day
+	write(t, f, "next day\n")
+
+	if got := logFiles(t, f); len(got) != 2 {
+		t.Fatalf("files = %v, want one backup and the current file", got)
+	}
+	if got := readFile(t, f.Path()); got != "next day\n" {
+		t.Errorf("current file = %q, want only the next day", got)
+	}
+}
+
+func TestFilePrunesAndCompresses(t *testing.T) {
+	f, clock := openTestFile(t, Rotation{MaxSize: 1, MaxBackups: 2, MaxAge: 48 * time.Hour, Compress: true})
+
+	for _, line := range []string{"a\n", "b\n", "c\n", "d\n"} {
+		write(t, f, line)
+		clock.t = clock.t.Add(time.Hour)
+	}
+
+	want := []string{"app-2024-12-25T12-00-00.000.log.gz", "app-2024-12-25T13-00-00.000.log.gz", "app.log"}
+	got := logFiles(t, f)
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	gz, err := os.Open(filepath.Join(filepath.Dir(f.Path()), want[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gz.Close()
+	zr, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := io.ReadAll(zr); err != nil || string(data) != "c\n" {
+		t.Errorf("compressed backup = %q, %v, want %q", data, err, "c\n")
+	}
+
+	// Backups older than MaxAge go at the next rotation
+	clock.t = clock.t.Add(72 * time.Hour)
+	if err := f.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := logFiles(t, f); len(got) != 2 {
+		t.Errorf("files = %v, want only the newest backup and the current file", got)
+	}
+}
+
+func TestFileKeepsNewestBackups(t *testing.T) {
+	// Rotations within the same millisecond are numbered
+	f, _ := openTestFile(t, Rotation{MaxSize: 1, MaxBackups: 2})
+	for _, line := range []string{"a\n", "b\n", "c\n", "d\n"} {
+		write(t, f, line)
+	}
+
+	want := []string{"app-2024-12-25T10-00-00.000.1.log", "app-2024-12-25T10-00-00.000.2.log", "app.log"}
+	if got := logFiles(t, f); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := readFile(t, filepath.Join(filepath.Dir(f.Path()), want[1])); got != "c\n" {
+		t.Errorf("newest backup = %q, want %q", got, "c\n")
+	}
+}
+
+func TestFileConcurrentWrites(t *testing.T) {
+	f, _ := openTestFile(t, Rotation{MaxSize: 512})
+
+	const writers, lines = 8, 100
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				_, _ = f.Write([]byte("concurrent log line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for _, name := range logFiles(t, f) {
+		content := readFile(t, filepath.Join(filepath.Dir(f.Path()), name))
+		for _, line := range strings.SplitAfter(content, "\n") {
+			if line != "" && line != "concurrent log line\n" {
+				t.Fatalf("%s has interleaved line %q", name, line)
+			}
+		}
+		total += strings.Count(content, "\n")
+	}
+	if total != writers*lines {
+		t.Errorf("wrote %d lines, want %d", total, writers*lines)
+	}
+}
+
+func TestFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Cleanup(func() { _ = Close() })
+
+	log := New(Config{Level: "info", Format: "json", Output: "file://" + filepath.ToSlash(path)})
+	log.Info("to the file", "key", "value")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); !strings.Contains(got, `"msg":"to the file"`) {
+		t.Errorf("log file = %q", got)
+	}
+
+	// Loggers for the same path share the file, which reopens after Close
+	New(Config{Level: "info", Format: "json", Output: path}).Info("again")
+	if got := readFile(t, path); strings.Count(got, "\n") != 2 {
+		t.Errorf("log file = %q, want two lines", got)
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("paths below are Unix paths")
+	}
+	tests := []struct {
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{output: "stderr", want: ""},
+		{output: "stdout", want: ""},
+		{output: "/var/log/app.log", want: "/var/log/app.log"},
+		{output: "logs/app.log", want: "logs/app.log"},
+		{output: "file:///var/log/app.log", want: "/var/log/app.log"},
+		{output: "file://localhost/var/log/app.log", want: "/var/log/app.log"},
+		{output: "file:logs/app.log", want: "logs/app.log"},
+		{output: "file://server/app.log", wantErr: true},
+		{output: "file://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			got, err := OutputPath(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OutputPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("OutputPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "0", want: 0},
+		{input: "512", want: 512},
+		{input: "512KB", want: 512 << 10},
+		{input: "100MB", want: 100 << 20},
+		{input: "100 mb", want: 100 << 20},
+		{input: "1GiB", want: 1 << 30},
+		{input: "10m", want: 10 << 20},
+		{input: "big", wantErr: true},
+		{input: "-1MB", wantErr: true},
+		{input: "1.5GB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "24h", want: 24 * time.Hour},
+		{input: "90m", want: 90 * time.Minute},
+		{input: "7d", want: 7 * 24 * time.Hour},
+		{input: "a week", wantErr: true},
+		{input: "-1h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
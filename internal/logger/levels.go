@@ -0,0 +1,289 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ComponentKey is the attribute naming the part of the CLI a logger
+// belongs to, added with Logger.With. Levels can set a minimum level for
+// each component.
+const ComponentKey = "component"
+
+// Levels holds the minimum levels of a logger, which can change while it
+// runs: a base level, overrides for components, and a debug switch that
+// lowers both to debug until turned off
+type Levels struct {
+	mu         sync.Mutex // held while changing
+	base       slog.LevelVar
+	floor      slog.LevelVar // lowest level anything logs at
+	components atomic.Pointer[map[string]slog.Level]
+	debug      atomic.Bool
+}
+
+// NewLevels returns levels with a base level and overrides for components
+func NewLevels(level slog.Level, components map[string]slog.Level) *Levels {
+	l := &Levels{}
+	l.Configure(level, components)
+	return l
+}
+
+// Configure replaces the base level and every component override
+func (l *Levels) Configure(level slog.Level, components map[string]slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.base.Set(level)
+	components = maps.Clone(components)
+	l.components.Store(&components)
+	l.update()
+}
+
+// Level returns the base level
+func (l *Levels) Level() slog.Level {
+	return l.base.Level()
+}
+
+// SetLevel changes the base level
+func (l *Levels) SetLevel(level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.base.Set(level)
+	l.update()
+}
+
+// Components returns a copy of the component overrides
+func (l *Levels) Components() map[string]slog.Level {
+	return maps.Clone(*l.components.Load())
+}
+
+// SetComponent sets the level of a component, overriding the base level
+func (l *Levels) SetComponent(name string, level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	components := maps.Clone(*l.components.Load())
+	if components == nil {
+		components = make(map[string]slog.Level)
+	}
+	components[name] = level
+	l.components.Store(&components)
+	l.update()
+}
+
+// ResetComponent removes the override of a component, which then logs at
+// the base level
+func (l *Levels) ResetComponent(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	components := maps.Clone(*l.components.Load())
+	delete(components, name)
+	l.components.Store(&components)
+	l.update()
+}
+
+// Debugging reports whether debug logging is switched on
+func (l *Levels) Debugging() bool {
+	return l.debug.Load()
+}
+
+// SetDebug switches debug logging on or off. While on, every component
+// logs at debug; the levels set otherwise apply again once it is off.
+func (l *Levels) SetDebug(on bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debug.Store(on)
+	l.update()
+}
+
+// ToggleDebug switches debug logging on if it is off and off if it is on,
+// and reports whether it is now on
+func (l *Levels) ToggleDebug() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	on := !l.debug.Load()
+	l.debug.Store(on)
+	l.update()
+	return on
+}
+
+// For returns the minimum level of a component; "" is the base level
+func (l *Levels) For(component string) slog.Level {
+	if l.debug.Load() {
+		return slog.LevelDebug
+	}
+	if level, ok := (*l.components.Load())[component]; ok {
+		return level
+	}
+	return l.base.Level()
+}
+
+// String describes the base level and component overrides, such as
+// "info (cli=warn greeting=debug)", whether or not debug logging is on
+func (l *Levels) String() string {
+	components := l.Components()
+	if len(components) == 0 {
+		return LevelName(l.Level())
+	}
+	overrides := make([]string, 0, len(components))
+	for name, level := range components {
+		overrides = append(overrides, name+"="+LevelName(level))
+	}
+	sort.Strings(overrides)
+	return LevelName(l.Level()) + " (" + strings.Join(overrides, " ") + ")"
+}
+
+// update recomputes the floor; l.mu must be held
+func (l *Levels) update() {
+	floor := l.base.Level()
+	for _, level := range *l.components.Load() {
+		floor = min(floor, level)
+	}
+	if l.debug.Load() {
+		floor = min(floor, slog.LevelDebug)
+	}
+	l.floor.Set(floor)
+}
+
+// LevelName returns the name of a level as accepted by ParseLevel, such
+// as warn
+func LevelName(level slog.Level) string {
+	switch level {
+	case slog.LevelDebug:
+		return "debug"
+	case slog.LevelInfo:
+		return "info"
+	case slog.LevelWarn:
+		return "warn"
+	case slog.LevelError:
+		return "error"
+	default:
+		return level.String()
+	}
+}
+
+// parseComponentLevels parses the level of each component. Components
+// with an unknown level are left out; the returned error names them.
+func parseComponentLevels(levels map[string]string) (map[string]slog.Level, error) {
+	components := make(map[string]slog.Level, len(levels))
+	var errs []error
+	for name, value := range levels {
+		level, err := ParseLevel(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", name, err))
+			continue
+		}
+		components[name] = level
+	}
+	return components, errors.Join(errs...)
+}
+
+// configLevels returns the levels described by cfg, logging at info where
+// a level is unknown. The returned error says which.
+func configLevels(cfg Config) (slog.Level, map[string]slog.Level, error) {
+	level, err := ParseLevel(cfg.Level)
+	components, componentsErr := parseComponentLevels(cfg.Levels)
+	return level, components, errors.Join(err, componentsErr)
+}
+
+type levelsKey struct{}
+
+// WithLevels returns a new context carrying the levels of the logger, so
+// commands can change them while they run
+func WithLevels(ctx context.Context, levels *Levels) context.Context {
+	return context.WithValue(ctx, levelsKey{}, levels)
+}
+
+// LevelsFromContext returns the levels added to ctx with WithLevels, or
+// nil
+func LevelsFromContext(ctx context.Context) *Levels {
+	if ctx == nil {
+		return nil
+	}
+	levels, _ := ctx.Value(levelsKey{}).(*Levels)
+	return levels
+}
+
+// levelHandler drops records below the level Levels sets for its
+// component: the ComponentKey attribute added with WithAttrs outside of
+// any group. The wrapped handler must let records through at the floor
+// of the levels.
+type levelHandler struct {
+	handler   slog.Handler
+	levels    *Levels
+	component string
+	grouped   bool // a group is open, so attributes are not the component
+}
+
+// Enabled reports whether records at level are logged for the component
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.levels.For(h.component) && h.handler.Enabled(ctx, level)
+}
+
+// Handle passes r on to the wrapped handler
+//
+//nolint:gocritic // slog.Handler interface requires value receiver
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler adding attrs to the wrapped handler,
+// following the component they name if any
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *h
+	derived.handler = h.handler.WithAttrs(attrs)
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == ComponentKey {
+				derived.component = a.Value.Resolve().String()
+			}
+		}
+	}
+	return &derived
+}
+
+// WithGroup returns a handler opening a group on the wrapped handler
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.handler = h.handler.WithGroup(name)
+	derived.grouped = true
+	return &derived
+}
+
+// followLevels makes opts let records through at the floor of levels,
+// leaving the choice to a levelHandler. Unless addSource is set, source
+// locations are shown only while the base level is debug or debug
+// logging is switched on.
+func followLevels(opts *slog.HandlerOptions, levels *Levels, addSource bool) {
+	opts.Level = &levels.floor
+	if addSource {
+		return
+	}
+	replaceAttr := opts.ReplaceAttr
+	opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.SourceKey && len(groups) == 0 && levels.Level() > slog.LevelDebug && !levels.Debugging() {
+			return slog.Attr{}
+		}
+		return replaceAttr(groups, a)
+	}
+}
+
+// toggleDebug switches debug logging of levels, logging the change while
+// debug logging is on so it shows whatever the levels
+func toggleDebug(levels *Levels, log Logger) {
+	if levels.Debugging() {
+		log.Info("debug logging switched off", "levels", levels.String())
+		levels.SetDebug(false)
+		return
+	}
+	levels.SetDebug(true)
+	log.Info("debug logging switched on")
+}
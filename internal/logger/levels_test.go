@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	levels := NewLevels(slog.LevelInfo, map[string]slog.Level{"cli": slog.LevelWarn})
+	levels.SetComponent("greeting", slog.LevelDebug)
+
+	tests := []struct {
+		component string
+		want      slog.Level
+	}{
+		{component: "", want: slog.LevelInfo},
+		{component: "server", want: slog.LevelInfo},
+		{component: "cli", want: slog.LevelWarn},
+		{component: "greeting", want: slog.LevelDebug},
+	}
+	for _, tt := range tests {
+		if got := levels.For(tt.component); got != tt.want {
+			t.Errorf("For(%q) = %v, want %v", tt.component, got, tt.want)
+		}
+	}
+	if got := levels.String(); got != "info (cli=warn greeting=debug)" {
+		t.Errorf("String() = %q", got)
+	}
+	if levels.floor.Level() != slog.LevelDebug {
+		t.Errorf("floor = %v, want the greeting level", levels.floor.Level())
+	}
+
+	levels.ResetComponent("greeting")
+	if got := levels.For("greeting"); got != slog.LevelInfo || levels.floor.Level() != slog.LevelInfo {
+		t.Errorf("after reset For(greeting) = %v, floor %v, want info", got, levels.floor.Level())
+	}
+
+	if !levels.ToggleDebug() || levels.For("cli") != slog.LevelDebug {
+		t.Errorf("ToggleDebug() did not lower cli to debug")
+	}
+	if levels.ToggleDebug() || levels.For("cli") != slog.LevelWarn {
+		t.Errorf("second ToggleDebug() did not restore cli to warn")
+	}
+}
+
+func TestComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	reloadable, reloader := NewReloadable(Config{Level: "info", Levels: map[string]string{"greeting": "debug", "cli": "warn"}, Format: "text", Writer: &buf})
+
+	for name, log := range map[string]Logger{
+		"new":        New(Config{Level: "info", Levels: map[string]string{"greeting": "debug", "cli": "warn"}, Format: "text", Writer: &buf}),
+		"reloadable": reloadable,
+	} {
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+			ctx := WithContext(context.Background(), log)
+			FromContext(WithComponent(ctx, "greeting")).Debug("greeting debug")
+			FromContext(WithComponent(ctx, "cli")).Info("cli info")
+			FromContext(WithComponent(ctx, "cli")).Warn("cli warn")
+			log.With(ComponentKey, "server").Debug("server debug")
+			log.With(ComponentKey, "server").Info("server info")
+			log.With(slog.Group("request", ComponentKey, "greeting")).Debug("grouped debug")
+
+			got := buf.String()
+			for _, want := range []string{"greeting debug", "component=greeting", "cli warn", "server info"} {
+				if !strings.Contains(got, want) {
+					t.Errorf("output is missing %q:\n%s", want, got)
+				}
+			}
+			for _, omit := range []string{"cli info", "server debug", "grouped debug"} {
+				if strings.Contains(got, omit) {
+					t.Errorf("output has %q:\n%s", omit, got)
+				}
+			}
+			if strings.Contains(got, "source=") {
+				t.Errorf("output has source locations below debug:\n%s", got)
+			}
+		})
+	}
+
+	// Levels changed at runtime survive reloads that leave them alone
+	buf.Reset()
+	log := reloadable.With(ComponentKey, "server")
+	reloader.Levels().SetComponent("server", slog.LevelDebug)
+	if err := reloader.Reload(Config{Level: "info", Levels: map[string]string{"greeting": "debug", "cli": "warn"}, Format: "json", Writer: &buf}); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	log.Debug("kept")
+	if err := reloader.Reload(Config{Level: "warn", Format: "json", Writer: &buf}); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	log.Info("replaced")
+	if got := buf.String(); !strings.Contains(got, "kept") || strings.Contains(got, "replaced") {
+		t.Errorf("output = %s, want the runtime level until the config changes it", got)
+	}
+	if err := reloader.Reload(Config{Level: "info", Levels: map[string]string{"cli": "loud"}}); err == nil {
+		t.Error("Reload() with an unknown component level succeeded")
+	}
+}
+
+func TestToggleDebug(t *testing.T) {
+	var buf bytes.Buffer
+	log, reloader := NewReloadable(Config{Level: "warn", Format: "text", Writer: &buf})
+	levels := reloader.Levels()
+
+	log.Debug("before")
+	toggleDebug(levels, log)
+	log.Debug("while debugging")
+	toggleDebug(levels, log)
+	log.Debug("after")
+
+	got := buf.String()
+	for _, want := range []string{"debug logging switched on", "while debugging", "source=", "debug logging switched off"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "before") || strings.Contains(got, "after") {
+		t.Errorf("output has debug lines while off:\n%s", got)
+	}
+}
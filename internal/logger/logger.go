@@ -2,6 +2,9 @@ package logger
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -18,84 +21,169 @@ type Logger interface {
 	Warn(msg string, fields ...any)
 	Error(msg string, fields ...any)
 
+	// The XxxContext methods add the fields of ctx set with WithFields
+	DebugContext(ctx context.Context, msg string, fields ...any)
+	InfoContext(ctx context.Context, msg string, fields ...any)
+	WarnContext(ctx context.Context, msg string, fields ...any)
+	ErrorContext(ctx context.Context, msg string, fields ...any)
+
 	With(fields ...any) Logger
 	WithError(err error) Logger
 }
 
 // Config holds logger configuration
 type Config struct {
-	Level     string // debug, info, warn, error
-	Format    string // text, json
-	Output    string // stdout, stderr
-	NoColor   bool   // disable color in text format
-	AddSource bool   // include file:line in output (auto-enabled for debug level)
+	Level     string            // debug, info, warn, error
+	Levels    map[string]string // level by component, overriding Level
+	Format    string            // text, json, color, logfmt
+	Output    string            // stdout, stderr, a file path or a file:// URL
+	Rotation  Rotation          // rotation of file outputs
+	Writer    io.Writer         // written to instead of Output when set
+	NoColor   bool              // disable color in text format
+	AddSource bool              // include file:line in output (auto-enabled for debug level)
+	Sinks     []Sink            // more destinations, each with its own level and format
+	Sampling  Sampling          // limits on records below warn, in every output
+	Redactor  *Redactor         // hides sensitive attributes in every output, if set
 }
 
 // DefaultConfig returns default logger configuration
 func DefaultConfig() Config {
 	return Config{
-		Level:   "info",
-		Format:  "text",
-		Output:  "stderr",
-		NoColor: false,
+		Level:    "info",
+		Format:   "text",
+		Output:   "stderr",
+		NoColor:  false,
+		Redactor: DefaultRedactor(),
 	}
 }
 
 // slogLogger wraps slog.Logger to implement our Logger interface
 type slogLogger struct {
 	logger *slog.Logger
+	ctx    context.Context // used by the methods without a context, if set
 }
 
 // New creates a new logger with the given configuration
 func New(cfg Config) Logger {
-	level := parseLogLevel(cfg.Level, &cfg)
-	opts := createHandlerOptions(level, cfg)
-	output := getOutput(cfg.Output)
-	handler := createHandler(cfg, output, opts)
+	level, components, levelsErr := configLevels(cfg)
+	handler, err := newHandler(cfg, NewLevels(level, components))
+	l := &slogLogger{
+		logger: slog.New(&contextHandler{handler: handler}),
+	}
+	// Callers validate the config first; say so rather than silently
+	// logging at a different level or place
+	warnFallback(l, errors.Join(levelsErr, err))
+	return l
+}
 
-	return &slogLogger{
-		logger: slog.New(handler),
+// warnFallback logs why a logger does not follow its configuration
+func warnFallback(l Logger, err error) {
+	if err != nil {
+		l.Warn("ignoring invalid log settings", "error", err)
 	}
 }
 
+// newHandler creates the handler for a configuration, fanning out to its
+// sinks if it has any and sampling if set. The main output and sinks
+// without a level of their own follow levels, if not nil, instead of the
+// configured level. An unknown level or a log file that cannot be opened
+// is returned as an error alongside a handler logging at info, to stderr
+// or without the sink.
+func newHandler(cfg Config, levels *Levels) (slog.Handler, error) {
+	// Parsing a debug level turns on AddSource for the main output only
+	primary := cfg
+	level, levelErr := parseLogLevel(primary.Level, &primary)
+	output, outputErr := getOutput(primary)
+	handler := createLeveledHandler(primary, output, level, levels)
+
+	errs := []error{levelErr, outputErr}
+	if len(cfg.Sinks) > 0 {
+		handlers := []slog.Handler{handler}
+		for _, sink := range cfg.Sinks {
+			h, err := sinkHandler(cfg, sink, levels)
+			if h != nil {
+				handlers = append(handlers, h)
+			}
+			errs = append(errs, err)
+		}
+		handler = NewMultiHandler(handlers...)
+	}
+	if cfg.Sampling.enabled() {
+		handler = NewSamplingHandler(handler, cfg.Sampling)
+	}
+	return handler, errors.Join(errs...)
+}
+
 // Debug logs a message at debug level
 func (l *slogLogger) Debug(msg string, fields ...any) {
 	// Skip 2 frames: this method and the caller's location
-	l.logWithCaller(slog.LevelDebug, msg, fields...)
+	l.logWithCaller(l.context(), slog.LevelDebug, msg, fields...)
 }
 
 // Info logs a message at info level
 func (l *slogLogger) Info(msg string, fields ...any) {
-	l.logWithCaller(slog.LevelInfo, msg, fields...)
+	l.logWithCaller(l.context(), slog.LevelInfo, msg, fields...)
 }
 
 // Warn logs a message at warn level
 func (l *slogLogger) Warn(msg string, fields ...any) {
-	l.logWithCaller(slog.LevelWarn, msg, fields...)
+	l.logWithCaller(l.context(), slog.LevelWarn, msg, fields...)
 }
 
 // Error logs a message at error level
 func (l *slogLogger) Error(msg string, fields ...any) {
-	l.logWithCaller(slog.LevelError, msg, fields...)
+	l.logWithCaller(l.context(), slog.LevelError, msg, fields...)
+}
+
+// DebugContext logs a message at debug level with the fields of ctx
+func (l *slogLogger) DebugContext(ctx context.Context, msg string, fields ...any) {
+	l.logWithCaller(ctx, slog.LevelDebug, msg, fields...)
+}
+
+// InfoContext logs a message at info level with the fields of ctx
+func (l *slogLogger) InfoContext(ctx context.Context, msg string, fields ...any) {
+	l.logWithCaller(ctx, slog.LevelInfo, msg, fields...)
+}
+
+// WarnContext logs a message at warn level with the fields of ctx
+func (l *slogLogger) WarnContext(ctx context.Context, msg string, fields ...any) {
+	l.logWithCaller(ctx, slog.LevelWarn, msg, fields...)
+}
+
+// ErrorContext logs a message at error level with the fields of ctx
+func (l *slogLogger) ErrorContext(ctx context.Context, msg string, fields ...any) {
+	l.logWithCaller(ctx, slog.LevelError, msg, fields...)
+}
+
+// context returns the context the logger was taken from, if any
+func (l *slogLogger) context() context.Context {
+	if l.ctx == nil {
+		return context.Background()
+	}
+	return l.ctx
 }
 
 // logWithCaller logs with the correct caller information
-func (l *slogLogger) logWithCaller(level slog.Level, msg string, fields ...any) {
+func (l *slogLogger) logWithCaller(ctx context.Context, level slog.Level, msg string, fields ...any) {
 	var pcs [1]uintptr
 	// Skip 3 frames to get the real caller:
 	// 1. runtime.Callers
 	// 2. this method (logWithCaller)
 	// 3. the wrapper method (Debug, Info, etc.)
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
 	runtime.Callers(3, pcs[:])
 	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
 	r.Add(fields...)
-	_ = l.logger.Handler().Handle(context.Background(), r)
+	_ = l.logger.Handler().Handle(ctx, r)
 }
 
 // With returns a new logger with additional fields
 func (l *slogLogger) With(fields ...any) Logger {
 	return &slogLogger{
 		logger: l.logger.With(fields...),
+		ctx:    l.ctx,
 	}
 }
 
@@ -104,32 +192,51 @@ func (l *slogLogger) WithError(err error) Logger {
 	return l.With("error", err)
 }
 
-// FromContext returns the logger from context, or a default logger if not found
+// FromContext returns the logger from context, or a default logger if not
+// found, naming the component set with WithComponent. Its methods without
+// a context add the fields of ctx set with WithFields.
 func FromContext(ctx context.Context) Logger {
-	if logger, ok := ctx.Value(loggerKey{}).(Logger); ok {
-		return logger
+	logger, ok := ctx.Value(loggerKey{}).(Logger)
+	if !ok {
+		logger = New(DefaultConfig())
+	}
+	if component, ok := ctx.Value(componentKey{}).(string); ok {
+		logger = logger.With(ComponentKey, component)
 	}
-	return New(DefaultConfig())
+	if sl, ok := logger.(*slogLogger); ok {
+		return &slogLogger{logger: sl.logger, ctx: ctx}
+	}
+	return logger
 }
 
-// parseLogLevel converts string level to slog.Level and handles debug special case
-func parseLogLevel(levelStr string, cfg *Config) slog.Level {
+// ParseLevel converts a level name to slog.Level. Names are case
+// insensitive and warning is accepted for warn.
+func ParseLevel(levelStr string) (slog.Level, error) {
 	switch strings.ToLower(levelStr) {
 	case "debug":
-		// Auto-enable source location for debug level
-		cfg.AddSource = true
-		return slog.LevelDebug
+		return slog.LevelDebug, nil
 	case "info":
-		return slog.LevelInfo
+		return slog.LevelInfo, nil
 	case "warn", "warning":
-		return slog.LevelWarn
+		return slog.LevelWarn, nil
 	case "error":
-		return slog.LevelError
+		return slog.LevelError, nil
 	default:
-		return slog.LevelInfo
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", levelStr)
 	}
 }
 
+// parseLogLevel converts string level to slog.Level and handles debug
+// special case. Unknown levels log at info; the returned error says why.
+func parseLogLevel(levelStr string, cfg *Config) (slog.Level, error) {
+	level, err := ParseLevel(levelStr)
+	if level == slog.LevelDebug {
+		// Auto-enable source location for debug level
+		cfg.AddSource = true
+	}
+	return level, err
+}
+
 // createHandlerOptions creates slog.HandlerOptions with source replacement
 func createHandlerOptions(level slog.Level, cfg Config) *slog.HandlerOptions {
 	// Get current working directory for relative paths
@@ -149,7 +256,7 @@ func createHandlerOptions(level slog.Level, cfg Config) *slog.HandlerOptions {
 				source.Function = ""
 			}
 		}
-		return a
+		return cfg.Redactor.ReplaceAttr(groups, a)
 	}
 
 	return &slog.HandlerOptions{
@@ -159,20 +266,32 @@ func createHandlerOptions(level slog.Level, cfg Config) *slog.HandlerOptions {
 	}
 }
 
-// getOutput returns the appropriate output file
-func getOutput(outputStr string) *os.File {
-	switch outputStr {
+// getOutput returns the appropriate output. Loggers writing to the same
+// file share it; a file that cannot be opened falls back to stderr.
+func getOutput(cfg Config) (io.Writer, error) {
+	if cfg.Writer != nil {
+		return cfg.Writer, nil
+	}
+	switch cfg.Output {
 	case "stdout":
-		return os.Stdout
-	case "stderr":
-		return os.Stderr
-	default:
-		return os.Stderr
+		return os.Stdout, nil
+	case "stderr", "":
+		return os.Stderr, nil
+	}
+
+	path, err := OutputPath(cfg.Output)
+	if err != nil {
+		return os.Stderr, err
 	}
+	file, err := openOutput(path, cfg.Rotation)
+	if err != nil {
+		return os.Stderr, err
+	}
+	return file, nil
 }
 
 // createHandler creates the appropriate slog.Handler based on configuration
-func createHandler(cfg Config, output *os.File, opts *slog.HandlerOptions) slog.Handler {
+func createHandler(cfg Config, output io.Writer, opts *slog.HandlerOptions) slog.Handler {
 	switch strings.ToLower(cfg.Format) {
 	case "json":
 		return slog.NewJSONHandler(output, opts)
@@ -181,11 +300,28 @@ func createHandler(cfg Config, output *os.File, opts *slog.HandlerOptions) slog.
 			return slog.NewTextHandler(output, opts)
 		}
 		return NewColorHandler(output, opts)
+	case "color":
+		return NewColorHandler(output, opts)
+	case "logfmt":
+		return slog.NewTextHandler(output, opts)
 	default:
 		return slog.NewTextHandler(output, opts)
 	}
 }
 
+// createLeveledHandler creates the handler of an output logging at level,
+// or following levels if not nil
+func createLeveledHandler(cfg Config, output io.Writer, level slog.Level, levels *Levels) slog.Handler {
+	if levels == nil {
+		return createHandler(cfg, output, createHandlerOptions(level, cfg))
+	}
+	addSource := cfg.AddSource
+	cfg.AddSource = true
+	opts := createHandlerOptions(level, cfg)
+	followLevels(opts, levels, addSource)
+	return &levelHandler{handler: createHandler(cfg, output, opts), levels: levels}
+}
+
 // WithContext returns a new context with the logger attached
 func WithContext(ctx context.Context, logger Logger) context.Context {
 	return context.WithValue(ctx, loggerKey{}, logger)
@@ -193,9 +329,10 @@ func WithContext(ctx context.Context, logger Logger) context.Context {
 
 type loggerKey struct{}
 
-// isTerminal checks if the file descriptor is a terminal
-func isTerminal(f *os.File) bool {
-	if f == nil {
+// isTerminal checks if the output is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || f == nil {
 		return false
 	}
 	// Simple check - in production you might want to use golang.org/x/term
@@ -222,11 +359,14 @@ func Default() Logger {
 // Debug logs a message at debug level using the default logger
 func Debug(msg string, fields ...any) {
 	if sl, ok := defaultLogger.(*slogLogger); ok {
+		if !sl.logger.Enabled(sl.context(), slog.LevelDebug) {
+			return
+		}
 		var pcs [1]uintptr
 		runtime.Callers(2, pcs[:]) // Skip runtime.Callers and this function
 		r := slog.NewRecord(time.Now(), slog.LevelDebug, msg, pcs[0])
 		r.Add(fields...)
-		_ = sl.logger.Handler().Handle(context.Background(), r)
+		_ = sl.logger.Handler().Handle(sl.context(), r)
 	} else {
 		defaultLogger.Debug(msg, fields...)
 	}
@@ -235,11 +375,14 @@ func Debug(msg string, fields ...any) {
 // Info logs a message at info level using the default logger
 func Info(msg string, fields ...any) {
 	if sl, ok := defaultLogger.(*slogLogger); ok {
+		if !sl.logger.Enabled(sl.context(), slog.LevelInfo) {
+			return
+		}
 		var pcs [1]uintptr
 		runtime.Callers(2, pcs[:])
 		r := slog.NewRecord(time.Now(), slog.LevelInfo, msg, pcs[0])
 		r.Add(fields...)
-		_ = sl.logger.Handler().Handle(context.Background(), r)
+		_ = sl.logger.Handler().Handle(sl.context(), r)
 	} else {
 		defaultLogger.Info(msg, fields...)
 	}
@@ -248,11 +391,14 @@ func Info(msg string, fields ...any) {
 // Warn logs a message at warn level using the default logger
 func Warn(msg string, fields ...any) {
 	if sl, ok := defaultLogger.(*slogLogger); ok {
+		if !sl.logger.Enabled(sl.context(), slog.LevelWarn) {
+			return
+		}
 		var pcs [1]uintptr
 		runtime.Callers(2, pcs[:])
 		r := slog.NewRecord(time.Now(), slog.LevelWarn, msg, pcs[0])
 		r.Add(fields...)
-		_ = sl.logger.Handler().Handle(context.Background(), r)
+		_ = sl.logger.Handler().Handle(sl.context(), r)
 	} else {
 		defaultLogger.Warn(msg, fields...)
 	}
@@ -261,11 +407,14 @@ func Warn(msg string, fields ...any) {
 // Error logs a message at error level using the default logger
 func Error(msg string, fields ...any) {
 	if sl, ok := defaultLogger.(*slogLogger); ok {
+		if !sl.logger.Enabled(sl.context(), slog.LevelError) {
+			return
+		}
 		var pcs [1]uintptr
 		runtime.Callers(2, pcs[:])
 		r := slog.NewRecord(time.Now(), slog.LevelError, msg, pcs[0])
 		r.Add(fields...)
-		_ = sl.logger.Handler().Handle(context.Background(), r)
+		_ = sl.logger.Handler().Handle(sl.context(), r)
 	} else {
 		defaultLogger.Error(msg, fields...)
 	}
@@ -3,6 +3,7 @@ package logger
 import (
 	"bytes"
 	"context"
+	"log/slog"
 	"testing"
 )
 
@@ -229,3 +230,30 @@ func TestAddSourceConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{name: "debug", want: slog.LevelDebug},
+		{name: "INFO", want: slog.LevelInfo},
+		{name: "warning", want: slog.LevelWarn},
+		{name: "error", want: slog.LevelError},
+		{name: "verbose", want: slog.LevelInfo, wantErr: true},
+		{name: "", want: slog.LevelInfo, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLevel(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLevel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Redaction actions
+const (
+	RedactMask = "mask" // Replace the value with [REDACTED]
+	RedactHash = "hash" // Replace the value with a short SHA-256 hash, so equal values can be correlated
+	RedactDrop = "drop" // Leave the attribute out
+)
+
+// redactedValue replaces masked values
+const redactedValue = "[REDACTED]"
+
+// RedactRule hides attributes whose key matches Key, or the parts of
+// string values matching Value. Key is a case-insensitive glob such as
+// *token*, matched against the attribute key and its dotted path within
+// groups, such as http.authorization.
+type RedactRule struct {
+	Key    string // Glob matching attribute keys
+	Value  string // Regular expression matching parts of values
+	Action string // RedactMask, RedactHash or RedactDrop; mask if empty
+}
+
+// DefaultRedactRules mask credentials and the names greeted by key, and
+// email addresses anywhere in values
+func DefaultRedactRules() []RedactRule {
+	return []RedactRule{
+		{Key: "*password*", Action: RedactMask},
+		{Key: "*secret*", Action: RedactMask},
+		{Key: "*token*", Action: RedactMask},
+		{Key: "*api_key*", Action: RedactMask},
+		{Key: "authorization", Action: RedactMask},
+		{Key: "cookie", Action: RedactMask},
+		{Key: "name", Action: RedactMask},
+		{Key: "names", Action: RedactMask},
+		{Key: "message", Action: RedactMask},
+		{Value: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, Action: RedactMask},
+	}
+}
+
+// Redactor applies redaction rules to log attributes and error details.
+// A nil Redactor leaves everything as is.
+type Redactor struct {
+	rules []redactRule
+}
+
+// defaultRedactor applies DefaultRedactRules
+var defaultRedactor, _ = NewRedactor(DefaultRedactRules())
+
+// DefaultRedactor returns a Redactor applying DefaultRedactRules
+func DefaultRedactor() *Redactor {
+	return defaultRedactor
+}
+
+type redactRule struct {
+	key    string
+	value  *regexp.Regexp
+	action string
+}
+
+// NewRedactor compiles rules. Each rule needs a Key or a Value.
+func NewRedactor(rules []RedactRule) (*Redactor, error) {
+	r := &Redactor{}
+	for i, rule := range rules {
+		if err := CheckRedactRule(rule); err != nil {
+			return nil, fmt.Errorf("redaction rule %d: %w", i+1, err)
+		}
+		compiled := redactRule{key: strings.ToLower(rule.Key), action: rule.Action}
+		if compiled.action == "" {
+			compiled.action = RedactMask
+		}
+		if rule.Value != "" {
+			compiled.value = regexp.MustCompile(rule.Value)
+		}
+		r.rules = append(r.rules, compiled)
+	}
+	return r, nil
+}
+
+// CheckRedactRule reports what is wrong with a rule, if anything
+func CheckRedactRule(rule RedactRule) error {
+	if rule.Key == "" && rule.Value == "" {
+		return fmt.Errorf("needs a key pattern or a value pattern")
+	}
+	if _, err := path.Match(strings.ToLower(rule.Key), ""); err != nil {
+		return fmt.Errorf("invalid key pattern %q", rule.Key)
+	}
+	if _, err := regexp.Compile(rule.Value); err != nil {
+		return fmt.Errorf("invalid value pattern %q: %w", rule.Value, err)
+	}
+	switch rule.Action {
+	case "", RedactMask, RedactHash, RedactDrop:
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q, use mask, hash or drop", rule.Action)
+	}
+}
+
+// ReplaceAttr redacts an attribute, for use in slog.HandlerOptions.
+// Dropped attributes are returned empty, which handlers leave out.
+func (r *Redactor) ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if r == nil || len(r.rules) == 0 || a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.SourceKey) {
+		return a
+	}
+	dotted := a.Key
+	if len(groups) > 0 {
+		dotted = strings.Join(groups, ".") + "." + a.Key
+	}
+
+	value, keep := r.redact(dotted, a.Key, a.Value.Resolve().Any())
+	if !keep {
+		return slog.Attr{}
+	}
+	if s, ok := value.(string); ok {
+		if original, ok := a.Value.Any().(string); !ok || original != s {
+			a.Value = slog.StringValue(s)
+		}
+	}
+	return a
+}
+
+// Redact returns a value with the rules applied, and false if it should be
+// left out. It is used for values outside log records, such as error
+// details.
+func (r *Redactor) Redact(key string, value any) (any, bool) {
+	if r == nil {
+		return value, true
+	}
+	return r.redact(key, key, value)
+}
+
+func (r *Redactor) redact(dotted, key string, value any) (any, bool) {
+	dotted, key = strings.ToLower(dotted), strings.ToLower(key)
+	for _, rule := range r.rules {
+		if rule.key == "" {
+			continue
+		}
+		if matchKey(rule.key, key) || matchKey(rule.key, dotted) {
+			return apply(rule.action, fmt.Sprint(value))
+		}
+	}
+
+	text, ok := valueText(value)
+	if !ok {
+		return value, true
+	}
+	redacted := text
+	for _, rule := range r.rules {
+		if rule.value == nil || !rule.value.MatchString(redacted) {
+			continue
+		}
+		if rule.action == RedactDrop {
+			return nil, false
+		}
+		redacted = rule.value.ReplaceAllStringFunc(redacted, func(match string) string {
+			replaced, _ := apply(rule.action, match)
+			return replaced.(string)
+		})
+	}
+	if redacted == text {
+		return value, true
+	}
+	return redacted, true
+}
+
+func matchKey(pattern, key string) bool {
+	ok, _ := path.Match(pattern, key)
+	return ok
+}
+
+// valueText returns the text value patterns are matched against: strings,
+// errors and Stringers
+func valueText(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case error:
+		return v.Error(), true
+	case fmt.Stringer:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
+
+// apply redacts text with an action
+func apply(action, text string) (any, bool) {
+	switch action {
+	case RedactDrop:
+		return nil, false
+	case RedactHash:
+		sum := sha256.Sum256([]byte(text))
+		return "sha256:" + hex.EncodeToString(sum[:6]), true
+	default:
+		return redactedValue, true
+	}
+}
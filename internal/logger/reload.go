@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// Reloader replaces the configuration of a logger while it is in use,
+// for example when the config file changes
+type Reloader struct {
+	current *atomic.Pointer[slog.Handler]
+	levels  *Levels
+	cfg     Config // last configuration applied
+}
+
+// NewReloadable creates a logger whose configuration can be replaced with
+// the returned Reloader. Loggers derived from it with With keep their
+// fields and follow later reloads.
+func NewReloadable(cfg Config) (Logger, *Reloader) {
+	level, components, levelsErr := configLevels(cfg)
+	r := &Reloader{
+		current: new(atomic.Pointer[slog.Handler]),
+		levels:  NewLevels(level, components),
+		cfg:     cfg,
+	}
+	handler, err := newHandler(cfg, r.levels)
+	r.current.Store(&handler)
+
+	l := &slogLogger{logger: slog.New(&contextHandler{handler: &swapHandler{current: r.current}})}
+	warnFallback(l, errors.Join(levelsErr, err))
+	return l, r
+}
+
+// Reload switches the logger to cfg. An unknown level or a log file that
+// cannot be opened is returned as an error and leaves the logger unchanged.
+// The levels of cfg replace those changed through Levels only if they
+// differ from the last configuration's. Log files the previous
+// configuration wrote to and cfg does not are closed.
+func (r *Reloader) Reload(cfg Config) error {
+	level, components, err := configLevels(cfg)
+	if err != nil {
+		return err
+	}
+	handler, err := newHandler(cfg, r.levels)
+	if err != nil {
+		releaseOutputs(unusedOutputs(cfg, r.cfg))
+		return err
+	}
+	if cfg.Level != r.cfg.Level || !maps.Equal(cfg.Levels, r.cfg.Levels) {
+		r.levels.Configure(level, components)
+	}
+	old := r.cfg
+	r.cfg = cfg
+	r.current.Store(&handler)
+	releaseOutputs(unusedOutputs(old, cfg))
+	return nil
+}
+
+// unusedOutputs returns the log files cfg writes to and keep does not, as
+// keys of files
+func unusedOutputs(cfg, keep Config) []string {
+	kept := outputFiles(keep)
+	var unused []string
+	for _, path := range outputFiles(cfg) {
+		if !slices.Contains(kept, path) {
+			unused = append(unused, path)
+		}
+	}
+	return unused
+}
+
+// outputFiles returns the log files of the main output and sinks of cfg,
+// as keys of files
+func outputFiles(cfg Config) []string {
+	var paths []string
+	add := func(output string, writer io.Writer) {
+		if writer != nil {
+			return
+		}
+		if path, err := OutputPath(output); err == nil && path != "" {
+			paths = append(paths, outputKey(path))
+		}
+	}
+	add(cfg.Output, cfg.Writer)
+	for _, sink := range cfg.Sinks {
+		add(sink.Output, sink.Writer)
+	}
+	return paths
+}
+
+// Levels returns the levels of the logger, which can be changed while it
+// runs
+func (r *Reloader) Levels() *Levels {
+	return r.levels
+}
+
+// swapHandler sends records to the current handler of a Reloader, with
+// the attributes and groups added since applied on top
+type swapHandler struct {
+	current *atomic.Pointer[slog.Handler]
+	derive  []func(slog.Handler) slog.Handler // WithAttrs and WithGroup calls, in order
+	derived atomic.Pointer[derivedHandler]    // derive applied to current, once per reload
+}
+
+// derivedHandler is a handler derived from the current handler base
+type derivedHandler struct {
+	base    *slog.Handler
+	handler slog.Handler
+}
+
+// Enabled reports whether the current handler, with the attributes added
+// since, handles records at level
+func (h *swapHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler().Enabled(ctx, level)
+}
+
+// Handle sends r to the current handler
+//
+//nolint:gocritic // slog.Handler interface requires value receiver
+func (h *swapHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(ctx, r)
+}
+
+// handler returns the current handler with derive applied
+func (h *swapHandler) handler() slog.Handler {
+	base := h.current.Load()
+	if len(h.derive) == 0 {
+		return *base
+	}
+	if d := h.derived.Load(); d != nil && d.base == base {
+		return d.handler
+	}
+	handler := *base
+	for _, derive := range h.derive {
+		handler = derive(handler)
+	}
+	h.derived.Store(&derivedHandler{base: base, handler: handler})
+	return handler
+}
+
+// WithAttrs returns a handler adding attrs to the current handler
+func (h *swapHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+// WithGroup returns a handler opening a group on the current handler
+func (h *swapHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *swapHandler) with(derive func(slog.Handler) slog.Handler) *swapHandler {
+	return &swapHandler{current: h.current, derive: append(slices.Clip(h.derive), derive)}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReloadable(t *testing.T) {
+	var buf bytes.Buffer
+	log, reloader := NewReloadable(Config{Level: "info", Format: "text", Writer: &buf})
+	child := log.With("component", "server")
+
+	child.Debug("hidden")
+	child.Info("before reload")
+	if err := reloader.Reload(Config{Level: "debug", Format: "json", Writer: &buf}); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	child.Debug("after reload")
+
+	if err := reloader.Reload(Config{Level: "verbose", Format: "text", Writer: &buf}); err == nil {
+		t.Error("Reload() with an unknown level succeeded")
+	}
+	child.Debug("still json")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "msg=\"before reload\" component=server") {
+		t.Errorf("line 1 = %q, want text with the child's fields", lines[0])
+	}
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"component":"server"`) {
+			t.Errorf("line = %q, want json with the child's fields", line)
+		}
+	}
+}
+
+func TestReloadClosesOutputs(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	t.Cleanup(func() { releaseOutputs([]string{outputKey(first), outputKey(second)}) })
+
+	log, reloader := NewReloadable(Config{Level: "info", Format: "text", Output: first})
+	log.Info("to first")
+	filesMu.Lock()
+	firstFile := files[outputKey(first)]
+	filesMu.Unlock()
+	if firstFile == nil {
+		t.Fatal("the first output is not open")
+	}
+
+	if err := reloader.Reload(Config{Level: "info", Format: "text", Output: second}); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	log.Info("to second")
+
+	filesMu.Lock()
+	_, registered := files[outputKey(first)]
+	filesMu.Unlock()
+	firstFile.mu.Lock()
+	closed := firstFile.file == nil
+	firstFile.mu.Unlock()
+	if registered || !closed {
+		t.Errorf("first output registered = %v, closed = %v, want closed and forgotten", registered, closed)
+	}
+
+	// A record already on its way to the first file is written and the
+	// file closed again
+	if _, err := firstFile.Write([]byte("late\n")); err != nil {
+		t.Fatalf("Write() after release error = %v", err)
+	}
+	if firstFile.file != nil {
+		t.Error("write after release left the first output open")
+	}
+
+	for path, want := range map[string]string{first: "to first", second: "to second"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); !strings.Contains(got, want) || strings.Count(got, "msg=") != 1 {
+			t.Errorf("%s = %q, want only %q", filepath.Base(path), got, want)
+		}
+	}
+}
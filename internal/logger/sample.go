@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Sampling limits how many records below warn are logged, so a message
+// logged for every item of a batch or every request cannot flood the
+// output. Records dropped are counted and reported once per Interval.
+type Sampling struct {
+	Interval   time.Duration // window of First and Thereafter, and period of the summaries; 1s if zero
+	First      int           // records with the same level and message logged per Interval; 0 logs all
+	Thereafter int           // after First, log every Thereafter-th of them; 0 drops the rest
+	Rate       int           // records logged per second overall; 0 is unlimited
+	Burst      int           // records logged at once before Rate applies; Rate if zero
+}
+
+// enabled reports whether s drops any records
+func (s Sampling) enabled() bool {
+	return s.First > 0 || s.Rate > 0
+}
+
+// NewSamplingHandler returns a handler passing records to handler as
+// allowed by s. Records at warn and above are always passed on. Every
+// Interval in which records were dropped ends with a warning saying how
+// many; one still pending when the program exits is logged by Close.
+func NewSamplingHandler(handler slog.Handler, s Sampling) slog.Handler {
+	if s.Interval <= 0 {
+		s.Interval = time.Second
+	}
+	if s.Burst <= 0 {
+		s.Burst = s.Rate
+	}
+	return &samplingHandler{
+		handler: handler,
+		sampler: &sampler{
+			cfg:    s,
+			output: handler,
+			now:    time.Now,
+			counts: make(map[samplingKey]int),
+			tokens: float64(s.Burst),
+		},
+	}
+}
+
+// samplingHandler drops the records its sampler does not allow
+type samplingHandler struct {
+	handler slog.Handler
+	sampler *sampler // shared by the handlers derived with WithAttrs and WithGroup
+}
+
+// Enabled reports whether the wrapped handler handles records at level
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle passes r on if the sampler allows it
+//
+//nolint:gocritic // slog.Handler interface requires value receiver
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.sampler.allow(r.Level, r.Message) {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler adding attrs to the wrapped handler
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{handler: h.handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup returns a handler opening a group on the wrapped handler
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{handler: h.handler.WithGroup(name), sampler: h.sampler}
+}
+
+// samplingKey identifies the records counted together for First and
+// Thereafter
+type samplingKey struct {
+	level   slog.Level
+	message string
+}
+
+// sampler decides which records are logged and counts the others
+type sampler struct {
+	cfg    Sampling
+	output slog.Handler     // handler summaries are logged to, without added attributes
+	now    func() time.Time // returns the current time
+
+	mu      sync.Mutex
+	window  time.Time           // start of the current Interval
+	counts  map[samplingKey]int // records seen in the window by key
+	tokens  float64             // records Rate allows now
+	filled  time.Time           // when tokens was last topped up
+	sampled int                 // dropped by First and Thereafter since the last summary
+	limited int                 // dropped by Rate since the last summary
+	since   time.Time           // first drop since the last summary
+	timer   *time.Timer         // logs the pending summary
+}
+
+// allow reports whether a record is logged, counting it if not
+func (s *sampler) allow(level slog.Level, message string) bool {
+	if level >= slog.LevelWarn {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+
+	if s.cfg.First > 0 {
+		if now.Sub(s.window) >= s.cfg.Interval {
+			s.window = now
+			clear(s.counts)
+		}
+		key := samplingKey{level: level, message: message}
+		s.counts[key]++
+		if n := s.counts[key] - s.cfg.First; n > 0 && (s.cfg.Thereafter <= 0 || n%s.cfg.Thereafter != 0) {
+			s.sampled++
+			s.dropped(now)
+			return false
+		}
+	}
+
+	if s.cfg.Rate > 0 {
+		if !s.filled.IsZero() {
+			s.tokens += now.Sub(s.filled).Seconds() * float64(s.cfg.Rate)
+			s.tokens = min(s.tokens, float64(s.cfg.Burst))
+		}
+		s.filled = now
+		if s.tokens < 1 {
+			s.limited++
+			s.dropped(now)
+			return false
+		}
+		s.tokens--
+	}
+	return true
+}
+
+// dropped schedules the summary of a dropped record; s.mu must be held
+func (s *sampler) dropped(now time.Time) {
+	if s.timer != nil {
+		return
+	}
+	s.since = now
+	s.timer = time.AfterFunc(s.cfg.Interval, s.summarize)
+	registerSampler(s)
+}
+
+// summarize logs how many records were dropped since the last summary,
+// if any
+func (s *sampler) summarize() {
+	s.mu.Lock()
+	sampled, limited, since := s.sampled, s.limited, s.since
+	s.sampled, s.limited = 0, 0
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+		unregisterSampler(s)
+	}
+	now := s.now()
+	s.mu.Unlock()
+
+	ctx := context.Background()
+	if sampled+limited == 0 || !s.output.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+	r := slog.NewRecord(now, slog.LevelWarn, "dropped log records", 0)
+	r.AddAttrs(
+		slog.Int("dropped", sampled+limited),
+		slog.Int("sampled", sampled),
+		slog.Int("rate_limited", limited),
+		slog.Duration("period", now.Sub(since).Round(time.Millisecond)),
+	)
+	_ = s.output.Handle(ctx, r)
+}
+
+// samplers are those with a summary pending, logged by Close
+var (
+	samplersMu sync.Mutex
+	samplers   = map[*sampler]struct{}{}
+)
+
+func registerSampler(s *sampler) {
+	samplersMu.Lock()
+	defer samplersMu.Unlock()
+	samplers[s] = struct{}{}
+}
+
+func unregisterSampler(s *sampler) {
+	samplersMu.Lock()
+	defer samplersMu.Unlock()
+	delete(samplers, s)
+}
+
+// flushSamplers logs every pending summary
+func flushSamplers() {
+	samplersMu.Lock()
+	pending := make([]*sampler, 0, len(samplers))
+	for s := range samplers {
+		pending = append(pending, s)
+	}
+	samplersMu.Unlock()
+
+	for _, s := range pending {
+		s.summarize()
+	}
+}
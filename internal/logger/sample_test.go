@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestSampler returns a logger sampling with s to buf as JSON, and a
+// function moving its clock forward
+func newTestSampler(buf *bytes.Buffer, s Sampling) (*slog.Logger, *sampler, func(time.Duration)) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	h := NewSamplingHandler(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}), s).(*samplingHandler)
+	h.sampler.now = func() time.Time { return now }
+	return slog.New(h), h.sampler, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestSamplingFirstThereafter(t *testing.T) {
+	var buf bytes.Buffer
+	log, s, advance := newTestSampler(&buf, Sampling{Interval: time.Minute, First: 2, Thereafter: 3})
+
+	for i := range 10 {
+		log.With("item", i).Info("greeted")
+		log.Debug("other")
+	}
+	log.Warn("never sampled")
+	log.Warn("never sampled")
+	log.Warn("never sampled")
+	advance(time.Minute)
+	log.Info("greeted", "item", 10)
+	s.summarize()
+
+	lines := decodeLines(t, &buf)
+	var items []float64
+	counts := map[string]int{}
+	var summary map[string]any
+	for _, line := range lines {
+		counts[line["msg"].(string)]++
+		switch line["msg"] {
+		case "greeted":
+			items = append(items, line["item"].(float64))
+		case "dropped log records":
+			summary = line
+		}
+	}
+	// First 2, then every 3rd of the rest: items 0, 1, 4, 7, and the first of the next interval
+	if want := []float64{0, 1, 4, 7, 10}; !slices.Equal(items, want) {
+		t.Errorf("greeted items = %v, want %v", items, want)
+	}
+	if counts["other"] != 4 || counts["never sampled"] != 3 {
+		t.Errorf("counts = %v, want 4 other and 3 never sampled", counts)
+	}
+	if summary == nil || summary["level"] != "WARN" || summary["dropped"] != float64(12) || summary["sampled"] != float64(12) {
+		t.Errorf("summary = %v, want 12 sampled records", summary)
+	}
+}
+
+func TestSamplingRate(t *testing.T) {
+	var buf bytes.Buffer
+	log, s, advance := newTestSampler(&buf, Sampling{Rate: 2, Burst: 3})
+
+	for range 5 {
+		log.Info("request")
+	}
+	advance(time.Second)
+	for range 5 {
+		log.Info("request")
+	}
+	s.summarize()
+
+	lines := decodeLines(t, &buf)
+	if len(lines) != 6 {
+		t.Fatalf("logged %d lines, want a burst of 3, 2 more a second later and a summary:\n%s", len(lines), buf.String())
+	}
+	if summary := lines[5]; summary["rate_limited"] != float64(5) || summary["period"] != float64(time.Second) {
+		t.Errorf("summary = %v, want 5 rate limited over a second", summary)
+	}
+
+	// Nothing dropped since, nothing to report
+	buf.Reset()
+	s.summarize()
+	if buf.Len() != 0 {
+		t.Errorf("summary without drops = %s", buf.String())
+	}
+}
+
+func TestSamplingSummaryOnClose(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(Config{Level: "info", Format: "text", Writer: &buf, Sampling: Sampling{First: 1, Interval: time.Hour}})
+	ctx := WithFields(context.Background(), "run_id", "r1")
+	for range 3 {
+		log.InfoContext(ctx, "generated personalized greeting")
+	}
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	if strings.Count(got, "generated personalized greeting") != 1 || !strings.Contains(got, `msg="dropped log records" dropped=2`) {
+		t.Errorf("output = %s, want one greeting and a summary of the 2 dropped", got)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package logger
+
+import "context"
+
+// ToggleDebugOnSignal does nothing on systems without SIGUSR1
+func ToggleDebugOnSignal(context.Context, *Levels, Logger) {}
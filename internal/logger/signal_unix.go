@@ -0,0 +1,28 @@
+//go:build unix
+
+package logger
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ToggleDebugOnSignal switches debug logging of levels on and off each
+// time the process receives SIGUSR1, until ctx is done
+func ToggleDebugOnSignal(ctx context.Context, levels *Levels, log Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				toggleDebug(levels, log)
+			}
+		}
+	}()
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// Sink is a log destination with its own level and format, written to
+// alongside the one described by Config
+type Sink struct {
+	Level    string    // debug, info, warn, error; empty for the Config level
+	Format   string    // text, json, color, logfmt
+	Output   string    // stdout, stderr, a file path or a file:// URL
+	Rotation Rotation  // rotation of file outputs
+	Writer   io.Writer // written to instead of Output when set
+}
+
+// sinkHandler creates the handler of a sink. A sink without a level of
+// its own follows levels, if not nil, like the main output. Unlike the
+// main output, a sink whose file cannot be opened is left out rather than
+// sent to stderr.
+func sinkHandler(cfg Config, sink Sink, levels *Levels) (slog.Handler, error) {
+	sub := Config{
+		Level:     sink.Level,
+		Format:    sink.Format,
+		Output:    sink.Output,
+		Rotation:  sink.Rotation,
+		Writer:    sink.Writer,
+		NoColor:   cfg.NoColor,
+		AddSource: cfg.AddSource,
+		Redactor:  cfg.Redactor,
+	}
+	if sub.Level == "" {
+		sub.Level = cfg.Level
+	} else {
+		levels = nil
+	}
+
+	level, levelErr := parseLogLevel(sub.Level, &sub)
+	output, err := getOutput(sub)
+	if err != nil {
+		return nil, err
+	}
+	return createLeveledHandler(sub, output, level, levels), levelErr
+}
+
+// multiHandler sends each record to every handler enabled for its level
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler returns a handler writing each record to all of
+// handlers that are enabled for its level
+func NewMultiHandler(handlers ...slog.Handler) slog.Handler {
+	return &multiHandler{handlers: handlers}
+}
+
+// Enabled reports whether any handler handles records at level
+func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle sends r to the enabled handlers, returning their errors joined
+//
+//nolint:gocritic // slog.Handler interface requires value receiver
+func (h *multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, r.Level) {
+			errs = append(errs, handler.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a handler adding attrs to every handler
+func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &multiHandler{handlers: handlers}
+}
+
+// WithGroup returns a handler opening a group on every handler
+func (h *multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &multiHandler{handlers: handlers}
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSinks(t *testing.T) {
+	var main, debugJSON, warnLogfmt, color bytes.Buffer
+	log := New(Config{
+		Level:  "info",
+		Format: "text",
+		Writer: &main,
+		Sinks: []Sink{
+			{Level: "debug", Format: "json", Writer: &debugJSON},
+			{Level: "warn", Format: "logfmt", Writer: &warnLogfmt},
+			{Format: "color", Writer: &color},
+		},
+	}).With("component", "test")
+
+	log.Debug("debug message")
+	log.Info("info message")
+	log.Warn("warn message")
+
+	tests := []struct {
+		name     string
+		output   string
+		wantMsgs []string
+		wantOmit []string
+		want     string
+	}{
+		{name: "main", output: main.String(), wantMsgs: []string{"info message", "warn message"}, wantOmit: []string{"debug message"}, want: "component=test"},
+		{name: "json debug", output: debugJSON.String(), wantMsgs: []string{"debug message", "info message", "warn message"}, want: `"component":"test"`},
+		{name: "logfmt warn", output: warnLogfmt.String(), wantMsgs: []string{"warn message"}, wantOmit: []string{"debug message", "info message"}, want: `level=WARN msg="warn message"`},
+		{name: "color at the main level", output: color.String(), wantMsgs: []string{"info message"}, wantOmit: []string{"debug message"}, want: colorReset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, msg := range tt.wantMsgs {
+				if !strings.Contains(tt.output, msg) {
+					t.Errorf("output is missing %q:\n%s", msg, tt.output)
+				}
+			}
+			for _, msg := range tt.wantOmit {
+				if strings.Contains(tt.output, msg) {
+					t.Errorf("output has %q:\n%s", msg, tt.output)
+				}
+			}
+			if !strings.Contains(tt.output, tt.want) {
+				t.Errorf("output is missing %q:\n%s", tt.want, tt.output)
+			}
+		})
+	}
+
+	// Only the debug sink adds source locations
+	if !strings.Contains(debugJSON.String(), `"source"`) || strings.Contains(main.String(), "source=") {
+		t.Errorf("source locations: json %q, main %q", debugJSON.String(), main.String())
+	}
+}
+
+func TestSinkFileError(t *testing.T) {
+	var main bytes.Buffer
+	handler, err := newHandler(Config{
+		Level:  "info",
+		Format: "text",
+		Writer: &main,
+		Sinks:  []Sink{{Output: filepath.Join(t.TempDir(), "file", "\x00", "app.log")}},
+	}, nil)
+	if err == nil {
+		t.Error("newHandler() with an unusable sink succeeded")
+	}
+
+	// The main output still works and the sink is left out
+	slog.New(handler).InfoContext(context.Background(), "still logged")
+	if !strings.Contains(main.String(), "still logged") {
+		t.Errorf("main output = %q", main.String())
+	}
+	if m, ok := handler.(*multiHandler); !ok || len(m.handlers) != 1 {
+		t.Errorf("handler = %#v, want a multi-handler without the sink", handler)
+	}
+}
@@ -0,0 +1,329 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed kubectl-style JSONPath template such as
+// "{.message}" or "{range [*]}{.code}{\"\\n\"}{end}".
+//
+// The supported subset covers literal text, quoted string literals, field
+// access (.name or ['name']), indexes ([0], [-1]), slices ([1:3]),
+// wildcards ([*] or .*) and range/end blocks. Missing fields produce no
+// output; multiple results of one expression are separated by spaces.
+type JSONPath struct {
+	nodes []jsonPathNode
+}
+
+// jsonPathNode is a piece of a template: literal text, a path, or a range
+type jsonPathNode struct {
+	text     string
+	isText   bool
+	path     []pathStep
+	children []jsonPathNode // Set for range blocks
+	isRange  bool
+}
+
+// pathStep is one step of a path expression
+type pathStep struct {
+	kind       stepKind
+	name       string
+	index      int
+	start, end *int
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepSlice
+	stepWildcard
+)
+
+// ParseJSONPath parses a JSONPath template. A bare path without braces,
+// like ".message", is treated as "{.message}".
+func ParseJSONPath(tmpl string) (*JSONPath, error) {
+	if !strings.Contains(tmpl, "{") {
+		tmpl = "{" + tmpl + "}"
+	}
+
+	nodes, _, err := parseNodes(tmpl, false)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// parseNodes parses nodes until the end of input or, inside a range, the
+// matching {end}. It returns the input remaining after {end}.
+func parseNodes(s string, inRange bool) (nodes []jsonPathNode, rest string, err error) {
+	for s != "" {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: s, isText: true})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: s[:open], isText: true})
+		}
+
+		closing := findClosingBrace(s, open)
+		if closing < 0 {
+			return nil, "", fmt.Errorf("unclosed action in %q", s[open:])
+		}
+		expr := strings.TrimSpace(s[open+1 : closing])
+		s = s[closing+1:]
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("{end} without {range}")
+			}
+			return nodes, s, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			children, remaining, err := parseNodes(s, true)
+			if err != nil {
+				return nil, "", err
+			}
+			s = remaining
+			nodes = append(nodes, jsonPathNode{path: path, children: children, isRange: true})
+		case strings.HasPrefix(expr, `"`):
+			text, err := strconv.Unquote(expr)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid string literal %s", expr)
+			}
+			nodes = append(nodes, jsonPathNode{text: text, isText: true})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{path: path})
+		}
+	}
+
+	if inRange {
+		return nil, "", fmt.Errorf("{range} without {end}")
+	}
+	return nodes, "", nil
+}
+
+// findClosingBrace returns the index of the } closing the { at open,
+// skipping braces inside quoted strings
+func findClosingBrace(s string, open int) int {
+	var quote byte
+	for i := open + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+// parsePath parses a path expression such as $.items[0].name
+func parsePath(expr string) ([]pathStep, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(expr, "$"), "@")
+	var steps []pathStep
+
+	for s != "" {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			if strings.HasPrefix(s, ".") {
+				return nil, fmt.Errorf("recursive descent is not supported in %q", expr)
+			}
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			name := s[:end]
+			s = s[end:]
+			switch name {
+			case "":
+				// "{.}" refers to the current value
+			case "*":
+				steps = append(steps, pathStep{kind: stepWildcard})
+			default:
+				steps = append(steps, pathStep{kind: stepField, name: name})
+			}
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", expr)
+			}
+			step, err := parseBracket(s[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, expr)
+			}
+			steps = append(steps, step)
+			s = s[end+1:]
+		default:
+			// Allow a leading bare field name, e.g. "message"
+			if len(steps) > 0 {
+				return nil, fmt.Errorf("unexpected %q in %q", s, expr)
+			}
+			s = "." + s
+		}
+	}
+	return steps, nil
+}
+
+// parseBracket parses the content of a [...] step
+func parseBracket(content string) (pathStep, error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "*":
+		return pathStep{kind: stepWildcard}, nil
+	case len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0]:
+		return pathStep{kind: stepField, name: content[1 : len(content)-1]}, nil
+	case strings.Contains(content, ":"):
+		from, to, _ := strings.Cut(content, ":")
+		step := pathStep{kind: stepSlice}
+		if from = strings.TrimSpace(from); from != "" {
+			n, err := strconv.Atoi(from)
+			if err != nil {
+				return pathStep{}, fmt.Errorf("invalid slice start %q", from)
+			}
+			step.start = &n
+		}
+		if to = strings.TrimSpace(to); to != "" {
+			n, err := strconv.Atoi(to)
+			if err != nil {
+				return pathStep{}, fmt.Errorf("invalid slice end %q", to)
+			}
+			step.end = &n
+		}
+		return step, nil
+	default:
+		n, err := strconv.Atoi(content)
+		if err != nil {
+			return pathStep{}, fmt.Errorf("invalid index %q", content)
+		}
+		return pathStep{kind: stepIndex, index: n}, nil
+	}
+}
+
+// Execute renders the template against data decoded from JSON
+func (jp *JSONPath) Execute(w io.Writer, data any) error {
+	return executeNodes(w, jp.nodes, data)
+}
+
+func executeNodes(w io.Writer, nodes []jsonPathNode, current any) error {
+	for _, node := range nodes {
+		if node.isText {
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return err
+			}
+			continue
+		}
+
+		values := evalPath(node.path, current)
+		if node.isRange {
+			for _, v := range values {
+				if err := executeNodes(w, node.children, v); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		cells := make([]string, len(values))
+		for i, v := range values {
+			cells[i] = formatCell(v)
+		}
+		if _, err := io.WriteString(w, strings.Join(cells, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evalPath applies each step to every current value
+func evalPath(steps []pathStep, data any) []any {
+	values := []any{data}
+	for _, step := range steps {
+		var next []any
+		for _, v := range values {
+			next = append(next, applyStep(step, v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func applyStep(step pathStep, v any) []any {
+	switch step.kind {
+	case stepField:
+		if obj, ok := v.(map[string]any); ok {
+			if field, ok := obj[step.name]; ok {
+				return []any{field}
+			}
+		}
+	case stepIndex:
+		if list, ok := v.([]any); ok {
+			i := step.index
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				return []any{list[i]}
+			}
+		}
+	case stepSlice:
+		if list, ok := v.([]any); ok {
+			start, end := 0, len(list)
+			if step.start != nil {
+				start = clampIndex(*step.start, len(list))
+			}
+			if step.end != nil {
+				end = clampIndex(*step.end, len(list))
+			}
+			if start < end {
+				return list[start:end]
+			}
+		}
+	case stepWildcard:
+		switch val := v.(type) {
+		case []any:
+			return val
+		case map[string]any:
+			keys := make([]string, 0, len(val))
+			for key := range val {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			out := make([]any, len(keys))
+			for i, key := range keys {
+				out[i] = val[key]
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+// clampIndex resolves a possibly negative slice bound into [0, n]
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
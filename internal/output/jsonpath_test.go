@@ -0,0 +1,64 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	data := map[string]any{
+		"name": "greeting",
+		"items": []any{
+			map[string]any{"code": "en", "hello": "Hello"},
+			map[string]any{"code": "es", "hello": "Hola"},
+			map[string]any{"code": "fr", "hello": "Bonjour"},
+		},
+		"meta": map[string]any{"b": "2", "a": "1"},
+	}
+
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{expr: "{.name}", want: "greeting"},
+		{expr: ".name", want: "greeting"},
+		{expr: "name", want: "greeting"},
+		{expr: "{$.name}", want: "greeting"},
+		{expr: "{.items[0].code}", want: "en"},
+		{expr: "{.items[-1].code}", want: "fr"},
+		{expr: "{.items[*].code}", want: "en es fr"},
+		{expr: "{.items[1:].code}", want: "es fr"},
+		{expr: "{.items[0]['hello']}", want: "Hello"},
+		{expr: "{.meta.*}", want: "1 2"},
+		{expr: "{.missing}", want: ""},
+		{expr: "name={.name}!", want: "name=greeting!"},
+		{expr: `{range .items[*]}{.code}:{.hello}{"\n"}{end}`, want: "en:Hello\nes:Hola\nfr:Bonjour\n"},
+		{expr: "{.items[0]}", want: `{"code":"en","hello":"Hello"}`},
+		{expr: "{.name", wantErr: true},
+		{expr: "{end}", wantErr: true},
+		{expr: "{range .items[*]}{.code}", wantErr: true},
+		{expr: "{..code}", wantErr: true},
+		{expr: "{.items[x]}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			jp, err := ParseJSONPath(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJSONPath(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var buf bytes.Buffer
+			if err := jp.Execute(&buf, data); err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Execute() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
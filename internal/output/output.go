@@ -0,0 +1,271 @@
+// Package output renders command results in the format selected with the
+// global --output/-o flag, so every command offers the same contract to
+// scripts: text, json, yaml, ndjson, table, csv, go-template=... and
+// jsonpath=....
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.yaml.in/yaml/v3"
+)
+
+// Format identifies an output format
+type Format string
+
+// Supported output formats
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatNDJSON   Format = "ndjson"
+	FormatTable    Format = "table"
+	FormatCSV      Format = "csv"
+	FormatTemplate Format = "go-template"
+	FormatJSONPath Format = "jsonpath"
+)
+
+// FlagName is the name of the global output flag
+const FlagName = "output"
+
+// legacyJSONFlag is the per-command --json flag kept for compatibility
+const legacyJSONFlag = "json"
+
+// Tabler is implemented by values that define their own table and CSV columns
+type Tabler interface {
+	Header() []string
+	Row() []string
+}
+
+// Printer renders values in a single output format
+type Printer struct {
+	Format Format
+
+	tmpl     *template.Template
+	jsonPath *JSONPath
+}
+
+// Parse creates a printer from an --output value
+func Parse(spec string) (*Printer, error) {
+	name, arg, hasArg := strings.Cut(spec, "=")
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	p := &Printer{Format: format}
+
+	switch format {
+	case FormatText, FormatJSON, FormatYAML, FormatNDJSON, FormatTable, FormatCSV:
+		if hasArg {
+			return nil, invalidFormat(spec, fmt.Sprintf("%s does not take an argument", format))
+		}
+	case FormatTemplate:
+		if !hasArg {
+			return nil, invalidFormat(spec, "go-template requires a template, e.g. go-template={{.message}}")
+		}
+		tmpl, err := template.New("output").Parse(arg)
+		if err != nil {
+			return nil, invalidFormat(spec, fmt.Sprintf("invalid go-template: %v", err))
+		}
+		p.tmpl = tmpl
+	case FormatJSONPath:
+		if !hasArg {
+			return nil, invalidFormat(spec, "jsonpath requires an expression, e.g. jsonpath={.message}")
+		}
+		jp, err := ParseJSONPath(arg)
+		if err != nil {
+			return nil, invalidFormat(spec, fmt.Sprintf("invalid jsonpath: %v", err))
+		}
+		p.jsonPath = jp
+	default:
+		return nil, invalidFormat(spec,
+			"must be one of text, json, yaml, ndjson, table, csv, go-template=..., jsonpath=...")
+	}
+	return p, nil
+}
+
+func invalidFormat(spec, message string) error {
+	return &errors.ValidationError{Field: FlagName, Value: spec, Message: message}
+}
+
+// AddFlag registers the --output/-o flag on a flag set
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringP(FlagName, "o", string(FormatText),
+		"output format (text, json, yaml, ndjson, table, csv, go-template=..., jsonpath=...)")
+}
+
+// AddLegacyJSONFlag registers the deprecated per-command --json flag
+func AddLegacyJSONFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(legacyJSONFlag, false, "Output in JSON format")
+	_ = cmd.Flags().MarkDeprecated(legacyJSONFlag, "use --output json instead")
+}
+
+// FromCommand returns the printer selected by the command's flags. The
+// deprecated --json flag wins over --output so existing scripts keep working.
+func FromCommand(cmd *cobra.Command) (*Printer, error) {
+	if legacy, err := cmd.Flags().GetBool(legacyJSONFlag); err == nil && legacy {
+		return &Printer{Format: FormatJSON}, nil
+	}
+
+	spec := string(FormatText)
+	if f := cmd.Flags().Lookup(FlagName); f != nil {
+		spec = f.Value.String()
+	}
+	return Parse(spec)
+}
+
+// Print writes a single value to w. Lists are printed as one record per
+// element in the row-oriented formats (ndjson, table, csv).
+func (p *Printer) Print(w io.Writer, v any) error {
+	switch p.Format {
+	case FormatJSON:
+		return writeJSON(w, v)
+	case FormatYAML:
+		return writeYAML(w, v)
+	case FormatNDJSON:
+		items, ok := sliceItems(v)
+		if !ok {
+			items = []any{v}
+		}
+		enc := json.NewEncoder(w)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatTable:
+		return writeRows(newTableWriter(w), v)
+	case FormatCSV:
+		return writeRows(newCSVWriter(w), v)
+	case FormatTemplate, FormatJSONPath:
+		return p.writeExpression(w, v)
+	default:
+		return writeText(w, v)
+	}
+}
+
+// writeText writes the plain text form of a value. Values implementing
+// fmt.Stringer print their String(); lists print one element per line.
+func writeText(w io.Writer, v any) error {
+	if s, ok := v.(fmt.Stringer); ok {
+		_, err := fmt.Fprintln(w, s.String())
+		return err
+	}
+	if items, ok := sliceItems(v); ok {
+		for _, item := range items {
+			if err := writeText(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	_, err := fmt.Fprintln(w, v)
+	return err
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// writeYAML writes v as YAML using its JSON field names
+func writeYAML(w io.Writer, v any) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(yamlNumbers(generic))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeExpression evaluates the go-template or jsonpath expression against
+// the JSON form of v, ending the output with a newline if it has none
+func (p *Printer) writeExpression(w io.Writer, v any) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if p.tmpl != nil {
+		err = p.tmpl.Execute(&buf, generic)
+	} else {
+		err = p.jsonPath.Execute(&buf, generic)
+	}
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInvalidArgument, "failed to render %s output", p.Format)
+	}
+
+	if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// toGeneric converts v to maps, slices and scalars keyed by JSON field names
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// yamlNumbers replaces json.Number values so YAML renders them unquoted
+func yamlNumbers(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		for k, item := range val {
+			val[k] = yamlNumbers(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = yamlNumbers(item)
+		}
+	}
+	return v
+}
+
+// sliceItems returns the elements of v if it is a slice or array
+func sliceItems(v any) ([]any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, true
+}
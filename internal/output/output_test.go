@@ -0,0 +1,153 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+)
+
+type sample struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+func (s sample) String() string {
+	return "sample " + s.Name
+}
+
+type samples []sample
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Format
+		wantErr bool
+	}{
+		{spec: "text", want: FormatText},
+		{spec: "JSON", want: FormatJSON},
+		{spec: "yaml", want: FormatYAML},
+		{spec: "ndjson", want: FormatNDJSON},
+		{spec: "table", want: FormatTable},
+		{spec: "csv", want: FormatCSV},
+		{spec: "go-template={{.name}}", want: FormatTemplate},
+		{spec: "jsonpath={.name}", want: FormatJSONPath},
+		{spec: "xml", wantErr: true},
+		{spec: "json=x", wantErr: true},
+		{spec: "go-template", wantErr: true},
+		{spec: "go-template={{.name", wantErr: true},
+		{spec: "jsonpath={range .items}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, err := Parse(tt.spec)
+			if tt.wantErr {
+				if !errors.IsValidation(err) {
+					t.Fatalf("Parse(%q) error = %v, want ValidationError", tt.spec, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.spec, err)
+			}
+			if p.Format != tt.want {
+				t.Errorf("Parse(%q) format = %v, want %v", tt.spec, p.Format, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	one := sample{Name: "a", Count: 1, Tags: []string{"x", "y"}}
+	list := samples{{Name: "a", Count: 1}, {Name: "b", Count: 2}}
+
+	tests := []struct {
+		name string
+		spec string
+		v    any
+		want string
+	}{
+		{name: "text uses String", spec: "text", v: one, want: "sample a\n"},
+		{name: "text list", spec: "text", v: list, want: "sample a\nsample b\n"},
+		{name: "text scalar", spec: "text", v: "v1.0.0", want: "v1.0.0\n"},
+		{
+			name: "json",
+			spec: "json",
+			v:    one,
+			want: "{\n  \"name\": \"a\",\n  \"count\": 1,\n  \"tags\": [\n    \"x\",\n    \"y\"\n  ]\n}\n",
+		},
+		{name: "yaml", spec: "yaml", v: sample{Name: "a", Count: 1}, want: "count: 1\nname: a\n"},
+		{name: "ndjson single", spec: "ndjson", v: one, want: `{"name":"a","count":1,"tags":["x","y"]}` + "\n"},
+		{
+			name: "ndjson list",
+			spec: "ndjson",
+			v:    list,
+			want: `{"name":"a","count":1}` + "\n" + `{"name":"b","count":2}` + "\n",
+		},
+		{name: "table", spec: "table", v: list, want: "NAME  COUNT  TAGS\na     1      \nb     2      \n"},
+		{name: "csv single", spec: "csv", v: one, want: "name,count,tags\na,1,\"[\"\"x\"\",\"\"y\"\"]\"\n"},
+		{name: "csv scalar", spec: "csv", v: "dev", want: "value\ndev\n"},
+		{name: "go-template", spec: "go-template={{.name}}={{.count}}", v: one, want: "a=1\n"},
+		{name: "jsonpath", spec: "jsonpath={.tags[*]}", v: one, want: "x y\n"},
+		{
+			name: "jsonpath range keeps own newlines",
+			spec: `jsonpath={range [*]}{.name}{"\n"}{end}`,
+			v:    list,
+			want: "a\nb\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.spec, err)
+			}
+			var buf bytes.Buffer
+			if err := p.Print(&buf, tt.v); err != nil {
+				t.Fatalf("Print() unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Print() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: "text", want: "sample a\nsample b\n"},
+		{spec: "json", want: "[\n  {\n    \"name\": \"a\",\n    \"count\": 1\n  },\n  {\n    \"name\": \"b\",\n    \"count\": 2\n  }\n]\n"},
+		{spec: "yaml", want: "count: 1\nname: a\n---\ncount: 2\nname: b\n"},
+		{spec: "ndjson", want: `{"name":"a","count":1}` + "\n" + `{"name":"b","count":2}` + "\n"},
+		{spec: "csv", want: "name,count,tags\na,1,\nb,2,\n"},
+		{spec: "jsonpath={.name}", want: "a\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.spec, err)
+			}
+			var buf bytes.Buffer
+			s := p.NewStream(&buf)
+			for _, v := range []sample{{Name: "a", Count: 1}, {Name: "b", Count: 2}} {
+				if err := s.Write(v); err != nil {
+					t.Fatalf("Write() unexpected error: %v", err)
+				}
+			}
+			if err := s.Flush(); err != nil {
+				t.Fatalf("Flush() unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("stream output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
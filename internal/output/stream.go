@@ -0,0 +1,85 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Stream writes a sequence of records as they are produced, such as the
+// results of a batch. Call Flush once after the last record.
+type Stream interface {
+	Write(v any) error
+	Flush() error
+}
+
+// NewStream creates a stream writing records to w in the printer's format.
+// JSON output collects the records into a single array, YAML output
+// separates them as documents, and the other formats emit each record as
+// soon as it is written.
+func (p *Printer) NewStream(w io.Writer) Stream {
+	switch p.Format {
+	case FormatJSON:
+		return &jsonStream{w: w, items: []any{}}
+	case FormatYAML:
+		return &yamlStream{w: w}
+	case FormatNDJSON:
+		return &funcStream{write: json.NewEncoder(w).Encode}
+	case FormatTable:
+		return newTableWriter(w)
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatTemplate, FormatJSONPath:
+		return &funcStream{write: func(v any) error { return p.writeExpression(w, v) }}
+	default:
+		return &funcStream{write: func(v any) error { return writeText(w, v) }}
+	}
+}
+
+// funcStream writes each record immediately
+type funcStream struct {
+	write func(v any) error
+}
+
+func (s *funcStream) Write(v any) error {
+	return s.write(v)
+}
+
+func (s *funcStream) Flush() error {
+	return nil
+}
+
+// jsonStream collects records and writes them as one JSON array
+type jsonStream struct {
+	w     io.Writer
+	items []any
+}
+
+func (s *jsonStream) Write(v any) error {
+	s.items = append(s.items, v)
+	return nil
+}
+
+func (s *jsonStream) Flush() error {
+	return writeJSON(s.w, s.items)
+}
+
+// yamlStream writes each record as a separate YAML document
+type yamlStream struct {
+	w       io.Writer
+	written bool
+}
+
+func (s *yamlStream) Write(v any) error {
+	if s.written {
+		if _, err := fmt.Fprintln(s.w, "---"); err != nil {
+			return err
+		}
+	}
+	s.written = true
+	return writeYAML(s.w, v)
+}
+
+func (s *yamlStream) Flush() error {
+	return nil
+}
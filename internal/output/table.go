@@ -0,0 +1,194 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// scalarColumn is the column name used for values that are not objects
+const scalarColumn = "value"
+
+// tableWriter renders rows as aligned columns with an upper-case header
+type tableWriter struct {
+	tw         *tabwriter.Writer
+	header     []string
+	wroteTitle bool
+}
+
+func newTableWriter(w io.Writer) *tableWriter {
+	return &tableWriter{tw: tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)}
+}
+
+func (t *tableWriter) Write(v any) error {
+	header, row, err := rowOf(v, t.header)
+	if err != nil {
+		return err
+	}
+	if !t.wroteTitle {
+		t.header = header
+		t.wroteTitle = true
+		titles := make([]string, len(header))
+		for i, col := range header {
+			titles[i] = strings.ToUpper(col)
+		}
+		if _, err := fmt.Fprintln(t.tw, strings.Join(titles, "\t")); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(t.tw, strings.Join(row, "\t"))
+	return err
+}
+
+func (t *tableWriter) Flush() error {
+	return t.tw.Flush()
+}
+
+// csvWriter renders rows as CSV with a header line
+type csvWriter struct {
+	w          *csv.Writer
+	header     []string
+	wroteTitle bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(v any) error {
+	header, row, err := rowOf(v, c.header)
+	if err != nil {
+		return err
+	}
+	if !c.wroteTitle {
+		c.header = header
+		c.wroteTitle = true
+		if err := c.w.Write(header); err != nil {
+			return err
+		}
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// writeRows writes every row of v, treating a slice as one row per element
+func writeRows(rw Stream, v any) error {
+	items, ok := sliceItems(v)
+	if !ok {
+		items = []any{v}
+	}
+	for _, item := range items {
+		if err := rw.Write(item); err != nil {
+			return err
+		}
+	}
+	return rw.Flush()
+}
+
+// rowOf returns the columns and cell values of a single value. When header
+// is non-nil the cells follow it, so later rows line up with the first.
+func rowOf(v any, header []string) (cols, row []string, err error) {
+	if t, ok := v.(Tabler); ok {
+		return t.Header(), t.Row(), nil
+	}
+
+	generic, err := toGeneric(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, ok := generic.(map[string]any)
+	if !ok {
+		return []string{scalarColumn}, []string{formatCell(generic)}, nil
+	}
+
+	cols = header
+	if cols == nil {
+		cols = columns(reflect.TypeOf(v))
+		cols = appendMissingKeys(cols, obj)
+	}
+
+	row = make([]string, len(cols))
+	for i, col := range cols {
+		row[i] = formatCell(obj[col])
+	}
+	return cols, row, nil
+}
+
+// columns returns the JSON field names of a struct type in declaration
+// order, following embedded structs
+func columns(t reflect.Type) []string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var cols []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			cols = append(cols, columns(field.Type)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		cols = append(cols, name)
+	}
+	return cols
+}
+
+// appendMissingKeys adds keys present in obj but not in cols, sorted
+func appendMissingKeys(cols []string, obj map[string]any) []string {
+	known := make(map[string]bool, len(cols))
+	for _, col := range cols {
+		known[col] = true
+	}
+
+	var extra []string
+	for key := range obj {
+		if !known[key] {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	return append(cols, extra...)
+}
+
+// formatCell renders a JSON value as a single table cell
+func formatCell(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return fmt.Sprintf("%t", val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(data)
+	}
+}
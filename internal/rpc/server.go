@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	greetingv1 "github.com/go-cli-template/hello-world-cli/api/greeting/v1"
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/greeting"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Config holds gRPC server configuration
+type Config struct {
+	Addr            string        // Listen address, e.g. ":9090"
+	ShutdownTimeout time.Duration // Grace period for in-flight RPCs
+	Fallbacks       []string      // Languages to try when negotiation fails
+
+	// DefaultLanguage returns the language for requests that name none;
+	// nil or "" uses the fallbacks
+	DefaultLanguage func() string
+
+	Clock    func() time.Time  // Returns the current time; defaults to time.Now
+	Calendar greeting.Calendar // Holiday source; nil disables holiday greetings
+}
+
+// DefaultConfig returns default gRPC server configuration
+func DefaultConfig() Config {
+	return Config{
+		Addr:            ":9090",
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// Server hosts the greeting service with health checking and reflection
+type Server struct {
+	cfg    Config
+	log    logger.Logger
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+// New creates a new gRPC server
+func New(cfg Config, log logger.Logger) *Server {
+	s := &Server{
+		cfg:    cfg,
+		log:    log,
+		health: health.NewServer(),
+	}
+
+	s.grpc = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.logUnary),
+		grpc.ChainStreamInterceptor(s.logStream),
+	)
+	svc := NewService(log, cfg.Fallbacks)
+	svc.clock = cfg.Clock
+	svc.calendar = cfg.Calendar
+	svc.defaultLanguage = cfg.DefaultLanguage
+	greetingv1.RegisterGreetingServiceServer(s.grpc, svc)
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	reflection.Register(s.grpc)
+
+	// Not serving until Serve is called
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	s.health.SetServingStatus(greetingv1.GreetingService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return s
+}
+
+// Run listens on the configured address and serves until ctx is canceled,
+// then shuts down gracefully
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return &errors.NetworkError{URL: s.cfg.Addr, Operation: "listen", Err: err}
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve serves on ln until ctx is canceled, then shuts down gracefully
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	// The listener is already bound, so clients can connect as soon as we
+	// report SERVING
+	s.health.Resume()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.grpc.Serve(ln)
+	}()
+
+	s.log.Info("grpc server listening", "addr", ln.Addr().String())
+
+	select {
+	case err := <-serveErr:
+		s.health.Shutdown()
+		return errors.Wrap(err, errors.CodeNetwork, "grpc server stopped unexpectedly")
+	case <-ctx.Done():
+	}
+
+	// Report NOT_SERVING first so clients stop sending traffic
+	s.health.Shutdown()
+	s.log.Info("shutting down grpc server", "timeout", s.cfg.ShutdownTimeout)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(s.cfg.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+	case <-timer.C:
+		s.grpc.Stop()
+		return errors.New(errors.CodeTimeout, "grpc server did not shut down cleanly")
+	}
+
+	if err := <-serveErr; err != nil {
+		return errors.Wrap(err, errors.CodeNetwork, "grpc server stopped unexpectedly")
+	}
+
+	s.log.Info("grpc server stopped")
+	return nil
+}
+
+// logUnary logs every unary RPC with its status and duration
+func (s *Server) logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	s.logRPC(info.FullMethod, start, err)
+	return resp, err
+}
+
+// logStream logs every streaming RPC with its status and duration
+func (s *Server) logStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	s.logRPC(info.FullMethod, start, err)
+	return err
+}
+
+func (s *Server) logRPC(method string, start time.Time, err error) {
+	s.log.Info("grpc request",
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+	)
+}
@@ -0,0 +1,246 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	greetingv1 "github.com/go-cli-template/hello-world-cli/api/greeting/v1"
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// nopLogger discards all log messages
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                         {}
+func (nopLogger) Info(string, ...any)                          {}
+func (nopLogger) Warn(string, ...any)                          {}
+func (nopLogger) Error(string, ...any)                         {}
+func (nopLogger) DebugContext(context.Context, string, ...any) {}
+func (nopLogger) InfoContext(context.Context, string, ...any)  {}
+func (nopLogger) WarnContext(context.Context, string, ...any)  {}
+func (nopLogger) ErrorContext(context.Context, string, ...any) {}
+func (l nopLogger) With(...any) logger.Logger                  { return l }
+func (l nopLogger) WithError(error) logger.Logger              { return l }
+
+// testTime is the fixed time test servers greet at, at night in UTC
+var testTime = time.Date(2026, time.March, 10, 23, 0, 0, 0, time.UTC)
+
+// startServer serves on an in-memory listener and returns a client
+// connection to it; the server is shut down when the test ends
+func startServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	ln := bufconn.Listen(1 << 20)
+	cfg := DefaultConfig()
+	cfg.Clock = func() time.Time { return testTime }
+	srv := New(cfg, nopLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	})
+	return conn
+}
+
+func TestGenerate(t *testing.T) {
+	client := greetingv1.NewGreetingServiceClient(startServer(t))
+
+	tests := []struct {
+		name           string
+		opts           *greetingv1.Options
+		wantMessage    string
+		wantLanguage   string
+		wantConfidence greetingv1.Confidence
+	}{
+		{
+			name:           "no options",
+			wantMessage:    "Hello, World!",
+			wantLanguage:   "en",
+			wantConfidence: greetingv1.Confidence_CONFIDENCE_NONE,
+		},
+		{
+			name:           "name and language",
+			opts:           &greetingv1.Options{Name: "Ana", Language: "es-MX"},
+			wantMessage:    "¡Buenas noches, Ana!",
+			wantLanguage:   "es",
+			wantConfidence: greetingv1.Confidence_CONFIDENCE_HIGH,
+		},
+		{
+			name:           "group with gender",
+			opts:           &greetingv1.Options{Names: []string{"Ana", "Eva"}, Gender: "female", Language: "es"},
+			wantMessage:    "¡Buenas noches a todas, Ana y Eva!",
+			wantLanguage:   "es",
+			wantConfidence: greetingv1.Confidence_CONFIDENCE_EXACT,
+		},
+		{
+			name:           "fallbacks",
+			opts:           &greetingv1.Options{Language: "gl", Fallbacks: []string{"fr"}},
+			wantMessage:    "Bonsoir le monde!",
+			wantLanguage:   "fr",
+			wantConfidence: greetingv1.Confidence_CONFIDENCE_NONE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Generate(context.Background(), &greetingv1.GenerateRequest{Options: tt.opts})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			greet := resp.GetGreeting()
+			if tt.wantMessage != "" && greet.GetMessage() != tt.wantMessage {
+				t.Errorf("message = %q, want %q", greet.GetMessage(), tt.wantMessage)
+			}
+			if greet.GetLanguage() != tt.wantLanguage {
+				t.Errorf("language = %q, want %q", greet.GetLanguage(), tt.wantLanguage)
+			}
+			if greet.GetConfidence() != tt.wantConfidence {
+				t.Errorf("confidence = %v, want %v", greet.GetConfidence(), tt.wantConfidence)
+			}
+			if !greet.GetTimestamp().AsTime().Equal(testTime) {
+				t.Errorf("timestamp = %v, want %v", greet.GetTimestamp().AsTime(), testTime)
+			}
+			if greet.GetPeriod() != "night" {
+				t.Errorf("period = %q, want night", greet.GetPeriod())
+			}
+		})
+	}
+}
+
+func TestGenerateInvalidLanguage(t *testing.T) {
+	client := greetingv1.NewGreetingServiceClient(startServer(t))
+
+	_, err := client.Generate(context.Background(), &greetingv1.GenerateRequest{
+		Options: &greetingv1.Options{Language: "C"},
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %v, want %v", st.Code(), codes.InvalidArgument)
+	}
+
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("details = %v, want one ErrorInfo", details)
+	}
+	if info, ok := details[0].(*errdetails.ErrorInfo); !ok || info.GetReason() != string(errors.CodeValidation) {
+		t.Errorf("details[0] = %v, want ErrorInfo with reason %s", details[0], errors.CodeValidation)
+	}
+}
+
+func TestListLanguages(t *testing.T) {
+	client := greetingv1.NewGreetingServiceClient(startServer(t))
+
+	resp, err := client.ListLanguages(context.Background(), &greetingv1.ListLanguagesRequest{})
+	if err != nil {
+		t.Fatalf("ListLanguages() error = %v", err)
+	}
+
+	found := false
+	for _, lang := range resp.GetLanguages() {
+		if lang.GetCode() == "ja" {
+			found = lang.GetName() == "日本語" && lang.GetHello() != ""
+		}
+	}
+	if !found {
+		t.Errorf("ListLanguages() = %v, want ja with name and hello", resp.GetLanguages())
+	}
+}
+
+func TestBatchGenerate(t *testing.T) {
+	client := greetingv1.NewGreetingServiceClient(startServer(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.BatchGenerate(ctx)
+	if err != nil {
+		t.Fatalf("BatchGenerate() error = %v", err)
+	}
+
+	requests := []*greetingv1.Options{
+		{Name: "Ana", Language: "es"},
+		{Name: "Bob", Language: "not a tag"},
+		{Name: "Chloé", Language: "fr"},
+	}
+	for _, opts := range requests {
+		if err := stream.Send(&greetingv1.GenerateRequest{Options: opts}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend() error = %v", err)
+	}
+
+	var responses []*greetingv1.BatchGenerateResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		responses = append(responses, resp)
+	}
+
+	if len(responses) != len(requests) {
+		t.Fatalf("got %d responses, want %d", len(responses), len(requests))
+	}
+	for i, resp := range responses {
+		if resp.GetIndex() != int64(i) {
+			t.Errorf("responses[%d].Index = %d", i, resp.GetIndex())
+		}
+	}
+	if got := responses[0].GetGreeting().GetMessage(); got != "¡Buenas noches, Ana!" {
+		t.Errorf("responses[0] message = %q", got)
+	}
+	if code := codes.Code(responses[1].GetError().GetCode()); code != codes.InvalidArgument {
+		t.Errorf("responses[1] error code = %v, want %v", code, codes.InvalidArgument)
+	}
+	if got := responses[2].GetGreeting().GetMessage(); got != "Bonsoir, Chloé!" {
+		t.Errorf("responses[2] message = %q", got)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	client := healthpb.NewHealthClient(startServer(t))
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: greetingv1.GreetingService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", resp.GetStatus())
+	}
+}
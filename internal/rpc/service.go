@@ -0,0 +1,158 @@
+// Package rpc exposes the greeting engine as a gRPC service.
+package rpc
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"time"
+
+	greetingv1 "github.com/go-cli-template/hello-world-cli/api/greeting/v1"
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/greeting"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// confidenceToProto maps greeting confidence levels to their protobuf enum
+var confidenceToProto = map[greeting.Confidence]greetingv1.Confidence{
+	greeting.ConfidenceExact: greetingv1.Confidence_CONFIDENCE_EXACT,
+	greeting.ConfidenceHigh:  greetingv1.Confidence_CONFIDENCE_HIGH,
+	greeting.ConfidenceLow:   greetingv1.Confidence_CONFIDENCE_LOW,
+	greeting.ConfidenceNone:  greetingv1.Confidence_CONFIDENCE_NONE,
+}
+
+// Service implements greetingv1.GreetingServiceServer
+type Service struct {
+	greetingv1.UnimplementedGreetingServiceServer
+
+	log       logger.Logger
+	fallbacks []string
+	clock     func() time.Time
+	calendar  greeting.Calendar
+
+	defaultLanguage func() string
+}
+
+// NewService creates a greeting service. Fallbacks are used for requests
+// that do not name any of their own.
+func NewService(log logger.Logger, fallbacks []string) *Service {
+	return &Service{log: log, fallbacks: fallbacks}
+}
+
+// Generate creates a single greeting
+func (s *Service) Generate(_ context.Context, req *greetingv1.GenerateRequest) (*greetingv1.GenerateResponse, error) {
+	greet, err := s.generate(req)
+	if err != nil {
+		return nil, errors.GRPCStatus(err).Err()
+	}
+	return &greetingv1.GenerateResponse{Greeting: greet}, nil
+}
+
+// ListLanguages returns every supported language
+func (s *Service) ListLanguages(context.Context, *greetingv1.ListLanguagesRequest) (*greetingv1.ListLanguagesResponse, error) {
+	langs := greeting.DescribeLanguages()
+	resp := &greetingv1.ListLanguagesResponse{
+		Languages: make([]*greetingv1.Language, len(langs)),
+	}
+	for i, lang := range langs {
+		resp.Languages[i] = &greetingv1.Language{Code: lang.Code, Name: lang.Name, Hello: lang.Hello}
+	}
+	return resp, nil
+}
+
+// BatchGenerate answers every request on the stream in order. Invalid
+// requests get an error result; only transport failures end the stream.
+func (s *Service) BatchGenerate(stream greetingv1.GreetingService_BatchGenerateServer) error {
+	var index, failed int64
+	for {
+		req, err := stream.Recv()
+		if stderrors.Is(err, io.EOF) {
+			s.log.Debug("batch complete", "records", index, "failed", failed)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &greetingv1.BatchGenerateResponse{Index: index}
+		if greet, genErr := s.generate(req); genErr != nil {
+			failed++
+			resp.Result = &greetingv1.BatchGenerateResponse_Error{Error: errors.GRPCStatus(genErr).Proto()}
+		} else {
+			resp.Result = &greetingv1.BatchGenerateResponse_Greeting{Greeting: greet}
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		index++
+	}
+}
+
+// generate validates a request and generates its greeting
+func (s *Service) generate(req *greetingv1.GenerateRequest) (*greetingv1.Greeting, error) {
+	opts, err := s.options(req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	greet := greeting.Generate(opts)
+	s.log.Debug("generated greeting",
+		"language", greet.Language,
+		"confidence", greet.Confidence,
+	)
+	return toProto(greet), nil
+}
+
+// options converts and validates request options
+func (s *Service) options(in *greetingv1.Options) (greeting.Options, error) {
+	opts := greeting.Options{
+		Name:         in.GetName(),
+		Names:        in.GetNames(),
+		Language:     in.GetLanguage(),
+		Fallbacks:    in.GetFallbacks(),
+		IncludeEmoji: in.GetIncludeEmoji(),
+		Clock:        s.clock,
+		Calendar:     s.calendar,
+	}
+
+	gender, err := greeting.ParseGender(in.GetGender())
+	if err != nil {
+		return opts, &errors.ValidationError{Field: "gender", Value: in.GetGender(), Message: err.Error()}
+	}
+	opts.Gender = gender
+
+	if opts.Language != "" {
+		if _, err := greeting.ParseTag(opts.Language); err != nil {
+			return opts, &errors.ValidationError{Field: "language", Value: opts.Language, Message: err.Error()}
+		}
+	}
+	for _, fallback := range opts.Fallbacks {
+		if _, err := greeting.ParseTag(fallback); err != nil {
+			return opts, &errors.ValidationError{Field: "fallbacks", Value: fallback, Message: err.Error()}
+		}
+	}
+	if len(opts.Fallbacks) == 0 {
+		opts.Fallbacks = s.fallbacks
+	}
+	if opts.Language == "" && s.defaultLanguage != nil {
+		opts.Language = s.defaultLanguage()
+	}
+
+	return opts, nil
+}
+
+// toProto converts a greeting to its protobuf message
+func toProto(g *greeting.Greeting) *greetingv1.Greeting {
+	return &greetingv1.Greeting{
+		Message:           g.Message,
+		Language:          g.Language,
+		RequestedLanguage: g.RequestedLanguage,
+		Confidence:        confidenceToProto[g.Confidence],
+		Emoji:             g.Emoji,
+		Timestamp:         timestamppb.New(g.Timestamp),
+		Period:            g.Period,
+		Holiday:           g.Holiday,
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+)
+
+// logLevelPath is the route of the admin API for log levels
+const logLevelPath = "/admin/log-level"
+
+// logLevelBody is the JSON body describing the log levels
+type logLevelBody struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+	Debug      bool              `json:"debug"`
+}
+
+// logLevelUpdate is the JSON body changing the log levels; fields left
+// out keep their value and a null component level removes the override
+type logLevelUpdate struct {
+	Level      *string            `json:"level"`
+	Components map[string]*string `json:"components"`
+	Debug      *bool              `json:"debug"`
+}
+
+// handleGetLogLevel serves GET /admin/log-level
+func (s *Server) handleGetLogLevel(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.logLevels())
+}
+
+// handlePutLogLevel serves PUT /admin/log-level, changing the levels given
+// and answering with all of them
+func (s *Server) handlePutLogLevel(w http.ResponseWriter, r *http.Request) {
+	var update logLevelUpdate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&update); err != nil {
+		s.writeError(w, r, &errors.ValidationError{Field: "body", Message: "must be a JSON object with level, components or debug: " + err.Error()})
+		return
+	}
+
+	// Check every level before changing any
+	if update.Level != nil {
+		if _, err := logger.ParseLevel(*update.Level); err != nil {
+			s.writeError(w, r, &errors.ValidationError{Field: "level", Value: *update.Level, Message: err.Error()})
+			return
+		}
+	}
+	names := make([]string, 0, len(update.Components))
+	for name, level := range update.Components {
+		if level != nil {
+			if _, err := logger.ParseLevel(*level); err != nil {
+				s.writeError(w, r, &errors.ValidationError{Field: "components." + name, Value: *level, Message: err.Error()})
+				return
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	levels := s.cfg.Levels
+	if update.Level != nil {
+		level, _ := logger.ParseLevel(*update.Level)
+		levels.SetLevel(level)
+	}
+	for _, name := range names {
+		if update.Components[name] == nil {
+			levels.ResetComponent(name)
+			continue
+		}
+		level, _ := logger.ParseLevel(*update.Components[name])
+		levels.SetComponent(name, level)
+	}
+	if update.Debug != nil {
+		levels.SetDebug(*update.Debug)
+	}
+
+	s.log.Warn("log levels changed", "levels", levels.String(), "debug", levels.Debugging(), "remote", r.RemoteAddr)
+	s.writeJSON(w, http.StatusOK, s.logLevels())
+}
+
+// logLevels describes the current log levels
+func (s *Server) logLevels() logLevelBody {
+	levels := s.cfg.Levels
+	body := logLevelBody{
+		Level:      logger.LevelName(levels.Level()),
+		Components: make(map[string]string),
+		Debug:      levels.Debugging(),
+	}
+	for name, level := range levels.Components() {
+		body.Components[name] = logger.LevelName(level)
+	}
+	return body
+}
@@ -0,0 +1,369 @@
+// Package server exposes the greeting engine over HTTP.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/greeting"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+	"github.com/go-cli-template/hello-world-cli/pkg/version"
+	"golang.org/x/text/language"
+)
+
+// Config holds server configuration
+type Config struct {
+	Addr              string        // Listen address, e.g. ":8080"
+	ReadHeaderTimeout time.Duration // Limit for reading request headers
+	ShutdownTimeout   time.Duration // Grace period for in-flight requests
+	Fallbacks         []string      // Languages to try when negotiation fails
+
+	// DefaultLanguage returns the language for requests that name none and
+	// send no usable Accept-Language header; nil or "" uses the fallbacks
+	DefaultLanguage func() string
+
+	Clock    func() time.Time  // Returns the current time; defaults to time.Now
+	Calendar greeting.Calendar // Holiday source; nil disables holiday greetings
+
+	// Levels are read and changed through /admin/log-level, served on
+	// AdminAddr rather than Addr; nil disables the admin API
+	Levels    *logger.Levels
+	AdminAddr string // Listen address of the admin API, e.g. "127.0.0.1:8081"
+}
+
+// DefaultConfig returns default server configuration. The admin API
+// listens on loopback only, since it has no authentication.
+func DefaultConfig() Config {
+	return Config{
+		Addr:              ":8080",
+		AdminAddr:         "127.0.0.1:8081",
+		ReadHeaderTimeout: 5 * time.Second,
+		ShutdownTimeout:   10 * time.Second,
+	}
+}
+
+// Server serves the greeting REST API
+type Server struct {
+	cfg   Config
+	log   logger.Logger
+	ready atomic.Bool
+}
+
+// New creates a new server
+func New(cfg Config, log logger.Logger) *Server {
+	return &Server{cfg: cfg, log: log}
+}
+
+// errorBody is the JSON body returned for failed requests
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    errors.ErrorCode `json:"code"`
+	Message string           `json:"message"`
+}
+
+// Handler returns the HTTP handler with all routes and request logging
+func (s *Server) Handler() http.Handler {
+	routes := map[string]http.HandlerFunc{
+		"/v1/greet":     s.handleGreet,
+		"/v1/languages": s.handleLanguages,
+		"/version":      s.handleVersion,
+		"/healthz":      s.handleHealth,
+		"/readyz":       s.handleReady,
+	}
+
+	mux := http.NewServeMux()
+	for path, handler := range routes {
+		mux.HandleFunc("GET "+path, handler)
+		mux.HandleFunc(path, s.handleMethodNotAllowed)
+	}
+	mux.HandleFunc("/", s.handleNotFound)
+	return s.logRequests(mux)
+}
+
+// AdminHandler returns the HTTP handler of the admin API with request
+// logging, or nil if it is disabled
+func (s *Server) AdminHandler() http.Handler {
+	if s.cfg.Levels == nil {
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+logLevelPath, s.handleGetLogLevel)
+	mux.HandleFunc("PUT "+logLevelPath, s.handlePutLogLevel)
+	mux.HandleFunc(logLevelPath, s.handleMethodNotAllowed)
+	mux.HandleFunc("/", s.handleNotFound)
+	return s.logRequests(mux)
+}
+
+// Run listens on the configured addresses and serves until ctx is
+// canceled, then shuts down gracefully
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return &errors.NetworkError{URL: s.cfg.Addr, Operation: "listen", Err: err}
+	}
+	var adminLn net.Listener
+	if s.cfg.Levels != nil {
+		if adminLn, err = net.Listen("tcp", s.cfg.AdminAddr); err != nil {
+			_ = ln.Close()
+			return &errors.NetworkError{URL: s.cfg.AdminAddr, Operation: "listen", Err: err}
+		}
+	}
+	return s.Serve(ctx, ln, adminLn)
+}
+
+// Serve serves the API on ln, and the admin API on adminLn if not nil,
+// until ctx is canceled, then shuts down gracefully
+func (s *Server) Serve(ctx context.Context, ln, adminLn net.Listener) error {
+	servers := map[*http.Server]net.Listener{s.newHTTPServer(ctx, s.Handler()): ln}
+	if adminLn != nil {
+		servers[s.newHTTPServer(ctx, s.AdminHandler())] = adminLn
+	}
+
+	serveErr := make(chan error, len(servers))
+	for srv, l := range servers {
+		go func() {
+			serveErr <- srv.Serve(l)
+		}()
+	}
+
+	s.ready.Store(true)
+	s.log.Info("server listening", "addr", ln.Addr().String())
+	if adminLn != nil {
+		s.log.Warn("admin API listening without authentication", "addr", adminLn.Addr().String())
+	}
+
+	running := len(servers)
+	select {
+	case err := <-serveErr:
+		s.ready.Store(false)
+		running--
+		for srv := range servers {
+			_ = srv.Close()
+		}
+		for ; running > 0; running-- {
+			<-serveErr
+		}
+		return errors.Wrap(err, errors.CodeNetwork, "server stopped unexpectedly")
+	case <-ctx.Done():
+	}
+
+	// Fail readiness first so load balancers stop sending traffic
+	s.ready.Store(false)
+	s.log.Info("shutting down server", "timeout", s.cfg.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cfg.ShutdownTimeout)
+	defer cancel()
+	var shutdownErr error
+	for srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil && shutdownErr == nil {
+			shutdownErr = errors.Wrap(err, errors.CodeTimeout, "server did not shut down cleanly")
+		}
+	}
+	if shutdownErr != nil {
+		return shutdownErr
+	}
+	for ; running > 0; running-- {
+		if err := <-serveErr; err != nil && !stderrors.Is(err, http.ErrServerClosed) {
+			return errors.Wrap(err, errors.CodeNetwork, "server stopped unexpectedly")
+		}
+	}
+
+	s.log.Info("server stopped")
+	return nil
+}
+
+// newHTTPServer returns an HTTP server for handler whose requests carry
+// the values of ctx
+func (s *Server) newHTTPServer(ctx context.Context, handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: s.cfg.ReadHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return context.WithoutCancel(ctx) },
+	}
+}
+
+// handleGreet serves GET /v1/greet?name=&lang=&emoji=&gender=; name may be
+// repeated to greet a group
+func (s *Server) handleGreet(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	opts := greeting.Options{
+		Names:     query["name"],
+		Language:  query.Get("lang"),
+		Fallbacks: s.cfg.Fallbacks,
+		Clock:     s.cfg.Clock,
+		Calendar:  s.cfg.Calendar,
+	}
+
+	gender, err := greeting.ParseGender(query.Get("gender"))
+	if err != nil {
+		s.writeError(w, r, &errors.ValidationError{Field: "gender", Value: query.Get("gender"), Message: err.Error()})
+		return
+	}
+	opts.Gender = gender
+
+	if value := query.Get("emoji"); value != "" {
+		emoji, err := strconv.ParseBool(value)
+		if err != nil {
+			s.writeError(w, r, &errors.ValidationError{Field: "emoji", Value: value, Message: "must be true or false"})
+			return
+		}
+		opts.IncludeEmoji = emoji
+	}
+
+	if opts.Language != "" {
+		if _, err := greeting.ParseTag(opts.Language); err != nil {
+			s.writeError(w, r, &errors.ValidationError{Field: "lang", Value: opts.Language, Message: err.Error()})
+			return
+		}
+	} else {
+		opts.Language = negotiateLanguage(r.Header.Get("Accept-Language"))
+		if opts.Language == "" && s.cfg.DefaultLanguage != nil {
+			opts.Language = s.cfg.DefaultLanguage()
+		}
+	}
+
+	greet := greeting.Generate(opts)
+	s.log.Debug("generated greeting",
+		"language", greet.Language,
+		"confidence", greet.Confidence,
+	)
+
+	w.Header().Set("Content-Language", greet.Language)
+	w.Header().Add("Vary", "Accept-Language")
+	s.writeJSON(w, http.StatusOK, greet)
+}
+
+// handleLanguages serves GET /v1/languages
+func (s *Server) handleLanguages(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, greeting.DescribeLanguages())
+}
+
+// handleVersion serves GET /version
+func (s *Server) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, version.GetBuildInfo())
+}
+
+// handleHealth serves GET /healthz; the process is alive if it can answer
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady serves GET /readyz; not ready before startup and during shutdown
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		err := errors.New(errors.CodeResourceExhausted, "server is not ready")
+		s.writeErrorStatus(w, r, http.StatusServiceUnavailable, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// handleNotFound answers unknown routes with a JSON error
+func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
+	s.writeError(w, r, errors.New(errors.CodeNotFound, "no route for "+r.Method+" "+r.URL.Path))
+}
+
+// handleMethodNotAllowed answers known routes requested with the wrong method
+func (s *Server) handleMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	allow := "GET, HEAD"
+	if r.URL.Path == logLevelPath {
+		allow += ", PUT"
+	}
+	w.Header().Set("Allow", allow)
+	err := errors.New(errors.CodeInvalidArgument, "method "+r.Method+" not allowed for "+r.URL.Path)
+	s.writeErrorStatus(w, r, http.StatusMethodNotAllowed, err)
+}
+
+// negotiateLanguage picks the best supported language from an
+// Accept-Language header, or "" to use the fallbacks
+func negotiateLanguage(header string) string {
+	if header == "" {
+		return ""
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return ""
+	}
+
+	requested := make([]string, len(tags))
+	for i, tag := range tags {
+		requested[i] = tag.String()
+	}
+	return greeting.DefaultCatalog().Preferred(requested)
+}
+
+// writeJSON writes v as a JSON response
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.log.Warn("failed to write response", "error", err)
+	}
+}
+
+// writeError writes an error as a JSON body with the matching HTTP status
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	s.writeErrorStatus(w, r, errors.HTTPStatus(err), err)
+}
+
+// writeErrorStatus writes an error as a JSON body with an explicit status
+func (s *Server) writeErrorStatus(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		s.log.Error("request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+
+	s.writeJSON(w, status, errorBody{Error: errorDetail{
+		Code:    errors.GetCode(err),
+		Message: errors.Message(err),
+	}})
+}
+
+// statusRecorder captures the response status for request logging
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// logRequests logs every request with its status and duration
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		s.log.Info("http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration", time.Since(start),
+			"remote", r.RemoteAddr,
+		)
+	})
+}
@@ -0,0 +1,370 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+)
+
+// bufferLogger is a logger that writes messages and fields to a buffer
+type bufferLogger struct {
+	buf    *bytes.Buffer
+	fields []any
+}
+
+func (l *bufferLogger) log(msg string, fields ...any) {
+	fmt.Fprintln(l.buf, msg, append(l.fields, fields...))
+}
+
+func (l *bufferLogger) Debug(msg string, fields ...any) { l.log(msg, fields...) }
+func (l *bufferLogger) Info(msg string, fields ...any)  { l.log(msg, fields...) }
+func (l *bufferLogger) Warn(msg string, fields ...any)  { l.log(msg, fields...) }
+func (l *bufferLogger) Error(msg string, fields ...any) { l.log(msg, fields...) }
+
+func (l *bufferLogger) DebugContext(_ context.Context, msg string, fields ...any) {
+	l.log(msg, fields...)
+}
+
+func (l *bufferLogger) InfoContext(_ context.Context, msg string, fields ...any) {
+	l.log(msg, fields...)
+}
+
+func (l *bufferLogger) WarnContext(_ context.Context, msg string, fields ...any) {
+	l.log(msg, fields...)
+}
+
+func (l *bufferLogger) ErrorContext(_ context.Context, msg string, fields ...any) {
+	l.log(msg, fields...)
+}
+
+func (l *bufferLogger) With(fields ...any) logger.Logger {
+	return &bufferLogger{buf: l.buf, fields: append(l.fields, fields...)}
+}
+
+func (l *bufferLogger) WithError(err error) logger.Logger {
+	return l.With("error", err)
+}
+
+// testTime is the fixed time test servers greet at, at night in UTC
+var testTime = time.Date(2026, time.March, 10, 23, 0, 0, 0, time.UTC)
+
+func newTestServer(buf *bytes.Buffer) *Server {
+	cfg := DefaultConfig()
+	cfg.Clock = func() time.Time { return testTime }
+	return New(cfg, &bufferLogger{buf: buf})
+}
+
+func TestHandleGreet(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		wantStatus     int
+		wantLanguage   string
+		wantMessage    string
+		wantCode       errors.ErrorCode
+	}{
+		{name: "default", target: "/v1/greet", wantStatus: http.StatusOK, wantLanguage: "en", wantMessage: "Hello, World!"},
+		{name: "name and lang", target: "/v1/greet?name=Ana&lang=es", wantStatus: http.StatusOK, wantLanguage: "es", wantMessage: "¡Buenas noches, Ana!"},
+		{
+			name:           "accept-language negotiation",
+			target:         "/v1/greet?name=Ana",
+			acceptLanguage: "gl, fr-CA;q=0.8, en;q=0.5",
+			wantStatus:     http.StatusOK,
+			wantLanguage:   "fr",
+			wantMessage:    "Bonsoir, Ana!",
+		},
+		{
+			name:           "lang overrides accept-language",
+			target:         "/v1/greet?lang=de",
+			acceptLanguage: "fr",
+			wantStatus:     http.StatusOK,
+			wantLanguage:   "de",
+		},
+		{name: "group", target: "/v1/greet?name=Ana&name=Bob&lang=de", wantStatus: http.StatusOK, wantLanguage: "de", wantMessage: "Hallo zusammen, Ana und Bob!"},
+		{name: "invalid gender", target: "/v1/greet?name=Ana&gender=robot", wantStatus: http.StatusBadRequest, wantCode: errors.CodeValidation},
+		{name: "emoji", target: "/v1/greet?emoji=true", wantStatus: http.StatusOK, wantLanguage: "en"},
+		{name: "invalid emoji", target: "/v1/greet?emoji=maybe", wantStatus: http.StatusBadRequest, wantCode: errors.CodeValidation},
+		{name: "invalid lang", target: "/v1/greet?lang=C", wantStatus: http.StatusBadRequest, wantCode: errors.CodeValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			handler := newTestServer(&logs).Handler()
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+
+			if tt.wantCode != "" {
+				var body errorBody
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("invalid error body: %v", err)
+				}
+				if body.Error.Code != tt.wantCode || body.Error.Message == "" {
+					t.Errorf("error = %+v, want code %s with a message", body.Error, tt.wantCode)
+				}
+				return
+			}
+
+			var got struct {
+				Message  string `json:"message"`
+				Language string `json:"language"`
+				Emoji    string `json:"emoji"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid greeting body: %v", err)
+			}
+			if got.Language != tt.wantLanguage {
+				t.Errorf("language = %q, want %q", got.Language, tt.wantLanguage)
+			}
+			if tt.wantMessage != "" && got.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if strings.Contains(tt.target, "emoji=true") && got.Emoji == "" {
+				t.Error("expected emoji in response")
+			}
+		})
+	}
+}
+
+func TestDefaultLanguage(t *testing.T) {
+	language := "de"
+	cfg := DefaultConfig()
+	cfg.DefaultLanguage = func() string { return language }
+	handler := New(cfg, &bufferLogger{buf: new(bytes.Buffer)}).Handler()
+
+	greet := func(acceptLanguage string) string {
+		req := httptest.NewRequest(http.MethodGet, "/v1/greet", nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header().Get("Content-Language")
+	}
+
+	if got := greet(""); got != "de" {
+		t.Errorf("language = %q, want the default de", got)
+	}
+	if got := greet("fr"); got != "fr" {
+		t.Errorf("language = %q, want fr from Accept-Language", got)
+	}
+
+	// The default is read for each request, so changes apply live
+	language = "es"
+	if got := greet(""); got != "es" {
+		t.Errorf("language after change = %q, want es", got)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		method     string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{method: http.MethodGet, target: "/v1/languages", wantStatus: http.StatusOK, wantBody: `"code":"ja"`},
+		{method: http.MethodGet, target: "/version", wantStatus: http.StatusOK, wantBody: `"goVersion"`},
+		{method: http.MethodGet, target: "/healthz", wantStatus: http.StatusOK, wantBody: `"ok"`},
+		{method: http.MethodGet, target: "/readyz", wantStatus: http.StatusServiceUnavailable, wantBody: `"RESOURCE_EXHAUSTED"`},
+		{method: http.MethodGet, target: "/nope", wantStatus: http.StatusNotFound, wantBody: `"NOT_FOUND"`},
+		{method: http.MethodPost, target: "/v1/greet", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			var logs bytes.Buffer
+			handler := newTestServer(&logs).Handler()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestLogLevelAdmin(t *testing.T) {
+	var logs bytes.Buffer
+	levels := logger.NewLevels(slog.LevelInfo, map[string]slog.Level{"cli": slog.LevelWarn})
+	cfg := DefaultConfig()
+	cfg.Levels = levels
+	srv := New(cfg, &bufferLogger{buf: &logs})
+	handler := srv.AdminHandler()
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "read", method: http.MethodGet, wantStatus: http.StatusOK, wantBody: `{"level":"info","components":{"cli":"warn"},"debug":false}`},
+		{
+			name:       "change",
+			method:     http.MethodPut,
+			body:       `{"level":"warn","components":{"cli":null,"greeting":"debug"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":"warn","components":{"greeting":"debug"},"debug":false}`,
+		},
+		{name: "toggle debug", method: http.MethodPut, body: `{"debug":true}`, wantStatus: http.StatusOK, wantBody: `"debug":true`},
+		{name: "unknown level", method: http.MethodPut, body: `{"components":{"cli":"loud"}}`, wantStatus: http.StatusBadRequest, wantBody: `"VALIDATION"`},
+		{name: "unknown field", method: http.MethodPut, body: `{"lvl":"warn"}`, wantStatus: http.StatusBadRequest, wantBody: `"VALIDATION"`},
+		{name: "wrong method", method: http.MethodPost, body: `{}`, wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/admin/log-level", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", rec.Body, tt.wantBody)
+			}
+		})
+	}
+
+	// A rejected change leaves every level alone
+	if levels.For("cli") != slog.LevelDebug || !levels.Debugging() || levels.Level() != slog.LevelWarn {
+		t.Errorf("levels = %s, debug %v", levels, levels.Debugging())
+	}
+
+	// The admin API is not served with the public one, and only with levels
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status of the admin route on the public handler = %d, want 404", rec.Code)
+	}
+	if newTestServer(&logs).AdminHandler() != nil {
+		t.Error("AdminHandler() without levels is not nil")
+	}
+	if addr := DefaultConfig().AdminAddr; !strings.HasPrefix(addr, "127.0.0.1:") {
+		t.Errorf("default admin address = %q, want loopback", addr)
+	}
+}
+
+func TestRequestLogging(t *testing.T) {
+	var logs bytes.Buffer
+	handler := newTestServer(&logs).Handler()
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	for _, want := range []string{"http request", "path /healthz", "status 200"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log output missing %s: %s", want, logs.String())
+		}
+	}
+}
+
+func TestServeGracefulShutdown(t *testing.T) {
+	var logs bytes.Buffer
+	srv := newTestServer(&logs)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln, nil) }()
+
+	url := "http://" + ln.Addr().String() + "/readyz"
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := http.Get(url) //nolint:gosec // test server address
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server never became ready: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Serve() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	if srv.ready.Load() {
+		t.Error("server still reports ready after shutdown")
+	}
+}
+
+func TestServeAdminListener(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Levels = logger.NewLevels(slog.LevelInfo, nil)
+	srv := New(cfg, &bufferLogger{buf: new(bytes.Buffer)})
+
+	var listeners [2]net.Listener
+	for i := range listeners {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		listeners[i] = ln
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, listeners[0], listeners[1]) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	}()
+
+	status := func(ln net.Listener) int {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/admin/log-level") //nolint:gosec // test server address
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got := status(listeners[0]); got != http.StatusNotFound {
+		t.Errorf("admin route on the public listener = %d, want 404", got)
+	}
+	if got := status(listeners[1]); got != http.StatusOK {
+		t.Errorf("admin route on the admin listener = %d, want 200", got)
+	}
+}
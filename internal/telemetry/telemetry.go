@@ -0,0 +1,257 @@
+// Package telemetry traces command invocations and counts them with
+// OpenTelemetry, exporting over OTLP or to stdout or a file
+package telemetry
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+)
+
+// Exporters
+const (
+	ExporterNone   = "none"   // Telemetry is off
+	ExporterOTLP   = "otlp"   // Send to an OpenTelemetry collector
+	ExporterStdout = "stdout" // Print spans and metrics as JSON on stdout
+	ExporterFile   = "file"   // Append spans and metrics as JSON to a file
+)
+
+// OTLP protocols
+const (
+	ProtocolHTTP = "http" // OTLP over HTTP with protobuf, port 4318
+	ProtocolGRPC = "grpc" // OTLP over gRPC, port 4317
+)
+
+// instrumentation names the tracer and meter of the CLI
+const instrumentation = "github.com/go-cli-template/hello-world-cli"
+
+// Attribute keys
+const (
+	CommandKey   = attribute.Key("cli.command")
+	ExitCodeKey  = attribute.Key("cli.exit_code")
+	ErrorCodeKey = attribute.Key("error.code")
+)
+
+// Config holds telemetry configuration
+type Config struct {
+	Exporter string // none, otlp, stdout, file
+	Endpoint string // OTLP host:port or URL; empty for OTEL_EXPORTER_OTLP_ENDPOINT or localhost
+	Protocol string // http or grpc
+	Insecure bool   // use plain HTTP or gRPC for a host:port endpoint
+	File     string // file written by the file exporter
+
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Setup installs tracer and meter providers for cfg as the global ones.
+// The returned function flushes and stops them; call it before exiting.
+// Export errors are logged to log.
+func Setup(ctx context.Context, cfg Config, log logger.Logger) (func(context.Context) error, error) {
+	if cfg.Exporter == "" || cfg.Exporter == ExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	spans, metrics, closer, err := newExporters(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.version", cfg.ServiceVersion),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to describe the telemetry resource")
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(spans), sdktrace.WithResource(res))
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metrics)), sdkmetric.WithResource(res))
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Warn("telemetry export failed", "error", err)
+	}))
+
+	return func(ctx context.Context) error {
+		errs := []error{tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx)}
+		if closer != nil {
+			errs = append(errs, closer.Close())
+		}
+		return stderrors.Join(errs...)
+	}, nil
+}
+
+// newExporters creates the span and metric exporters of cfg, and the file
+// they write to if they need closing
+func newExporters(ctx context.Context, cfg Config) (sdktrace.SpanExporter, sdkmetric.Exporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		spans, metrics, err := newOTLPExporters(ctx, cfg)
+		return spans, metrics, nil, err
+	case ExporterStdout:
+		spans, metrics, err := newWriterExporters(os.Stdout)
+		return spans, metrics, nil, err
+	case ExporterFile:
+		if cfg.File == "" {
+			return nil, nil, nil, errors.New(errors.CodeConfigInvalid, "the file telemetry exporter needs telemetry.file")
+		}
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, nil, nil, &errors.FileError{Path: cfg.File, Operation: "open", Err: err}
+		}
+		spans, metrics, err := newWriterExporters(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, nil, err
+		}
+		return spans, metrics, f, nil
+	default:
+		return nil, nil, nil, errors.New(errors.CodeConfigInvalid, "unknown telemetry exporter "+cfg.Exporter)
+	}
+}
+
+// newWriterExporters creates exporters writing JSON to w
+func newWriterExporters(w io.Writer) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	spans, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.CodeInternal, "failed to create the span exporter")
+	}
+	metrics, err := stdoutmetric.New(stdoutmetric.WithWriter(w))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.CodeInternal, "failed to create the metric exporter")
+	}
+	return spans, metrics, nil
+}
+
+// newOTLPExporters creates exporters sending to an OTLP endpoint. Options
+// left empty come from the standard OTEL_EXPORTER_OTLP_* variables.
+func newOTLPExporters(ctx context.Context, cfg Config) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	isURL := strings.Contains(cfg.Endpoint, "://")
+
+	var (
+		spans   sdktrace.SpanExporter
+		metrics sdkmetric.Exporter
+		err     error
+	)
+	switch cfg.Protocol {
+	case ProtocolGRPC:
+		var traceOpts []otlptracegrpc.Option
+		var metricOpts []otlpmetricgrpc.Option
+		switch {
+		case isURL:
+			traceOpts = append(traceOpts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+			metricOpts = append(metricOpts, otlpmetricgrpc.WithEndpointURL(cfg.Endpoint))
+		case cfg.Endpoint != "":
+			traceOpts = append(traceOpts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+			metricOpts = append(metricOpts, otlpmetricgrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
+			metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
+		}
+		if spans, err = otlptracegrpc.New(ctx, traceOpts...); err == nil {
+			metrics, err = otlpmetricgrpc.New(ctx, metricOpts...)
+		}
+	case ProtocolHTTP, "":
+		var traceOpts []otlptracehttp.Option
+		var metricOpts []otlpmetrichttp.Option
+		switch {
+		case isURL:
+			traceOpts = append(traceOpts, otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"))
+			metricOpts = append(metricOpts, otlpmetrichttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/metrics"))
+		case cfg.Endpoint != "":
+			traceOpts = append(traceOpts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+			metricOpts = append(metricOpts, otlpmetrichttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+			metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+		}
+		if spans, err = otlptracehttp.New(ctx, traceOpts...); err == nil {
+			metrics, err = otlpmetrichttp.New(ctx, metricOpts...)
+		}
+	default:
+		return nil, nil, errors.New(errors.CodeConfigInvalid, "unknown OTLP protocol "+cfg.Protocol)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.CodeConfigInvalid, "failed to create the OTLP exporters")
+	}
+	return spans, metrics, nil
+}
+
+// Tracer returns the tracer of the CLI
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// StartCommand starts the span of a command invocation, named after its
+// command path such as "hello-world-cli greet"
+func StartCommand(ctx context.Context, path string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts, trace.WithAttributes(CommandKey.String(path)))
+	return Tracer().Start(ctx, path, opts...)
+}
+
+// EndCommand ends the span of a command invocation started with
+// StartCommand, recording err, and counts the invocation by command and
+// exit code
+func EndCommand(ctx context.Context, path string, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	exitCode := int(errors.GetExitCode(err))
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(ExitCodeKey.Int(exitCode))
+	RecordError(span, err)
+	span.End()
+
+	counter, counterErr := otel.Meter(instrumentation).Int64Counter("cli.command.invocations",
+		metric.WithDescription("Command invocations by command and exit code"),
+		metric.WithUnit("{invocation}"))
+	if counterErr != nil {
+		otel.Handle(counterErr)
+		return
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(CommandKey.String(path), ExitCodeKey.Int(exitCode)))
+}
+
+// RecordError records err on span with its ErrorCode and marks the span
+// as failed. A nil error is ignored.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	code := ErrorCodeKey.String(string(errors.GetCode(err)))
+	span.RecordError(err, trace.WithAttributes(code))
+	span.SetAttributes(code)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// RecordSpan records a span for work that already happened, from start to
+// end, such as the steps run before telemetry was set up
+func RecordSpan(ctx context.Context, name string, start, end time.Time) {
+	_, span := Tracer().Start(ctx, name, trace.WithTimestamp(start))
+	span.End(trace.WithTimestamp(end))
+}
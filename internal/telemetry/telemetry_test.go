@@ -0,0 +1,156 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-cli-template/hello-world-cli/internal/errors"
+	"github.com/go-cli-template/hello-world-cli/internal/logger"
+)
+
+// record installs providers recording spans and metrics in memory
+func record(t *testing.T) (*tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	tracerProvider := otel.GetTracerProvider()
+	meterProvider := otel.GetMeterProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tracerProvider)
+		otel.SetMeterProvider(meterProvider)
+	})
+	return spans, reader
+}
+
+func TestCommandSpans(t *testing.T) {
+	spans, reader := record(t)
+
+	start := time.Now().Add(-time.Second)
+	ctx, _ := StartCommand(context.Background(), "app greet", trace.WithTimestamp(start))
+	RecordSpan(ctx, "config.load", start, start.Add(time.Millisecond))
+	EndCommand(ctx, "app greet", &errors.ValidationError{Field: "name", Message: "name is required"})
+
+	ctx, _ = StartCommand(context.Background(), "app greet")
+	EndCommand(ctx, "app greet", nil)
+
+	ended := spans.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("ended %d spans, want 3", len(ended))
+	}
+	child, failed, succeeded := ended[0], ended[1], ended[2]
+	if child.Name() != "config.load" || child.Parent().SpanID() != failed.SpanContext().SpanID() {
+		t.Errorf("child span %s has parent %s, want config.load within the command span", child.Name(), child.Parent().SpanID())
+	}
+	if !failed.StartTime().Equal(start) {
+		t.Errorf("command span started at %v, want %v", failed.StartTime(), start)
+	}
+	if failed.Status().Code != codes.Error {
+		t.Errorf("status = %v, want an error", failed.Status())
+	}
+	wantAttrs := map[attribute.Key]string{CommandKey: "app greet", ExitCodeKey: "65", ErrorCodeKey: "VALIDATION"}
+	for _, kv := range failed.Attributes() {
+		if want, ok := wantAttrs[kv.Key]; ok && kv.Value.Emit() != want {
+			t.Errorf("attribute %s = %s, want %s", kv.Key, kv.Value.Emit(), want)
+		}
+		delete(wantAttrs, kv.Key)
+	}
+	if len(wantAttrs) > 0 {
+		t.Errorf("missing attributes %v", wantAttrs)
+	}
+	if len(failed.Events()) != 1 || failed.Events()[0].Name != "exception" {
+		t.Errorf("events = %v, want the recorded error", failed.Events())
+	}
+	if succeeded.Status().Code == codes.Error {
+		t.Errorf("successful command has status %v", succeeded.Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if m.Name != "cli.command.invocations" || !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				code, _ := dp.Attributes.Value(ExitCodeKey)
+				counts[code.Emit()] += dp.Value
+			}
+		}
+	}
+	if counts["0"] != 1 || counts["65"] != 1 {
+		t.Errorf("invocations by exit code = %v, want one each of 0 and 65", counts)
+	}
+}
+
+func TestSetupFile(t *testing.T) {
+	tracerProvider, meterProvider := otel.GetTracerProvider(), otel.GetMeterProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tracerProvider)
+		otel.SetMeterProvider(meterProvider)
+	})
+
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterFile, File: path, ServiceName: "app"}, logger.Default())
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	ctx, _ := StartCommand(context.Background(), "app greet")
+	EndCommand(ctx, "app greet", nil)
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"Name":"app greet"`, `"cli.command.invocations"`, `"service.name"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("telemetry file is missing %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestSetupErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "file without a path", cfg: Config{Exporter: ExporterFile}},
+		{name: "file that cannot be opened", cfg: Config{Exporter: ExporterFile, File: filepath.Join(t.TempDir(), "missing", "telemetry.json")}},
+		{name: "unknown exporter", cfg: Config{Exporter: "zipkin"}},
+		{name: "unknown protocol", cfg: Config{Exporter: ExporterOTLP, Protocol: "udp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Setup(context.Background(), tt.cfg, logger.Default()); err == nil {
+				t.Error("Setup() succeeded, want an error")
+			}
+		})
+	}
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone}, logger.Default())
+	if err != nil || shutdown(context.Background()) != nil {
+		t.Errorf("Setup() with no exporter error = %v", err)
+	}
+}